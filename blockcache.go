@@ -0,0 +1,417 @@
+package sdk
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	gosync "sync"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync/syncconnect"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultBlockCacheSize = 1024
+	// defaultRollbackWindow is the Cardano mainnet security parameter k.
+	defaultRollbackWindow uint64 = 2160
+)
+
+// BlockCacheOption configures a [BlockCache] during [NewBlockCache].
+type BlockCacheOption func(*BlockCache)
+
+// WithBlockCacheSize sets how many blocks are kept in memory. Values below 1
+// are ignored.
+func WithBlockCacheSize(size int) BlockCacheOption {
+	return func(c *BlockCache) {
+		if size > 0 {
+			c.size = size
+		}
+	}
+}
+
+// WithBlockCacheDir enables the on-disk tier. Cached blocks are written to
+// dir as soon as the tip buries them deeper than the rollback window, and
+// are read back on later lookups once they have left the in-memory tier.
+// Blocks evicted from memory before they are buried are held back until
+// they can be written, so up to a rollback window of blocks may stay in
+// memory beyond the cache size. The directory is created if needed.
+func WithBlockCacheDir(dir string) BlockCacheOption {
+	return func(c *BlockCache) {
+		c.dir = dir
+	}
+}
+
+// WithRollbackWindow sets how many blocks below the tip may still be rolled
+// back. Only blocks deeper than this are persisted to disk. The default is
+// 2160, the Cardano mainnet security parameter.
+func WithRollbackWindow(blocks uint64) BlockCacheOption {
+	return func(c *BlockCache) {
+		c.window = blocks
+	}
+}
+
+// BlockCache caches blocks by hash for FetchBlock. Recent blocks live in a
+// bounded in-memory LRU; blocks older than the rollback window can also be
+// persisted to a directory (see [WithBlockCacheDir]).
+//
+// Install it on a client with [WithBlockCache]. The cache then serves
+// Sync.FetchBlock calls from memory or disk when every requested ref is
+// present (including calls made by higher-level helpers such as the
+// cardano package's ReadBlock), stores fetched blocks, and watches
+// FollowTip streams opened through the same client: applied blocks advance
+// the tip used to decide immutability, and undone blocks are evicted.
+//
+// The tip is the highest of the heights reported by FollowTip, the heights
+// of blocks stored in the cache, and [BlockCache.SetTipHeight]. A client
+// that only fetches blocks should call SetTipHeight (for example from
+// ReadTip) for older blocks to reach the disk tier.
+//
+// A BlockCache is safe for concurrent use.
+type BlockCache struct {
+	mu        gosync.Mutex
+	size      int
+	dir       string
+	window    uint64
+	tipHeight uint64
+	entries   map[string]*list.Element
+	order     *list.List
+	// pending holds the blocks not yet written to the disk tier, lowest
+	// first, including those already evicted from memory.
+	pending     pendingBlocks
+	pendingKeys map[string]*pendingBlock
+}
+
+// NewBlockCache returns an empty [BlockCache].
+func NewBlockCache(options ...BlockCacheOption) *BlockCache {
+	c := &BlockCache{
+		size:        defaultBlockCacheSize,
+		window:      defaultRollbackWindow,
+		entries:     make(map[string]*list.Element),
+		order:       list.New(),
+		pendingKeys: make(map[string]*pendingBlock),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// WithBlockCache installs cache on the client as a Connect interceptor. See
+// [BlockCache] for the calls it affects.
+func WithBlockCache(cache *BlockCache) ClientOption {
+	return WithConnectOptions(connect.WithInterceptors(cache.Interceptor()))
+}
+
+// Get returns a copy of the cached block with the given hash, looking in
+// memory first and then on disk.
+func (c *BlockCache) Get(hash []byte) (*sync.AnyChainBlock, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.get(hex.EncodeToString(hash))
+}
+
+// Put stores a copy of block, keyed by its header hash. Blocks without a
+// parsed header are ignored. The block's height advances the tip.
+func (c *BlockCache) Put(block *sync.AnyChainBlock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(block)
+}
+
+// Invalidate removes the block with the given hash from memory and disk.
+func (c *BlockCache) Invalidate(hash []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(hex.EncodeToString(hash))
+}
+
+// SetTipHeight records the current chain height, which decides which
+// cached blocks are beyond the rollback window.
+func (c *BlockCache) SetTipHeight(height uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setTip(height)
+}
+
+// Observe updates the cache from a FollowTip event: applied blocks are
+// stored and advance the tip, undone blocks are invalidated. Streams opened
+// through a client configured with [WithBlockCache] call it automatically.
+func (c *BlockCache) Observe(resp *sync.FollowTipResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setTip(resp.GetTip().GetHeight())
+	switch action := resp.GetAction().(type) {
+	case *sync.FollowTipResponse_Apply:
+		c.put(action.Apply)
+	case *sync.FollowTipResponse_Undo:
+		if ref := BlockRefOf(action.Undo); ref != nil {
+			c.invalidate(hex.EncodeToString(ref.GetHash()))
+		}
+	}
+}
+
+// Interceptor returns the Connect interceptor that routes FetchBlock and
+// FollowTip through the cache. [WithBlockCache] installs it for you.
+func (c *BlockCache) Interceptor() connect.Interceptor {
+	return &blockCacheInterceptor{cache: c}
+}
+
+func (c *BlockCache) get(key string) (*sync.AnyChainBlock, bool) {
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		entry := elem.Value.(*blockCacheEntry)
+		return proto.Clone(entry.block).(*sync.AnyChainBlock), true
+	}
+	if pending, ok := c.pendingKeys[key]; ok {
+		return proto.Clone(pending.block).(*sync.AnyChainBlock), true
+	}
+	if c.dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	block := &sync.AnyChainBlock{}
+	if err := proto.Unmarshal(data, block); err != nil {
+		return nil, false
+	}
+	return block, true
+}
+
+func (c *BlockCache) put(block *sync.AnyChainBlock) {
+	ref := BlockRefOf(block)
+	if ref == nil {
+		return
+	}
+	key := hex.EncodeToString(ref.GetHash())
+	entry := &blockCacheEntry{
+		key:   key,
+		block: proto.Clone(block).(*sync.AnyChainBlock),
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[key] = c.order.PushFront(entry)
+	}
+	for c.order.Len() > c.size {
+		evicted := c.order.Remove(c.order.Back()).(*blockCacheEntry)
+		delete(c.entries, evicted.key)
+	}
+	if c.dir != "" {
+		c.queue(key, ref.GetHeight(), entry.block)
+	}
+	// A block proves the chain has reached at least its height.
+	c.setTip(ref.GetHeight())
+	c.persistImmutable()
+}
+
+// setTip raises the tip to height and persists the pending blocks it
+// buries beyond the rollback window.
+func (c *BlockCache) setTip(height uint64) {
+	if height <= c.tipHeight {
+		return
+	}
+	c.tipHeight = height
+	c.persistImmutable()
+}
+
+func (c *BlockCache) invalidate(key string) {
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+	if pending, ok := c.pendingKeys[key]; ok {
+		heap.Remove(&c.pending, pending.index)
+		delete(c.pendingKeys, key)
+	}
+	if c.dir != "" {
+		_ = os.Remove(c.path(key))
+	}
+}
+
+// queue holds block back for the disk tier until the tip buries it.
+func (c *BlockCache) queue(key string, height uint64, block *sync.AnyChainBlock) {
+	if pending, ok := c.pendingKeys[key]; ok {
+		pending.height, pending.block = height, block
+		heap.Fix(&c.pending, pending.index)
+		return
+	}
+	pending := &pendingBlock{key: key, height: height, block: block}
+	heap.Push(&c.pending, pending)
+	c.pendingKeys[key] = pending
+}
+
+// persistImmutable writes and releases the pending blocks buried beyond the
+// rollback window.
+func (c *BlockCache) persistImmutable() {
+	if c.tipHeight < c.window {
+		return
+	}
+	for len(c.pending) > 0 && c.pending[0].height <= c.tipHeight-c.window {
+		pending := heap.Pop(&c.pending).(*pendingBlock)
+		delete(c.pendingKeys, pending.key)
+		c.persist(pending.key, pending.block)
+	}
+}
+
+// persist writes block to the disk tier. Failures are ignored; the block
+// is simply fetched from the server again.
+func (c *BlockCache) persist(key string, block *sync.AnyChainBlock) {
+	if c.dir == "" {
+		return
+	}
+	data, err := proto.Marshal(block)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path(key), data, 0o600)
+}
+
+func (c *BlockCache) path(key string) string {
+	return filepath.Join(c.dir, key+".block")
+}
+
+// blockCacheEntry is a block held in the in-memory tier.
+type blockCacheEntry struct {
+	key   string
+	block *sync.AnyChainBlock
+}
+
+// pendingBlock is a block waiting to be buried deep enough for the disk
+// tier.
+type pendingBlock struct {
+	key    string
+	height uint64
+	block  *sync.AnyChainBlock
+	index  int
+}
+
+// pendingBlocks is a [heap.Interface] of pending blocks ordered by height.
+type pendingBlocks []*pendingBlock
+
+func (p pendingBlocks) Len() int { return len(p) }
+
+func (p pendingBlocks) Less(i, j int) bool { return p[i].height < p[j].height }
+
+func (p pendingBlocks) Swap(i, j int) {
+	p[i], p[j] = p[j], p[i]
+	p[i].index = i
+	p[j].index = j
+}
+
+func (p *pendingBlocks) Push(x any) {
+	pending := x.(*pendingBlock)
+	pending.index = len(*p)
+	*p = append(*p, pending)
+}
+
+func (p *pendingBlocks) Pop() any {
+	old := *p
+	pending := old[len(old)-1]
+	old[len(old)-1] = nil
+	*p = old[:len(old)-1]
+	return pending
+}
+
+type blockCacheInterceptor struct {
+	cache *BlockCache
+}
+
+func (i *blockCacheInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(
+		ctx context.Context,
+		req connect.AnyRequest,
+	) (connect.AnyResponse, error) {
+		if req.Spec().Procedure != syncconnect.SyncServiceFetchBlockProcedure {
+			return next(ctx, req)
+		}
+		fetchReq, ok := req.Any().(*sync.FetchBlockRequest)
+		// Field-masked responses are partial; never serve or store them.
+		if !ok || len(fetchReq.GetFieldMask().GetPaths()) > 0 {
+			return next(ctx, req)
+		}
+		if blocks, ok := i.lookup(fetchReq.GetRef()); ok {
+			return connect.NewResponse(&sync.FetchBlockResponse{
+				Block: blocks,
+			}), nil
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if fetchResp, ok := resp.Any().(*sync.FetchBlockResponse); ok {
+			for _, block := range fetchResp.GetBlock() {
+				i.cache.Put(block)
+			}
+		}
+		return resp, nil
+	}
+}
+
+func (i *blockCacheInterceptor) WrapStreamingClient(
+	next connect.StreamingClientFunc,
+) connect.StreamingClientFunc {
+	return func(
+		ctx context.Context,
+		spec connect.Spec,
+	) connect.StreamingClientConn {
+		conn := next(ctx, spec)
+		if spec.Procedure != syncconnect.SyncServiceFollowTipProcedure {
+			return conn
+		}
+		return &observingClientConn{StreamingClientConn: conn, cache: i.cache}
+	}
+}
+
+func (*blockCacheInterceptor) WrapStreamingHandler(
+	next connect.StreamingHandlerFunc,
+) connect.StreamingHandlerFunc {
+	return next
+}
+
+// lookup returns the blocks for refs only if every ref is cached by hash.
+func (i *blockCacheInterceptor) lookup(
+	refs []*sync.BlockRef,
+) ([]*sync.AnyChainBlock, bool) {
+	if len(refs) == 0 {
+		return nil, false
+	}
+	blocks := make([]*sync.AnyChainBlock, 0, len(refs))
+	for _, ref := range refs {
+		if len(ref.GetHash()) == 0 {
+			return nil, false
+		}
+		block, ok := i.cache.Get(ref.GetHash())
+		if !ok {
+			return nil, false
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, true
+}
+
+type observingClientConn struct {
+	connect.StreamingClientConn
+	cache *BlockCache
+}
+
+func (c *observingClientConn) Receive(msg any) error {
+	if err := c.StreamingClientConn.Receive(msg); err != nil {
+		return err
+	}
+	if resp, ok := msg.(*sync.FollowTipResponse); ok {
+		c.cache.Observe(resp)
+	}
+	return nil
+}
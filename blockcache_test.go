@@ -0,0 +1,204 @@
+package sdk
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync/syncconnect"
+)
+
+func TestBlockCacheServesFetchBlockAndInvalidatesOnUndo(t *testing.T) {
+	fakeSync := &blockSyncHandler{
+		scriptedSyncHandler: scriptedSyncHandler{
+			responses: []*sync.FollowTipResponse{undoResponse(5, 0x05)},
+		},
+	}
+	path, handler := syncconnect.NewSyncServiceHandler(fakeSync)
+	server := newTestServerClient(t, path, handler)
+	cache := NewBlockCache()
+	client := NewClient(WithBaseUrl(server.URL()), WithBlockCache(cache))
+
+	fetch := func() {
+		t.Helper()
+		resp, err := client.FetchBlockWithContext(
+			context.Background(),
+			connect.NewRequest(&sync.FetchBlockRequest{
+				Ref: []*sync.BlockRef{{Slot: 5, Hash: []byte{0x05}}},
+			}),
+		)
+		if err != nil {
+			t.Fatalf("FetchBlockWithContext returned error: %v", err)
+		}
+		if got := BlockRefOf(resp.Msg.GetBlock()[0]).GetSlot(); got != 5 {
+			t.Fatalf("fetched block slot = %d, want 5", got)
+		}
+	}
+
+	fetch()
+	fetch()
+	if fakeSync.fetches != 1 {
+		t.Fatalf("server fetches = %d, want 1", fakeSync.fetches)
+	}
+
+	stream, err := client.FollowTip(connect.NewRequest(&sync.FollowTipRequest{}))
+	if err != nil {
+		t.Fatalf("FollowTip returned error: %v", err)
+	}
+	for stream.Receive() {
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if _, ok := cache.Get([]byte{0x05}); ok {
+		t.Fatal("undone block is still cached")
+	}
+
+	fetch()
+	if fakeSync.fetches != 2 {
+		t.Fatalf("server fetches after undo = %d, want 2", fakeSync.fetches)
+	}
+}
+
+func TestBlockCachePersistsImmutableBlocks(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewBlockCache(
+		WithBlockCacheSize(1),
+		WithBlockCacheDir(dir),
+		WithRollbackWindow(10),
+	)
+	cache.SetTipHeight(100)
+
+	cache.Put(testBlock(50, 0x32))
+	cache.Put(testBlock(95, 0x5f))
+	cache.Put(testBlock(96, 0x60))
+
+	if _, ok := cache.Get([]byte{0x32}); !ok {
+		t.Fatal("immutable block was not persisted to disk")
+	}
+
+	reopened := NewBlockCache(WithBlockCacheDir(dir))
+	if _, ok := reopened.Get([]byte{0x32}); !ok {
+		t.Fatal("persisted block not found by a new cache")
+	}
+	if _, ok := reopened.Get([]byte{0x5f}); ok {
+		t.Fatal("volatile block was persisted to disk")
+	}
+}
+
+func TestBlockCachePersistsWhenTipAdvances(t *testing.T) {
+	fakeSync := &blockSyncHandler{}
+	path, handler := syncconnect.NewSyncServiceHandler(fakeSync)
+	server := newTestServerClient(t, path, handler)
+	dir := t.TempDir()
+	cache := NewBlockCache(WithBlockCacheDir(dir), WithRollbackWindow(10))
+	client := NewClient(WithBaseUrl(server.URL()), WithBlockCache(cache))
+
+	// Only FetchBlock is used: fetched blocks advance the tip, and blocks
+	// still in memory are persisted once buried, without being evicted.
+	for _, slot := range []uint64{20, 40} {
+		_, err := client.FetchBlockWithContext(
+			context.Background(),
+			connect.NewRequest(&sync.FetchBlockRequest{
+				Ref: []*sync.BlockRef{{Slot: slot, Hash: []byte{byte(slot)}}},
+			}),
+		)
+		if err != nil {
+			t.Fatalf("FetchBlockWithContext returned error: %v", err)
+		}
+	}
+
+	reopened := NewBlockCache(WithBlockCacheDir(dir))
+	if _, ok := reopened.Get([]byte{20}); !ok {
+		t.Fatal("buried block was not persisted to disk")
+	}
+	if _, ok := reopened.Get([]byte{40}); ok {
+		t.Fatal("volatile block was persisted to disk")
+	}
+}
+
+func TestBlockCachePersistsBlocksEvictedBeforeBurial(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewBlockCache(WithBlockCacheDir(dir))
+
+	// Follow more blocks than the default size holds in memory: each is
+	// evicted long before the default window buries it.
+	const blocks = defaultBlockCacheSize + defaultRollbackWindow + 100
+	for height := uint64(1); height <= blocks; height++ {
+		cache.Observe(&sync.FollowTipResponse{
+			Action: &sync.FollowTipResponse_Apply{Apply: heightBlock(height)},
+			Tip:    &sync.BlockRef{Height: height},
+		})
+	}
+
+	reopened := NewBlockCache(WithBlockCacheDir(dir))
+	for _, height := range []uint64{1, 50, blocks - defaultRollbackWindow} {
+		if _, ok := reopened.Get(heightHash(height)); !ok {
+			t.Fatalf("buried block at height %d was not persisted to disk", height)
+		}
+	}
+	volatile := heightHash(blocks - defaultRollbackWindow + 1)
+	if _, ok := reopened.Get(volatile); ok {
+		t.Fatal("volatile block was persisted to disk")
+	}
+	if _, ok := cache.Get(volatile); !ok {
+		t.Fatal("evicted volatile block is no longer served")
+	}
+
+	// Undoing a pending block drops it before it reaches the disk.
+	cache.Observe(&sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Undo{Undo: heightBlock(blocks)},
+	})
+	cache.SetTipHeight(blocks + defaultRollbackWindow)
+	if _, ok := reopened.Get(heightHash(blocks)); ok {
+		t.Fatal("undone block was persisted to disk")
+	}
+	if _, ok := reopened.Get(heightHash(blocks - 1)); !ok {
+		t.Fatal("buried block was not persisted once the tip advanced")
+	}
+}
+
+func TestBlockCacheStoresCopies(t *testing.T) {
+	cache := NewBlockCache()
+	block := testBlock(7, 0x07)
+	cache.Put(block)
+	block.GetCardano().GetHeader().Slot = 8
+
+	cached, ok := cache.Get([]byte{0x07})
+	if !ok {
+		t.Fatal("block not cached")
+	}
+	if got := BlockRefOf(cached).GetSlot(); got != 7 {
+		t.Fatalf("cached block slot = %d, want 7", got)
+	}
+}
+
+// heightHash returns a distinct block hash for height.
+func heightHash(height uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, height)
+}
+
+func heightBlock(height uint64) *sync.AnyChainBlock {
+	block := testBlock(height, 0)
+	block.GetCardano().GetHeader().Hash = heightHash(height)
+	return block
+}
+
+type blockSyncHandler struct {
+	scriptedSyncHandler
+	fetches int
+}
+
+func (s *blockSyncHandler) FetchBlock(
+	_ context.Context,
+	req *connect.Request[sync.FetchBlockRequest],
+) (*connect.Response[sync.FetchBlockResponse], error) {
+	s.fetches++
+	blocks := make([]*sync.AnyChainBlock, 0, len(req.Msg.GetRef()))
+	for _, ref := range req.Msg.GetRef() {
+		blocks = append(blocks, testBlock(ref.GetSlot(), ref.GetHash()[0]))
+	}
+	return connect.NewResponse(&sync.FetchBlockResponse{Block: blocks}), nil
+}
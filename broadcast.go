@@ -0,0 +1,264 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	gosync "sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+)
+
+// ErrQuorumNotReached is returned by [BroadcastWithContext] when every
+// endpoint has answered and fewer than the quorum accepted the transaction.
+var ErrQuorumNotReached = errors.New("broadcast quorum not reached")
+
+// BroadcastStatus is the outcome of submitting to one endpoint.
+type BroadcastStatus int
+
+const (
+	// BroadcastPending: the endpoint has not answered yet.
+	BroadcastPending BroadcastStatus = iota
+	// BroadcastAccepted: SubmitTx succeeded.
+	BroadcastAccepted
+	// BroadcastRejected: the endpoint judged the transaction invalid
+	// (InvalidArgument or FailedPrecondition).
+	BroadcastRejected
+	// BroadcastTimeout: the endpoint did not answer before the deadline.
+	BroadcastTimeout
+	// BroadcastFailed: any other error, such as an unreachable endpoint.
+	BroadcastFailed
+)
+
+func (s BroadcastStatus) String() string {
+	switch s {
+	case BroadcastPending:
+		return "pending"
+	case BroadcastAccepted:
+		return "accepted"
+	case BroadcastRejected:
+		return "rejected"
+	case BroadcastTimeout:
+		return "timeout"
+	case BroadcastFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("BroadcastStatus(%d)", int(s))
+	}
+}
+
+// BroadcastOutcome is what one endpoint answered.
+type BroadcastOutcome struct {
+	// Endpoint is the client's base URL.
+	Endpoint string
+	Status   BroadcastStatus
+	// Ref is the transaction reference returned on acceptance.
+	Ref []byte
+	// Err is the submission error for any status but accepted and pending.
+	Err     error
+	Latency time.Duration
+}
+
+// BroadcastOption configures [BroadcastWithContext].
+type BroadcastOption func(*broadcastConfig)
+
+type broadcastConfig struct {
+	quorum  int
+	timeout time.Duration
+}
+
+// WithBroadcastQuorum sets how many endpoints must accept before the
+// broadcast returns. Values below 1 are ignored; the default is 1. A
+// quorum larger than the number of endpoints can never be reached.
+func WithBroadcastQuorum(n int) BroadcastOption {
+	return func(c *broadcastConfig) {
+		if n > 0 {
+			c.quorum = n
+		}
+	}
+}
+
+// WithBroadcastTimeout bounds each endpoint's SubmitTx call. By default
+// only the caller's context applies.
+func WithBroadcastTimeout(timeout time.Duration) BroadcastOption {
+	return func(c *broadcastConfig) {
+		c.timeout = timeout
+	}
+}
+
+// BroadcastResult collects the per-endpoint outcomes of a broadcast. It is
+// returned as soon as the quorum is reached; endpoints still pending at
+// that point keep running and can be awaited with [BroadcastResult.Wait].
+type BroadcastResult struct {
+	mu       gosync.Mutex
+	outcomes []BroadcastOutcome
+	done     chan struct{}
+}
+
+// Outcomes returns a snapshot of the outcomes, in client order.
+func (r *BroadcastResult) Outcomes() []BroadcastOutcome {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]BroadcastOutcome(nil), r.outcomes...)
+}
+
+// Wait blocks until every endpoint has answered or ctx is done, then
+// returns the outcomes.
+func (r *BroadcastResult) Wait(ctx context.Context) []BroadcastOutcome {
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+	}
+	return r.Outcomes()
+}
+
+// Accepted returns how many endpoints have accepted the transaction.
+func (r *BroadcastResult) Accepted() int {
+	return r.count(BroadcastAccepted)
+}
+
+// Conflicting reports whether the endpoints disagree so far: one rejected
+// the transaction while another accepted it, or two accepted it under
+// different references.
+func (r *BroadcastResult) Conflicting() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ref []byte
+	accepted, rejected := false, false
+	for _, outcome := range r.outcomes {
+		switch outcome.Status {
+		case BroadcastAccepted:
+			if accepted && string(outcome.Ref) != string(ref) {
+				return true
+			}
+			accepted, ref = true, outcome.Ref
+		case BroadcastRejected:
+			rejected = true
+		case BroadcastPending, BroadcastTimeout, BroadcastFailed:
+			// No verdict on the transaction itself.
+		}
+	}
+	return accepted && rejected
+}
+
+func (r *BroadcastResult) count(status BroadcastStatus) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, outcome := range r.outcomes {
+		if outcome.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Broadcast calls [BroadcastWithContext] with a background context.
+func Broadcast(
+	clients []*UtxorpcClient,
+	tx []byte,
+	options ...BroadcastOption,
+) (*BroadcastResult, error) {
+	return BroadcastWithContext(context.Background(), clients, tx, options...)
+}
+
+// BroadcastWithContext submits the raw transaction tx through every client
+// concurrently via Submit.SubmitTx. It returns once the quorum of
+// endpoints has accepted (see [WithBroadcastQuorum]), or once every
+// endpoint has answered without reaching it, in which case the error wraps
+// [ErrQuorumNotReached]. If ctx ends first, the error is ctx's.
+//
+// Submissions still pending on return keep using ctx, so cancelling it
+// after the call abandons them. Check [BroadcastResult.Conflicting] to
+// detect endpoints that disagree about the transaction's validity.
+func BroadcastWithContext(
+	ctx context.Context,
+	clients []*UtxorpcClient,
+	tx []byte,
+	options ...BroadcastOption,
+) (*BroadcastResult, error) {
+	cfg := broadcastConfig{quorum: 1}
+	for _, option := range options {
+		option(&cfg)
+	}
+	result := &BroadcastResult{
+		outcomes: make([]BroadcastOutcome, len(clients)),
+		done:     make(chan struct{}),
+	}
+	answers := make(chan BroadcastStatus, len(clients))
+	var wg gosync.WaitGroup
+	for i, client := range clients {
+		result.outcomes[i].Endpoint = client.URL()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outcome := submitTo(ctx, client, tx, cfg.timeout)
+			result.mu.Lock()
+			result.outcomes[i] = outcome
+			result.mu.Unlock()
+			answers <- outcome.Status
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(result.done)
+	}()
+
+	accepted := 0
+	for range clients {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case status := <-answers:
+			if status == BroadcastAccepted {
+				accepted++
+			}
+			if accepted >= cfg.quorum {
+				return result, nil
+			}
+		}
+	}
+	return result, fmt.Errorf(
+		"%w: %d of %d endpoints accepted, need %d",
+		ErrQuorumNotReached,
+		accepted,
+		len(clients),
+		cfg.quorum,
+	)
+}
+
+func submitTo(
+	ctx context.Context,
+	client *UtxorpcClient,
+	tx []byte,
+	timeout time.Duration,
+) BroadcastOutcome {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	resp, err := client.SubmitTxWithContext(ctx, connect.NewRequest(&submit.SubmitTxRequest{
+		Tx: &submit.AnyChainTx{Type: &submit.AnyChainTx_Raw{Raw: tx}},
+	}))
+	outcome := BroadcastOutcome{
+		Endpoint: client.URL(),
+		Latency:  time.Since(start),
+		Err:      err,
+	}
+	switch code := connect.CodeOf(err); {
+	case err == nil:
+		outcome.Status = BroadcastAccepted
+		outcome.Ref = resp.Msg.GetRef()
+	case code == connect.CodeInvalidArgument || code == connect.CodeFailedPrecondition:
+		outcome.Status = BroadcastRejected
+	case code == connect.CodeDeadlineExceeded || errors.Is(err, context.DeadlineExceeded):
+		outcome.Status = BroadcastTimeout
+	default:
+		outcome.Status = BroadcastFailed
+	}
+	return outcome
+}
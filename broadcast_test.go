@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+)
+
+// broadcastSubmitHandler answers SubmitTx with ref, or with err if set,
+// after delay.
+type broadcastSubmitHandler struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+	ref   []byte
+	err   error
+	delay time.Duration
+}
+
+func (s *broadcastSubmitHandler) SubmitTx(
+	ctx context.Context,
+	_ *connect.Request[submit.SubmitTxRequest],
+) (*connect.Response[submit.SubmitTxResponse], error) {
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return connect.NewResponse(&submit.SubmitTxResponse{Ref: s.ref}), nil
+}
+
+func newBroadcastClients(t *testing.T, handlers ...*broadcastSubmitHandler) []*UtxorpcClient {
+	t.Helper()
+	clients := make([]*UtxorpcClient, len(handlers))
+	for i, handler := range handlers {
+		path, h := submitconnect.NewSubmitServiceHandler(handler)
+		clients[i] = newTestServerClient(t, path, h)
+	}
+	return clients
+}
+
+func TestBroadcastReturnsAtQuorum(t *testing.T) {
+	clients := newBroadcastClients(
+		t,
+		&broadcastSubmitHandler{ref: []byte("tx")},
+		&broadcastSubmitHandler{ref: []byte("tx")},
+		&broadcastSubmitHandler{ref: []byte("tx"), delay: 500 * time.Millisecond},
+	)
+
+	result, err := Broadcast(clients, []byte{0x84}, WithBroadcastQuorum(2))
+	if err != nil {
+		t.Fatalf("Broadcast returned error: %v", err)
+	}
+	if got := result.Accepted(); got != 2 {
+		t.Fatalf("Accepted = %d at return, want 2", got)
+	}
+	if status := result.Outcomes()[2].Status; status != BroadcastPending {
+		t.Fatalf("slow endpoint status = %v at return, want pending", status)
+	}
+
+	outcomes := result.Wait(context.Background())
+	for i, outcome := range outcomes {
+		if outcome.Status != BroadcastAccepted || string(outcome.Ref) != "tx" {
+			t.Fatalf("outcome %d = %+v, want accepted with ref tx", i, outcome)
+		}
+		if outcome.Endpoint != clients[i].URL() {
+			t.Fatalf("outcome %d endpoint = %q, want %q", i, outcome.Endpoint, clients[i].URL())
+		}
+	}
+	if result.Conflicting() {
+		t.Fatal("Conflicting = true for unanimous endpoints")
+	}
+}
+
+func TestBroadcastClassifiesOutcomes(t *testing.T) {
+	clients := newBroadcastClients(
+		t,
+		&broadcastSubmitHandler{ref: []byte("tx")},
+		&broadcastSubmitHandler{
+			err: connect.NewError(connect.CodeInvalidArgument, errors.New("bad input")),
+		},
+		&broadcastSubmitHandler{delay: time.Second},
+		&broadcastSubmitHandler{
+			err: connect.NewError(connect.CodeUnavailable, errors.New("node syncing")),
+		},
+	)
+
+	result, err := Broadcast(
+		clients,
+		[]byte{0x84},
+		WithBroadcastQuorum(2),
+		WithBroadcastTimeout(100*time.Millisecond),
+	)
+	if !errors.Is(err, ErrQuorumNotReached) {
+		t.Fatalf("Broadcast error = %v, want ErrQuorumNotReached", err)
+	}
+	want := []BroadcastStatus{
+		BroadcastAccepted,
+		BroadcastRejected,
+		BroadcastTimeout,
+		BroadcastFailed,
+	}
+	for i, outcome := range result.Outcomes() {
+		if outcome.Status != want[i] {
+			t.Fatalf("outcome %d status = %v, want %v", i, outcome.Status, want[i])
+		}
+		if (outcome.Err == nil) != (want[i] == BroadcastAccepted) {
+			t.Fatalf("outcome %d err = %v with status %v", i, outcome.Err, outcome.Status)
+		}
+	}
+	if !result.Conflicting() {
+		t.Fatal("Conflicting = false with an accepted and a rejected outcome")
+	}
+}
+
+func TestBroadcastFlagsDifferingRefs(t *testing.T) {
+	clients := newBroadcastClients(
+		t,
+		&broadcastSubmitHandler{ref: []byte("a")},
+		&broadcastSubmitHandler{ref: []byte("b")},
+	)
+
+	result, err := Broadcast(clients, []byte{0x84}, WithBroadcastQuorum(2))
+	if err != nil {
+		t.Fatalf("Broadcast returned error: %v", err)
+	}
+	if !result.Conflicting() {
+		t.Fatal("Conflicting = false for differing refs")
+	}
+}
@@ -0,0 +1,179 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+const (
+	// credentialHashSize is the length of a Cardano key or script hash.
+	credentialHashSize = 28
+	// maxAssetNameSize is the longest asset name the ledger accepts.
+	maxAssetNameSize = 32
+)
+
+// ParseAddress returns the raw bytes of a Cardano address given as bech32
+// (Shelley, including stake addresses), base58 (Byron) or hex, as taken by
+// [Client.GetUtxosByAddress] and the other address searches. Those reject
+// stake addresses, which hold no UTxOs.
+func ParseAddress(address string) ([]byte, error) {
+	if address == "" {
+		return nil, errors.New("address is empty")
+	}
+	if raw, err := hex.DecodeString(address); err == nil {
+		if _, err := common.NewAddressFromBytes(raw); err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", address, err)
+		}
+		return raw, nil
+	}
+	addr, err := common.NewAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	raw, err := addr.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	return raw, nil
+}
+
+// decodePaymentPart returns the 28-byte payment credential hash named by
+// credential: a hex hash, a bech32 credential (addr_vkh, script, ...) or an
+// address whose payment part is used.
+func decodePaymentPart(credential string) ([]byte, error) {
+	raw, _, err := decodeCredential(credential, paymentCredentialKind)
+	return raw, err
+}
+
+// decodeDelegationPart returns the 28-byte stake credential hash named by
+// credential: a hex hash, a bech32 credential (stake_vkh, script, ...) or an
+// address (including a stake address) whose delegation part is used.
+func decodeDelegationPart(credential string) ([]byte, error) {
+	raw, _, err := decodeCredential(credential, stakeCredentialKind)
+	return raw, err
+}
+
+// credentialKind describes the payment or stake credentials accepted by
+// decodeCredential.
+type credentialKind struct {
+	name string
+	// hrps are the CIP-5 prefixes of bech32 credentials of this kind.
+	hrps []string
+	// fromAddress extracts the credential from an address.
+	fromAddress func(*common.Address) ([]byte, bool)
+}
+
+var (
+	paymentCredentialKind = credentialKind{
+		name:        "payment",
+		hrps:        []string{"addr_vkh", "addr_shared_vkh", "script"},
+		fromAddress: paymentPart,
+	}
+	stakeCredentialKind = credentialKind{
+		name:        "stake",
+		hrps:        []string{"stake_vkh", "stake_shared_vkh", "script"},
+		fromAddress: delegationPart,
+	}
+)
+
+func paymentPart(addr *common.Address) ([]byte, bool) {
+	switch addr.PayloadPayload().(type) {
+	case common.AddressPayloadKeyHash, common.AddressPayloadScriptHash:
+		return addr.PaymentKeyHash().Bytes(), true
+	}
+	return nil, false
+}
+
+func delegationPart(addr *common.Address) ([]byte, bool) {
+	cred, ok := addr.StakeCredential()
+	if !ok {
+		return nil, false
+	}
+	return cred.Credential.Bytes(), true
+}
+
+// decodeCredential returns the credential hash named by credential and,
+// when it was given as an address, that address.
+func decodeCredential(
+	credential string,
+	kind credentialKind,
+) ([]byte, *common.Address, error) {
+	if credential == "" {
+		return nil, nil, errors.New("credential is empty")
+	}
+	if raw, err := hex.DecodeString(credential); err == nil {
+		if len(raw) != credentialHashSize {
+			return nil, nil, fmt.Errorf(
+				"invalid credential %q: got %d bytes, want %d",
+				credential,
+				len(raw),
+				credentialHashSize,
+			)
+		}
+		return raw, nil, nil
+	}
+	if hrp, data, err := bech32.DecodeNoLimit(credential); err == nil {
+		raw, err := bech32.ConvertBits(data, 5, 8, false)
+		if err == nil && len(raw) == credentialHashSize {
+			if !slices.Contains(kind.hrps, hrp) {
+				return nil, nil, fmt.Errorf(
+					"invalid credential %q: %s is not a %s credential",
+					credential,
+					hrp,
+					kind.name,
+				)
+			}
+			return raw, nil, nil
+		}
+	}
+	addr, err := common.NewAddress(credential)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid credential %q: %w", credential, err)
+	}
+	raw, ok := kind.fromAddress(&addr)
+	if !ok {
+		return nil, nil, fmt.Errorf(
+			"address %q does not carry a %s credential",
+			credential,
+			kind.name,
+		)
+	}
+	return raw, &addr, nil
+}
+
+// decodePolicyID decodes a hex policy ID and checks its length.
+func decodePolicyID(policyID string) ([]byte, error) {
+	raw, err := hex.DecodeString(policyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode policy ID: %w", err)
+	}
+	if len(raw) != credentialHashSize {
+		return nil, fmt.Errorf(
+			"invalid policy ID: got %d bytes, want %d",
+			len(raw),
+			credentialHashSize,
+		)
+	}
+	return raw, nil
+}
+
+// decodeAssetName decodes a hex asset name and checks its length.
+func decodeAssetName(assetName string) ([]byte, error) {
+	raw, err := hex.DecodeString(assetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode asset name: %w", err)
+	}
+	if len(raw) > maxAssetNameSize {
+		return nil, fmt.Errorf(
+			"invalid asset name: got %d bytes, max %d",
+			len(raw),
+			maxAssetNameSize,
+		)
+	}
+	return raw, nil
+}
@@ -0,0 +1,271 @@
+package cardano
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// CIP-67 asset name labels in common use.
+const (
+	AssetLabelReferenceNFT uint16 = 100 // CIP-68 reference token
+	AssetLabelNFT          uint16 = 222 // CIP-68 NFT
+	AssetLabelFT           uint16 = 333 // CIP-68 fungible token
+	AssetLabelRFT          uint16 = 444 // CIP-68 rich fungible token
+)
+
+const (
+	// assetFingerprintHRP is the bech32 prefix of CIP-14 fingerprints.
+	assetFingerprintHRP = "asset"
+	// assetLabelSize is the length of a CIP-67 label prefix.
+	assetLabelSize = 4
+)
+
+// AssetFingerprint returns the CIP-14 fingerprint (asset1...) of an asset.
+// A fingerprint is a hash: it identifies an asset for display but cannot be
+// turned back into its policy ID and name.
+func AssetFingerprint(policyID, assetName []byte) string {
+	return common.NewAssetFingerprint(policyID, assetName).String()
+}
+
+// AssetNameLabel decodes the CIP-67 label prefixing an asset name. It
+// returns the label and the rest of the name, or ok false when the name
+// carries no valid label.
+func AssetNameLabel(assetName []byte) (label uint16, name []byte, ok bool) {
+	if len(assetName) < assetLabelSize {
+		return 0, assetName, false
+	}
+	prefix := binary.BigEndian.Uint32(assetName)
+	if prefix&0xf000000f != 0 {
+		return 0, assetName, false
+	}
+	// #nosec G115 -- the label is the 16 bits above the checksum
+	label = uint16(prefix >> 12)
+	if byte(prefix>>4) != labelChecksum(label) {
+		return 0, assetName, false
+	}
+	return label, assetName[assetLabelSize:], true
+}
+
+// LabeledAssetName returns name prefixed with a CIP-67 label, as used by
+// CIP-68 tokens.
+func LabeledAssetName(label uint16, name []byte) []byte {
+	prefix := uint32(label)<<12 | uint32(labelChecksum(label))<<4
+	return append(binary.BigEndian.AppendUint32(nil, prefix), name...)
+}
+
+// labelChecksum is the CRC-8 (polynomial 0x07) of a CIP-67 label.
+func labelChecksum(label uint16) byte {
+	var crc byte
+	for _, b := range []byte{byte(label >> 8), byte(label)} {
+		crc ^= b
+		for range 8 {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Asset is a native asset held by a UTxO.
+type Asset struct {
+	PolicyID []byte
+	// Name is the on-chain asset name, including any CIP-67 label.
+	Name     []byte
+	Quantity uint64
+}
+
+// UtxoAssets returns the native assets held by a UTxO, as returned by the
+// search helpers.
+func UtxoAssets(item *query.AnyUtxoData) []Asset {
+	var assets []Asset
+	for _, multiasset := range utxoOutput(item).GetAssets() {
+		for _, asset := range multiasset.GetAssets() {
+			assets = append(assets, Asset{
+				PolicyID: multiasset.GetPolicyId(),
+				Name:     asset.GetName(),
+				Quantity: bigIntUint64(asset.GetQuantity()),
+			})
+		}
+	}
+	return assets
+}
+
+// Unit returns the asset's key in [Value.Assets].
+func (a Asset) Unit() string {
+	return AssetUnit(a.PolicyID, a.Name)
+}
+
+// Fingerprint returns the asset's CIP-14 fingerprint.
+func (a Asset) Fingerprint() string {
+	return AssetFingerprint(a.PolicyID, a.Name)
+}
+
+// Label returns the asset's CIP-67 label, if its name has one.
+func (a Asset) Label() (uint16, bool) {
+	label, _, ok := AssetNameLabel(a.Name)
+	return label, ok
+}
+
+// DisplayName returns the asset name without its CIP-67 label, as text when
+// it is printable UTF-8 and as hex otherwise.
+func (a Asset) DisplayName() string {
+	_, name, _ := AssetNameLabel(a.Name)
+	if utf8.Valid(name) && !strings.ContainsFunc(string(name), func(r rune) bool {
+		return !unicode.IsPrint(r)
+	}) {
+		return string(name)
+	}
+	return hex.EncodeToString(name)
+}
+
+// AssetID names the native assets matched by the asset searches: those of
+// a policy, those with a name, or the one with both. A CIP-14 Fingerprint
+// narrows the match further; the server cannot search by it, so it is
+// matched on the results.
+type AssetID struct {
+	PolicyID    []byte
+	Name        []byte
+	Fingerprint string
+}
+
+// ParseAssetID parses an asset given as a hex policy ID, an asset unit (see
+// [AssetUnit]), a "policy.name" pair or a CIP-14 fingerprint (asset1...).
+// Names are hex; use [TextAssetID] for a UTF-8 name. In a pair, either side
+// may be empty, and the name may be a fingerprint to match within the
+// policy.
+func ParseAssetID(asset string) (AssetID, error) {
+	if asset == "" {
+		return AssetID{}, errors.New("asset is empty")
+	}
+	if isAssetFingerprint(asset) {
+		if err := checkAssetFingerprint(asset); err != nil {
+			return AssetID{}, err
+		}
+		return AssetID{Fingerprint: asset}, nil
+	}
+	policyID, name, ok := strings.Cut(asset, ".")
+	if !ok {
+		if len(asset) > 2*credentialHashSize {
+			policy, name, err := splitAssetUnit(asset)
+			if err != nil {
+				return AssetID{}, err
+			}
+			return AssetID{PolicyID: policy, Name: name}, nil
+		}
+		policy, err := decodePolicyID(asset)
+		if err != nil {
+			return AssetID{}, err
+		}
+		return AssetID{PolicyID: policy}, nil
+	}
+	if policyID == "" && name == "" {
+		return AssetID{}, fmt.Errorf("asset %q names no policy or asset name", asset)
+	}
+	var id AssetID
+	var err error
+	if policyID != "" {
+		if id.PolicyID, err = decodePolicyID(policyID); err != nil {
+			return AssetID{}, err
+		}
+	}
+	switch {
+	case isAssetFingerprint(name):
+		if err := checkAssetFingerprint(name); err != nil {
+			return AssetID{}, err
+		}
+		id.Fingerprint = name
+	case name != "":
+		if id.Name, err = decodeAssetName(name); err != nil {
+			return AssetID{}, err
+		}
+	}
+	return id, nil
+}
+
+// TextAssetID returns the asset with a hex policy ID and a name given as
+// UTF-8 text, such as "HUNT". The policy ID may be empty to match the name
+// under any policy.
+func TextAssetID(policyID, name string) (AssetID, error) {
+	var id AssetID
+	if policyID != "" {
+		policy, err := decodePolicyID(policyID)
+		if err != nil {
+			return AssetID{}, err
+		}
+		id.PolicyID = policy
+	}
+	if name == "" {
+		return id, nil
+	}
+	if !utf8.ValidString(name) {
+		return AssetID{}, fmt.Errorf("asset name %q is not valid UTF-8", name)
+	}
+	if len(name) > maxAssetNameSize {
+		return AssetID{}, fmt.Errorf(
+			"invalid asset name: got %d bytes, max %d",
+			len(name),
+			maxAssetNameSize,
+		)
+	}
+	id.Name = []byte(name)
+	return id, nil
+}
+
+func isAssetFingerprint(s string) bool {
+	return strings.HasPrefix(s, assetFingerprintHRP+"1")
+}
+
+func checkAssetFingerprint(fingerprint string) error {
+	hrp, data, err := bech32.Decode(fingerprint)
+	if err != nil {
+		return fmt.Errorf("invalid asset fingerprint %q: %w", fingerprint, err)
+	}
+	raw, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return fmt.Errorf("invalid asset fingerprint %q: %w", fingerprint, err)
+	}
+	if hrp != assetFingerprintHRP || len(raw) != common.Blake2b160Size {
+		return fmt.Errorf("invalid asset fingerprint %q", fingerprint)
+	}
+	return nil
+}
+
+// pattern returns the asset pattern the server matches, or nil for any
+// asset.
+func (a AssetID) pattern() *chaincardano.AssetPattern {
+	if a.PolicyID == nil && a.Name == nil {
+		return nil
+	}
+	return &chaincardano.AssetPattern{PolicyId: a.PolicyID, AssetName: a.Name}
+}
+
+// filter drops the UTxOs of resp holding no asset with the ID's
+// fingerprint.
+func (a AssetID) filter(resp *query.SearchUtxosResponse) {
+	if a.Fingerprint == "" || resp == nil {
+		return
+	}
+	items := resp.GetItems()[:0]
+	for _, item := range resp.GetItems() {
+		for _, asset := range UtxoAssets(item) {
+			if asset.Fingerprint() == a.Fingerprint {
+				items = append(items, item)
+				break
+			}
+		}
+	}
+	resp.Items = items
+}
@@ -0,0 +1,216 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	sdk "github.com/utxorpc/go-sdk"
+)
+
+func TestAssetFingerprint(t *testing.T) {
+	// CIP-14 test vectors
+	tests := []struct {
+		policyID  string
+		assetName string
+		want      string
+	}{
+		{"7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373", "", "asset1rjklcrnsdzqp65wjgrg55sy9723kw09mlgvlc3"},
+		{"7eae28af2208be856f7a119668ae52a49b73725e326dc16579dcc373", "504154415445", "asset13n25uv0yaf5kus35fm2k86cqy60z58d9xmde92"},
+		{"1e349c9bdea19fd6c147626a5260bc44b71635f398b67c59881df209", "504154415445", "asset1hv4p5tv2a837mzqrst04d0dcptdjmluqvdx9k3"},
+	}
+	for _, tt := range tests {
+		got := AssetFingerprint(mustDecodeHex(t, tt.policyID), mustDecodeHex(t, tt.assetName))
+		if got != tt.want {
+			t.Fatalf("AssetFingerprint(%s, %s) = %s, want %s", tt.policyID, tt.assetName, got, tt.want)
+		}
+	}
+}
+
+func TestAssetNameLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		label uint16
+	}{
+		{"000643b0", AssetLabelReferenceNFT},
+		{"000de140", AssetLabelNFT},
+		{"0014df10", AssetLabelFT},
+	}
+	for _, tt := range tests {
+		assetName := append(mustDecodeHex(t, tt.name), "HUNT"...)
+		label, rest, ok := AssetNameLabel(assetName)
+		if !ok || label != tt.label || string(rest) != "HUNT" {
+			t.Fatalf("AssetNameLabel(%x) = %d, %q, %v, want %d, HUNT", assetName, label, rest, ok, tt.label)
+		}
+		if got := LabeledAssetName(tt.label, []byte("HUNT")); !bytes.Equal(got, assetName) {
+			t.Fatalf("LabeledAssetName(%d) = %x, want %x", tt.label, got, assetName)
+		}
+	}
+	for _, name := range []string{"48554e54", "0014df11", "0014df"} {
+		if label, _, ok := AssetNameLabel(mustDecodeHex(t, name)); ok {
+			t.Fatalf("AssetNameLabel(%s) = %d, want no label", name, label)
+		}
+	}
+
+	asset := Asset{Name: mustDecodeHex(t, "0014df1048554e54")}
+	if label, ok := asset.Label(); !ok || label != AssetLabelFT || asset.DisplayName() != "HUNT" {
+		t.Fatalf("asset label = %d, %v, name %q, want 333 HUNT", label, ok, asset.DisplayName())
+	}
+	if got := (Asset{Name: []byte{0x00, 0xff}}).DisplayName(); got != "00ff" {
+		t.Fatalf("DisplayName = %q, want hex", got)
+	}
+}
+
+func TestParseAssetID(t *testing.T) {
+	policy := strings.Repeat("ab", credentialHashSize)
+	fingerprint := AssetFingerprint(mustDecodeHex(t, policy), []byte("HUNT"))
+
+	tests := []struct {
+		name    string
+		asset   string
+		want    AssetID
+		wantErr bool
+	}{
+		{name: "policy", asset: policy, want: AssetID{PolicyID: mustDecodeHex(t, policy)}},
+		{name: "unit", asset: policy + "48554e54", want: AssetID{PolicyID: mustDecodeHex(t, policy), Name: []byte("HUNT")}},
+		{name: "dotted", asset: policy + ".48554e54", want: AssetID{PolicyID: mustDecodeHex(t, policy), Name: []byte("HUNT")}},
+		{name: "name only", asset: ".48554e54", want: AssetID{Name: []byte("HUNT")}},
+		{name: "fingerprint", asset: fingerprint, want: AssetID{Fingerprint: fingerprint}},
+		{
+			name:  "fingerprint in policy",
+			asset: policy + "." + fingerprint,
+			want:  AssetID{PolicyID: mustDecodeHex(t, policy), Fingerprint: fingerprint},
+		},
+		{name: "dotted utf-8", asset: policy + ".HUNT", wantErr: true},
+		{name: "bad fingerprint", asset: "asset1qqqq", wantErr: true},
+		{name: "empty pair", asset: ".", wantErr: true},
+		{name: "short policy", asset: "abcd", wantErr: true},
+		{name: "long name", asset: policy + "." + strings.Repeat("78", 33), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAssetID(tt.asset)
+			checkAssetID(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestTextAssetID(t *testing.T) {
+	policy := strings.Repeat("ab", credentialHashSize)
+
+	tests := []struct {
+		name      string
+		policyID  string
+		assetName string
+		want      AssetID
+		wantErr   bool
+	}{
+		{name: "utf-8 name", policyID: policy, assetName: "HUNT", want: AssetID{PolicyID: mustDecodeHex(t, policy), Name: []byte("HUNT")}},
+		{name: "name only", assetName: "HUNT", want: AssetID{Name: []byte("HUNT")}},
+		{name: "hex-like text", policyID: policy, assetName: "cafe", want: AssetID{PolicyID: mustDecodeHex(t, policy), Name: []byte("cafe")}},
+		{name: "invalid utf-8", policyID: policy, assetName: "\xff", wantErr: true},
+		{name: "long name", policyID: policy, assetName: strings.Repeat("x", 33), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TextAssetID(tt.policyID, tt.assetName)
+			checkAssetID(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func checkAssetID(t *testing.T, got AssetID, err error, want AssetID, wantErr bool) {
+	t.Helper()
+	if wantErr {
+		if err == nil {
+			t.Fatalf("parsed %+v, want an error", got)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("parse returned error: %v", err)
+	}
+	if !bytes.Equal(got.PolicyID, want.PolicyID) || !bytes.Equal(got.Name, want.Name) ||
+		got.Fingerprint != want.Fingerprint {
+		t.Fatalf("asset = %x, %x, %q, want %x, %x, %q",
+			got.PolicyID, got.Name, got.Fingerprint, want.PolicyID, want.Name, want.Fingerprint)
+	}
+}
+
+func TestAssetSearchByFingerprint(t *testing.T) {
+	_, wallet := testAddress(t, 0x11, 0x22)
+	policy := bytes.Repeat([]byte{0xab}, credentialHashSize)
+	hunt := AssetUnit(policy, LabeledAssetName(AssetLabelFT, []byte("HUNT")))
+	dedi := AssetUnit(policy, LabeledAssetName(AssetLabelFT, []byte("DEDI")))
+	fakeQuery := &fingerprintQueryClient{items: []*query.AnyUtxoData{
+		builderUtxo(0x01, wallet, Value{Lovelace: 2_000_000, Assets: map[string]uint64{hunt: 5}}),
+		builderUtxo(0x02, wallet, Value{Lovelace: 2_000_000, Assets: map[string]uint64{dedi: 5}}),
+	}}
+	client := NewClient(sdk.WithBaseUrl("http://example.test"))
+	client.UtxorpcClient.Query = fakeQuery
+	fingerprint := AssetFingerprint(policy, LabeledAssetName(AssetLabelFT, []byte("HUNT")))
+	huntID, err := ParseAssetID(hex.EncodeToString(policy) + "." + fingerprint)
+	if err != nil {
+		t.Fatalf("ParseAssetID returned error: %v", err)
+	}
+
+	resp, err := client.GetUtxosByAssetID(huntID)
+	if err != nil {
+		t.Fatalf("GetUtxosByAssetID returned error: %v", err)
+	}
+	if items := resp.Msg.GetItems(); len(items) != 1 || items[0].GetTxoRef().GetHash()[0] != 0x01 {
+		t.Fatalf("items = %v, want only the HUNT UTxO", items)
+	}
+	asset := fakeQuery.searchUtxosReq.Msg.GetPredicate().GetMatch().GetCardano().GetAsset()
+	if !bytes.Equal(asset.GetPolicyId(), policy) || asset.GetAssetName() != nil {
+		t.Fatalf("asset pattern = %v, want the policy alone", asset)
+	}
+
+	var pages int
+	for resp, err := range client.GetUtxosByAddressWithAssetIDPages(wallet, AssetID{Fingerprint: fingerprint}) {
+		if err != nil {
+			t.Fatalf("GetUtxosByAddressWithAssetIDPages returned error: %v", err)
+		}
+		pages++
+		if items := resp.Msg.GetItems(); len(items) != 1 {
+			t.Fatalf("page items = %v, want only the HUNT UTxO", items)
+		}
+	}
+	if pages != 1 {
+		t.Fatalf("got %d pages, want 1", pages)
+	}
+
+	fakeQuery.searchUtxosReq = nil
+	alone := AssetID{Fingerprint: fingerprint}
+	if _, err := client.GetUtxosByAssetIDWithContext(context.Background(), alone); err == nil ||
+		!strings.Contains(err.Error(), "policy ID") {
+		t.Fatalf("GetUtxosByAssetID error = %v, want a fingerprint error", err)
+	}
+	for _, err := range client.GetUtxosByAssetIDPages(alone) {
+		if err == nil {
+			t.Fatal("GetUtxosByAssetIDPages accepted a fingerprint alone")
+		}
+	}
+	if fakeQuery.searchUtxosReq != nil {
+		t.Fatal("SearchUtxos was called for a fingerprint alone")
+	}
+}
+
+// fingerprintQueryClient answers every SearchUtxos with the same items.
+type fingerprintQueryClient struct {
+	recordingQueryClient
+	items []*query.AnyUtxoData
+}
+
+func (f *fingerprintQueryClient) SearchUtxos(
+	_ context.Context,
+	req *connect.Request[query.SearchUtxosRequest],
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	f.searchUtxosReq = req
+	return connect.NewResponse(&query.SearchUtxosResponse{Items: slices.Clone(f.items)}), nil
+}
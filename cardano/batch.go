@@ -0,0 +1,93 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+)
+
+// BatchError is returned by [Client.SubmitBatch] when a transaction could
+// not be submitted. Index is the position of that transaction in the input;
+// transactions after it were not sent. Err describes the failure: the
+// hex decoding error for malformed input, otherwise the error from
+// [Client.SubmitAndWaitWithContext], usually a [*TxWaitError]. [errors.Is]
+// with [ErrTxRejected], [ErrTxDropped] or [ErrTxDeadline], [errors.As] and
+// connect.CodeOf work on a BatchError directly.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch transaction %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchOption configures [Client.SubmitBatch].
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	stage submit.Stage
+}
+
+// WithBatchWaitStage makes SubmitBatch wait for each transaction to reach
+// stage, typically submit.Stage_STAGE_MEMPOOL, before sending the next one.
+// By default each transaction is sent as soon as the previous one is
+// acknowledged.
+func WithBatchWaitStage(stage submit.Stage) BatchOption {
+	return func(c *batchConfig) {
+		c.stage = stage
+	}
+}
+
+// SubmitBatch calls [Client.SubmitBatchWithContext] with a background
+// context.
+func (c *Client) SubmitBatch(
+	txCbors []string,
+	options ...BatchOption,
+) ([]*SubmitResult, error) {
+	return c.SubmitBatchWithContext(context.Background(), txCbors, options...)
+}
+
+// SubmitBatchWithContext submits a chain of transactions in order, one at a
+// time, so that a transaction spending outputs of an earlier one is never
+// sent before it. txCbors are signed transaction CBOR encoded as hex.
+//
+// The returned results are in input order: results[i] belongs to
+// txCbors[i]. Submission stops at the first failure, which is reported as a
+// [*BatchError] carrying its index; results then holds the transactions
+// accepted before it. Every entry is hex-decoded before anything is sent, so
+// malformed input never leaves a partially submitted chain.
+func (c *Client) SubmitBatchWithContext(
+	ctx context.Context,
+	txCbors []string,
+	options ...BatchOption,
+) ([]*SubmitResult, error) {
+	cfg := batchConfig{stage: submit.Stage_STAGE_ACKNOWLEDGED}
+	for _, option := range options {
+		option(&cfg)
+	}
+	for i, txCbor := range txCbors {
+		if _, err := hex.DecodeString(txCbor); err != nil {
+			return nil, &BatchError{
+				Index: i,
+				Err:   fmt.Errorf("failed to decode transaction: %w", err),
+			}
+		}
+	}
+
+	results := make([]*SubmitResult, 0, len(txCbors))
+	for i, txCbor := range txCbors {
+		result, err := c.SubmitAndWaitWithContext(ctx, txCbor, WithWaitStage(cfg.stage))
+		if err != nil {
+			return results, &BatchError{Index: i, Err: err}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
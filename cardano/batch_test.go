@@ -0,0 +1,96 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+)
+
+func TestSubmitBatchStopsAtFirstRejection(t *testing.T) {
+	fakeSubmit := &batchSubmitHandler{rejectAt: 1}
+	client := newSubmitTestClient(t, fakeSubmit, &depthQueryHandler{})
+
+	results, err := client.SubmitBatch([]string{"aa", "bb", "cc"})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 {
+		t.Fatalf("error = %v, want a *BatchError at index 1", err)
+	}
+	if !errors.Is(err, ErrTxRejected) {
+		t.Fatalf("error = %v, want ErrTxRejected", err)
+	}
+	if code := connect.CodeOf(err); code != connect.CodeFailedPrecondition {
+		t.Fatalf("connect.CodeOf = %v, want %v", code, connect.CodeFailedPrecondition)
+	}
+	if len(results) != 1 || string(results[0].Ref) != "aa" {
+		t.Fatalf("results = %v, want only the first transaction", results)
+	}
+	if want := []string{"aa", "bb"}; !slicesEqual(fakeSubmit.sent, want) {
+		t.Fatalf("sent = %v, want %v", fakeSubmit.sent, want)
+	}
+}
+
+func TestSubmitBatchWaitsForStage(t *testing.T) {
+	fakeSubmit := &scriptedSubmitHandler{
+		stages: []submit.Stage{submit.Stage_STAGE_MEMPOOL},
+	}
+	client := newSubmitTestClient(t, fakeSubmit, &depthQueryHandler{})
+
+	results, err := client.SubmitBatch(
+		[]string{"aa", "bb"},
+		WithBatchWaitStage(submit.Stage_STAGE_MEMPOOL),
+	)
+	if err != nil {
+		t.Fatalf("SubmitBatch returned error: %v", err)
+	}
+	for i, result := range results {
+		if !result.Reached(submit.Stage_STAGE_MEMPOOL) {
+			t.Fatalf("result %d did not reach the mempool", i)
+		}
+	}
+}
+
+func TestSubmitBatchValidatesBeforeSending(t *testing.T) {
+	fakeSubmit := &batchSubmitHandler{rejectAt: -1}
+	client := newSubmitTestClient(t, fakeSubmit, &depthQueryHandler{})
+
+	_, err := client.SubmitBatch([]string{"aa", "not-hex"})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Index != 1 {
+		t.Fatalf("error = %v, want a *BatchError at index 1", err)
+	}
+	var waitErr *TxWaitError
+	if errors.As(err, &waitErr) {
+		t.Fatalf("error = %v, want a decoding error, not a *TxWaitError", err)
+	}
+	if len(fakeSubmit.sent) != 0 {
+		t.Fatalf("sent = %v, want nothing", fakeSubmit.sent)
+	}
+}
+
+// batchSubmitHandler records the submitted transactions and rejects the
+// one at index rejectAt.
+type batchSubmitHandler struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+	rejectAt int
+	sent     []string
+}
+
+func (s *batchSubmitHandler) SubmitTx(
+	_ context.Context,
+	req *connect.Request[submit.SubmitTxRequest],
+) (*connect.Response[submit.SubmitTxResponse], error) {
+	raw := req.Msg.GetTx().GetRaw()
+	s.sent = append(s.sent, hex.EncodeToString(raw))
+	if len(s.sent)-1 == s.rejectAt {
+		return nil, connect.NewError(
+			connect.CodeFailedPrecondition,
+			errors.New("missing input"),
+		)
+	}
+	return connect.NewResponse(&submit.SubmitTxResponse{Ref: []byte(hex.EncodeToString(raw))}), nil
+}
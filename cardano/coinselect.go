@@ -0,0 +1,331 @@
+package cardano
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// ErrInsufficientFunds is returned by coin selection, and so by
+// [TxBuilder.Build], when the available UTxOs cannot cover the target.
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrMaxInputsExceeded is returned by coin selection when covering the
+// target would take more inputs than allowed by [WithMaxInputs].
+var ErrMaxInputsExceeded = errors.New("maximum number of inputs exceeded")
+
+// ErrNoCollateral is returned by [SelectCollateral], and so by
+// [TxBuilder.Build], when no suitable UTxOs cover the collateral.
+var ErrNoCollateral = errors.New("no suitable collateral")
+
+// CoinSelector chooses UTxOs whose combined value covers a target, for
+// example the outputs and fee of a transaction less its fixed inputs.
+// Implementations must not return a UTxO twice, nor one not in available.
+type CoinSelector interface {
+	Select(available []*query.AnyUtxoData, target Value) ([]*query.AnyUtxoData, error)
+}
+
+// CoinSelectionOption configures the selectors returned by [LargestFirst]
+// and [RandomImprove].
+type CoinSelectionOption func(*coinSelectionConfig)
+
+type coinSelectionConfig struct {
+	maxInputs int
+	seed      uint64
+	seeded    bool
+}
+
+// WithMaxInputs limits the number of UTxOs a selection may return. Zero,
+// the default, means no limit. In a [TxBuilder], the limit applies to all
+// the UTxOs it selects from the change address, over every balance round.
+func WithMaxInputs(n int) CoinSelectionOption {
+	return func(c *coinSelectionConfig) {
+		c.maxInputs = max(n, 0)
+	}
+}
+
+// WithSelectionSeed makes [RandomImprove] deterministic: the same seed,
+// available UTxOs and target always give the same selection. Use it in
+// tests.
+func WithSelectionSeed(seed uint64) CoinSelectionOption {
+	return func(c *coinSelectionConfig) {
+		c.seed, c.seeded = seed, true
+	}
+}
+
+// resumableSelector is implemented by the selectors of this package so that
+// [TxBuilder] can apply [WithMaxInputs] to the inputs it selects over all
+// its balance rounds.
+type resumableSelector interface {
+	// selectAfter selects like Select, counting chosen inputs picked by
+	// earlier calls towards the input limit.
+	selectAfter(available []*query.AnyUtxoData, target Value, chosen int) ([]*query.AnyUtxoData, error)
+}
+
+func newCoinSelectionConfig(options []CoinSelectionOption) coinSelectionConfig {
+	var cfg coinSelectionConfig
+	for _, option := range options {
+		option(&cfg)
+	}
+	return cfg
+}
+
+// LargestFirst returns a [CoinSelector] that covers each asset of the
+// target in turn, then lovelace, always taking the UTxO holding the most of
+// what is still missing. It uses few inputs and is predictable, at the cost
+// of fragmenting the wallet into small UTxOs over time.
+func LargestFirst(options ...CoinSelectionOption) CoinSelector {
+	return &largestFirst{cfg: newCoinSelectionConfig(options)}
+}
+
+type largestFirst struct {
+	cfg coinSelectionConfig
+}
+
+func (s *largestFirst) Select(available []*query.AnyUtxoData, target Value) ([]*query.AnyUtxoData, error) {
+	return s.selectAfter(available, target, 0)
+}
+
+func (s *largestFirst) selectAfter(
+	available []*query.AnyUtxoData,
+	target Value,
+	chosen int,
+) ([]*query.AnyUtxoData, error) {
+	sel := newSelection(available, target, s.cfg.maxInputs, chosen)
+	for _, dim := range sel.dimensions() {
+		for sel.short(dim) > 0 {
+			best := -1
+			for i, c := range sel.remaining {
+				if q := quantityOf(c.value, dim); q > 0 &&
+					(best < 0 || q > quantityOf(sel.remaining[best].value, dim)) {
+					best = i
+				}
+			}
+			if best < 0 {
+				return nil, sel.insufficient(dim)
+			}
+			if err := sel.take(best); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return sel.inputs, nil
+}
+
+// RandomImprove returns a [CoinSelector] implementing the CIP-2
+// random-improve algorithm, extended to multi-asset targets by running it
+// for each asset of the target in turn, then for lovelace.
+//
+// For each, UTxOs holding it are first drawn at random until it is
+// covered. The selection is then improved with further random UTxOs while
+// they bring its total closer to twice the target without exceeding three
+// times the target or the input limit. The surplus makes change outputs of
+// a size similar to the payments, which keeps the wallet's UTxO set healthy.
+// If the random phase exceeds the input limit, selection falls back to
+// [LargestFirst], as CIP-2 recommends.
+func RandomImprove(options ...CoinSelectionOption) CoinSelector {
+	return &randomImprove{cfg: newCoinSelectionConfig(options)}
+}
+
+type randomImprove struct {
+	cfg coinSelectionConfig
+}
+
+func (s *randomImprove) Select(available []*query.AnyUtxoData, target Value) ([]*query.AnyUtxoData, error) {
+	return s.selectAfter(available, target, 0)
+}
+
+func (s *randomImprove) selectAfter(
+	available []*query.AnyUtxoData,
+	target Value,
+	chosen int,
+) ([]*query.AnyUtxoData, error) {
+	// A generator per call keeps seeded selections repeatable and Select
+	// safe for concurrent use.
+	intn := rand.IntN
+	if s.cfg.seeded {
+		intn = rand.New(rand.NewPCG(s.cfg.seed, s.cfg.seed)).IntN
+	}
+	sel := newSelection(available, target, s.cfg.maxInputs, chosen)
+	dims := sel.dimensions()
+	for _, dim := range dims {
+		for sel.short(dim) > 0 {
+			candidates := sel.holding(dim)
+			if len(candidates) == 0 {
+				return nil, sel.insufficient(dim)
+			}
+			if err := sel.take(candidates[intn(len(candidates))]); err != nil {
+				if errors.Is(err, ErrMaxInputsExceeded) {
+					return (&largestFirst{cfg: s.cfg}).selectAfter(available, target, chosen)
+				}
+				return nil, err
+			}
+		}
+	}
+	for _, dim := range dims {
+		want := quantityOf(target, dim)
+		ideal, limit := 2*want, 3*want
+		for {
+			if sel.full() {
+				return sel.inputs, nil
+			}
+			candidates := sel.holding(dim)
+			if len(candidates) == 0 {
+				break
+			}
+			i := candidates[intn(len(candidates))]
+			have := quantityOf(sel.total, dim)
+			next := have + quantityOf(sel.remaining[i].value, dim)
+			if next > limit || absDiff(next, ideal) >= absDiff(have, ideal) {
+				break
+			}
+			if err := sel.take(i); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return sel.inputs, nil
+}
+
+// SelectCollateral picks UTxOs to use as collateral worth at least amount
+// lovelace: key-locked, holding only ADA, and carrying no datum or
+// reference script. It prefers the smallest single UTxO that is enough,
+// and otherwise combines the largest ones, up to maxInputs (zero means no
+// limit). It fails with [ErrNoCollateral].
+func SelectCollateral(
+	available []*query.AnyUtxoData,
+	amount uint64,
+	maxInputs int,
+) ([]*query.AnyUtxoData, error) {
+	var candidates []candidate
+	for _, utxo := range available {
+		output := utxoOutput(utxo)
+		value := outputValue(output)
+		if _, ok := paymentKeyHash(output.GetAddress()); !ok ||
+			value.HasAssets() || output.GetDatum() != nil || output.GetScript() != nil {
+			continue
+		}
+		candidates = append(candidates, candidate{utxo, value})
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(a.value.Lovelace, b.value.Lovelace)
+	})
+	if i := slices.IndexFunc(candidates, func(c candidate) bool { return c.value.Lovelace >= amount }); i >= 0 {
+		return []*query.AnyUtxoData{candidates[i].utxo}, nil
+	}
+	var selected []*query.AnyUtxoData
+	var total uint64
+	for _, c := range slices.Backward(candidates) {
+		if maxInputs > 0 && len(selected) == maxInputs {
+			break
+		}
+		selected = append(selected, c.utxo)
+		if total += c.value.Lovelace; total >= amount {
+			return selected, nil
+		}
+	}
+	return nil, fmt.Errorf(
+		"%w: need %d lovelace in at most %d key-locked, ADA-only UTxOs",
+		ErrNoCollateral,
+		amount,
+		maxInputs,
+	)
+}
+
+type candidate struct {
+	utxo  *query.AnyUtxoData
+	value Value
+}
+
+// selection is the state of a coin selection in progress.
+type selection struct {
+	remaining []candidate
+	inputs    []*query.AnyUtxoData
+	total     Value
+	target    Value
+	maxInputs int
+	// chosen counts inputs selected before, which use up maxInputs too.
+	chosen int
+}
+
+func newSelection(available []*query.AnyUtxoData, target Value, maxInputs, chosen int) *selection {
+	sel := &selection{target: target, maxInputs: maxInputs, chosen: chosen}
+	for _, utxo := range available {
+		sel.remaining = append(sel.remaining, candidate{utxo, UtxoValue(utxo)})
+	}
+	return sel
+}
+
+// dimensions returns the assets of the target in a stable order, then ""
+// for lovelace.
+func (s *selection) dimensions() []string {
+	var dims []string
+	for unit, quantity := range s.target.Assets {
+		if quantity > 0 {
+			dims = append(dims, unit)
+		}
+	}
+	slices.Sort(dims)
+	return append(dims, "")
+}
+
+// short returns how much of dim is still missing.
+func (s *selection) short(dim string) uint64 {
+	want, have := quantityOf(s.target, dim), quantityOf(s.total, dim)
+	return want - min(want, have)
+}
+
+// holding returns the indexes of the remaining UTxOs holding some dim.
+func (s *selection) holding(dim string) []int {
+	var indexes []int
+	for i, c := range s.remaining {
+		if quantityOf(c.value, dim) > 0 {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (s *selection) full() bool {
+	return s.maxInputs > 0 && s.chosen+len(s.inputs) >= s.maxInputs
+}
+
+// take moves remaining[i] into the selection.
+func (s *selection) take(i int) error {
+	if s.full() {
+		return fmt.Errorf("%w: %d inputs do not cover the target", ErrMaxInputsExceeded, s.maxInputs)
+	}
+	c := s.remaining[i]
+	s.remaining = slices.Delete(s.remaining, i, i+1)
+	s.inputs = append(s.inputs, c.utxo)
+	s.total = s.total.Add(c.value)
+	return nil
+}
+
+func (s *selection) insufficient(dim string) error {
+	name := dim
+	if dim == "" {
+		name = "lovelace"
+	}
+	return fmt.Errorf("%w: short %d %s", ErrInsufficientFunds, s.short(dim), name)
+}
+
+// quantityOf returns the amount of an asset unit in v, or its lovelace for
+// the unit "".
+func quantityOf(v Value, unit string) uint64 {
+	if unit == "" {
+		return v.Lovelace
+	}
+	return v.Assets[unit]
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
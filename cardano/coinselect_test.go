@@ -0,0 +1,173 @@
+package cardano
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+func selectedHashes(utxos []*query.AnyUtxoData) []byte {
+	hashes := make([]byte, len(utxos))
+	for i, utxo := range utxos {
+		hashes[i] = utxo.GetTxoRef().GetHash()[0]
+	}
+	return hashes
+}
+
+func selectedValue(utxos []*query.AnyUtxoData) Value {
+	var total Value
+	for _, utxo := range utxos {
+		total = total.Add(UtxoValue(utxo))
+	}
+	return total
+}
+
+func TestLargestFirst(t *testing.T) {
+	_, wallet := testAddress(t, 0x11, 0x22)
+	token := strings.Repeat("ab", credentialHashSize) + "746f6b656e"
+	available := []*query.AnyUtxoData{
+		builderUtxo(0x01, wallet, Value{Lovelace: 3_000_000}),
+		builderUtxo(0x02, wallet, Value{Lovelace: 9_000_000}),
+		builderUtxo(0x03, wallet, Value{Lovelace: 1_500_000, Assets: map[string]uint64{token: 5}}),
+		builderUtxo(0x04, wallet, Value{Lovelace: 1_500_000, Assets: map[string]uint64{token: 2}}),
+		builderUtxo(0x05, wallet, Value{Lovelace: 5_000_000}),
+	}
+
+	tests := []struct {
+		name   string
+		target Value
+		want   []byte
+	}{
+		{name: "lovelace", target: Value{Lovelace: 10_000_000}, want: []byte{0x02, 0x05}},
+		{name: "asset first", target: Value{Lovelace: 2_000_000, Assets: map[string]uint64{token: 6}}, want: []byte{0x03, 0x04}},
+		{name: "asset then lovelace", target: Value{Lovelace: 5_000_000, Assets: map[string]uint64{token: 1}}, want: []byte{0x03, 0x02}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := LargestFirst().Select(available, tt.target)
+			if err != nil {
+				t.Fatalf("Select returned error: %v", err)
+			}
+			if got := selectedHashes(selected); string(got) != string(tt.want) {
+				t.Fatalf("selected = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoinSelectionLimits(t *testing.T) {
+	_, wallet := testAddress(t, 0x11, 0x22)
+	available := []*query.AnyUtxoData{
+		builderUtxo(0x01, wallet, Value{Lovelace: 2_000_000}),
+		builderUtxo(0x02, wallet, Value{Lovelace: 2_000_000}),
+		builderUtxo(0x03, wallet, Value{Lovelace: 2_000_000}),
+	}
+	for _, selector := range []CoinSelector{
+		LargestFirst(WithMaxInputs(2)),
+		RandomImprove(WithMaxInputs(2), WithSelectionSeed(1)),
+	} {
+		_, err := selector.Select(available, Value{Lovelace: 5_000_000})
+		if !errors.Is(err, ErrMaxInputsExceeded) {
+			t.Fatalf("%T: Select error = %v, want ErrMaxInputsExceeded", selector, err)
+		}
+		_, err = selector.Select(available, Value{Assets: map[string]uint64{strings.Repeat("cd", credentialHashSize): 1}})
+		if !errors.Is(err, ErrInsufficientFunds) {
+			t.Fatalf("%T: Select error = %v, want ErrInsufficientFunds", selector, err)
+		}
+	}
+}
+
+func TestRandomImprove(t *testing.T) {
+	_, wallet := testAddress(t, 0x11, 0x22)
+	token := strings.Repeat("ab", credentialHashSize) + "746f6b656e"
+	var available []*query.AnyUtxoData
+	for i := range 20 {
+		value := Value{Lovelace: uint64(i+1) * 1_000_000}
+		if i%4 == 0 {
+			value.Assets = map[string]uint64{token: 3}
+		}
+		available = append(available, builderUtxo(byte(i+1), wallet, value))
+	}
+	target := Value{Lovelace: 12_000_000, Assets: map[string]uint64{token: 4}}
+
+	first, err := RandomImprove(WithSelectionSeed(7)).Select(available, target)
+	if err != nil {
+		t.Fatalf("Select returned error: %v", err)
+	}
+	seeded := RandomImprove(WithSelectionSeed(7))
+	for range 2 {
+		again, err := seeded.Select(available, target)
+		if err != nil {
+			t.Fatalf("Select returned error: %v", err)
+		}
+		if string(selectedHashes(first)) != string(selectedHashes(again)) {
+			t.Fatalf("seeded selections differ: %x and %x", selectedHashes(first), selectedHashes(again))
+		}
+	}
+
+	total := selectedValue(first)
+	if !total.Covers(target) {
+		t.Fatalf("selected %+v, want at least %+v", total, target)
+	}
+	seen := make(map[string]bool)
+	for _, utxo := range first {
+		if key := utxoKey(utxo); seen[key] {
+			t.Fatalf("UTxO %s selected twice", key)
+		} else {
+			seen[key] = true
+		}
+	}
+
+	limited, err := RandomImprove(WithSelectionSeed(7), WithMaxInputs(3)).Select(available, Value{Lovelace: 30_000_000})
+	if err != nil {
+		t.Fatalf("Select returned error: %v", err)
+	}
+	if len(limited) > 3 || selectedValue(limited).Lovelace < 30_000_000 {
+		t.Fatalf("selected %x, want at most 3 inputs covering the target", selectedHashes(limited))
+	}
+}
+
+func TestSelectCollateral(t *testing.T) {
+	_, wallet := testAddress(t, 0x11, 0x22)
+	script := append([]byte{0x71}, make([]byte, credentialHashSize)...)
+	token := strings.Repeat("ab", credentialHashSize) + "746f6b656e"
+	available := []*query.AnyUtxoData{
+		builderUtxo(0x01, script, Value{Lovelace: 5_000_000}),
+		builderUtxo(0x02, wallet, Value{Lovelace: 9_000_000, Assets: map[string]uint64{token: 1}}),
+		builderUtxo(0x03, wallet, Value{Lovelace: 4_000_000}),
+		builderUtxo(0x04, wallet, Value{Lovelace: 2_000_000}),
+		builderUtxo(0x05, wallet, Value{Lovelace: 3_000_000}),
+	}
+
+	tests := []struct {
+		name      string
+		amount    uint64
+		maxInputs int
+		want      []byte
+		err       error
+	}{
+		{name: "smallest enough", amount: 2_500_000, maxInputs: 3, want: []byte{0x05}},
+		{name: "combined", amount: 6_000_000, maxInputs: 3, want: []byte{0x03, 0x05}},
+		{name: "too many inputs", amount: 8_000_000, maxInputs: 2, err: ErrNoCollateral},
+		{name: "not enough", amount: 10_000_000, maxInputs: 3, err: ErrNoCollateral},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := SelectCollateral(available, tt.amount, tt.maxInputs)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("SelectCollateral error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectCollateral returned error: %v", err)
+			}
+			if got := selectedHashes(selected); string(got) != string(tt.want) {
+				t.Fatalf("selected = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
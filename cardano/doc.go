@@ -25,10 +25,16 @@
 //     full signed transaction CBOR encoded as a hex string.
 //   - [Client.GetBlockByRef], [Client.WatchBlocksByRef], and
 //     [Client.WatchTransaction] accept a hex block hash plus a slot; pass an
-//     empty hash and -1 slot to start from origin / current tip.
-//   - Address arguments are raw bytes, not bech32. Use a Cardano library such
-//     as [github.com/blinklabs-io/gouroboros] to decode addresses first.
+//     empty hash and -1 slot to start from origin / current tip. An invalid
+//     hex hash is returned as an error.
+//   - Address arguments of the GetUtxosBy* helpers are raw bytes, not bech32.
+//     Use a Cardano library such as [github.com/blinklabs-io/gouroboros] to
+//     decode addresses first.
 //   - Asset filters: policy ID and asset name are raw bytes.
+//   - Predicate builders ([NewOutputPattern], [NewTxPredicate],
+//     [NewUtxoPredicate]) take strings instead: addresses as bech32, base58
+//     or hex; credentials as a 28-byte hex hash, bech32 credential or
+//     address; policy IDs and asset names as hex.
 //
 // # API surface
 //
@@ -43,19 +49,47 @@
 // Query helpers:
 //
 //	GetProtocolParameters()
+//	GetGenesis()                                — Cardano genesis configuration
+//	GetEraSummary()                             — era boundaries
 //	GetUtxoByRef(txHash, idx)                   — hex or base64 hash
 //	GetUtxosByRefs(refs)                        — batched
-//	GetUtxosByAddress(addressBytes)
-//	GetUtxosByAddressWithAsset(addr, policyId, assetName)
+//	GetUtxosByAddress(addressBytes)             — not a stake address
+//	GetUtxosByAddressWithAsset(addressBytes, policyId, assetName)
+//	GetUtxosByPaymentCredential(credential)     — key or script hash, any address
+//	GetUtxosByStakeCredential(credential)       — any address delegating to it
 //	GetUtxosByAsset(policyId, assetName)        — at least one of the two required
+//	GetUtxosByAssetID(asset)                    — [AssetID], may be a fingerprint
+//	GetUtxosByAddressWithAssetID(addressBytes, asset)
 //	GetUtxosByAddressPages(addressBytes)        — lazy automatic pagination
 //	GetUtxosByAddressWithAssetPages(...)
+//	GetUtxosByPaymentCredentialPages(...)
+//	GetUtxosByStakeCredentialPages(...)
 //	GetUtxosByAssetPages(...)
+//	GetUtxosByAssetIDPages(asset)
+//	GetUtxosByAddressWithAssetIDPages(...)
+//	GetUtxosByPredicate(pred)                   — arbitrary UtxoPredicateBuilder
+//	GetUtxosByPredicatePages(pred)
 //
 // UTxO search helpers accept optional [SearchOption] values. Use
 // [WithSearchMaxItems], [WithSearchStartToken], and [WithSearchFieldMask] to
 // control pagination and field selection without constructing a raw protobuf
-// request.
+// request. Address searches take raw address bytes; [ParseAddress] decodes
+// bech32, base58 and hex strings. Addresses given to the address and
+// credential searches must be on the server's network, read once from its
+// genesis, or the search fails with [ErrNetworkMismatch]; the check is
+// skipped when the genesis cannot be read. Asset searches
+// take raw policy ID and asset name bytes, or an [AssetID]: [ParseAssetID]
+// reads a hex policy ID, unit, "policy.name" or CIP-14 fingerprint, and
+// [TextAssetID] takes a UTF-8 asset name. A fingerprint cannot be reversed,
+// so it is matched on the client against the UTxOs of its policy or
+// address; on its own it is an error.
+//
+// Assets (local, for display):
+//
+//	AssetFingerprint(policy, name)              — CIP-14 asset1... fingerprint
+//	AssetNameLabel(name), LabeledAssetName(...) — CIP-67 labels (100, 222, 333, 444)
+//	UtxoAssets(utxo)                            — [Asset] with Label, DisplayName,
+//	                                              Fingerprint and Unit
 //
 // Submit helpers:
 //
@@ -64,6 +98,97 @@
 //	GetMempoolTransactions()
 //	WaitForTransaction(txRefHex)                — server stream
 //	WatchMempoolTransactions()                  — server stream
+//	SubmitAndWait(txCborHex, opts...)           — submit, then follow WaitForTx
+//	                                              to a stage or depth
+//
+// SubmitAndWait options are [WithWaitStage], [WithConfirmationDepth] and
+// [WithDepthPollInterval]. Its failures are [*TxWaitError] values wrapping
+// [ErrTxRejected], [ErrTxDropped], [ErrTxDeadline] or [ErrTxRefMismatch].
+//
+// Decoding (local, no server call):
+//
+//	DecodeTransaction(txCborHex)                — hash, inputs, outputs, fee,
+//	                                              validity interval
+//	TransactionHash(txCborHex)                  — hex transaction id
+//
+// Building (reads params and UTxOs, evaluates scripts):
+//
+//	NewTxBuilder()                              — [*TxBuilder]; Build() returns
+//	                                              unsigned CBOR hex
+//	PayTo / AddOutput / AddInput / SpendScript / ReadFrom
+//	AttachScript / AttachDatum / Mint / SetMetadata
+//	ValidFrom / ValidUntil / RequireSigner / ChangeAddress / CoinSelection
+//	Value, AssetUnit(policy, name), UtxoValue(utxo)
+//
+// Coin selection (local, over search results):
+//
+//	LargestFirst(opts...)                       — [CoinSelector], the default
+//	RandomImprove(opts...)                      — CIP-2, multi-asset
+//	WithMaxInputs(n), WithSelectionSeed(seed)   — input limit, deterministic mode
+//	SelectCollateral(utxos, amount, maxInputs)  — ADA-only, key-locked
+//
+// Signing (local, no server call):
+//
+//	NewEd25519Signer(key), NewExtendedSigner(key) — in-memory [Signer]s
+//	KeyHash(signer)                             — credential / required signer
+//	SignTransaction(txCborHex, signers...)      — sign body hash, attach witnesses
+//	CreateVkeyWitness(txCborHex, signer)        — detached witness
+//	AddVkeyWitnesses(txCborHex, witnesses...)   — merge; body bytes untouched
+//
+// Evaluation:
+//
+//	EvaluateTransactionReport(txCborHex)        — typed EvalTx result with
+//	                                              per-redeemer costs and fees
+//	NewEvalReport(eval, params)                 — same, from raw messages
+//	(*EvalReport).WithinLimits() / Err()
+//
+// Fees:
+//
+//	CalculateFee(inputs, params)                — Conway min fee breakdown
+//	CalculateTxFee(txCborHex, refScriptSize, params)
+//	EstimateTransactionFee(txCborHex)           — params and reference
+//	                                              scripts read from the server
+//
+// Validation:
+//
+//	ValidateTransaction(txCborHex)              — local checks against params,
+//	                                              inputs and tip
+//	ValidateTransaction(txCborHex, vc)          — same, with caller-supplied
+//	                                              [ValidationContext]
+//
+// Each broken rule comes back as a [Violation] naming its [ValidationRule]
+// and, for unknown inputs, the [InputKind] its index refers to.
+//
+// Batches:
+//
+//	SubmitBatch(txCborHexes, opts...)           — ordered submission of a tx chain
+//
+// SubmitBatch stops at the first failure and returns a [*BatchError] with
+// the input index. [WithBatchWaitStage] waits for each transaction to reach
+// a stage, such as the mempool, before sending the next.
+//
+// Lifecycle tracking:
+//
+//	NewTracker(opts...)                         — follows submitted txs to finality
+//	(*Tracker).Track(txHashHex, txCborHex)      — register a submitted tx
+//	(*Tracker).State(txHashHex) / States() / Forget(txHashHex)
+//	(*Tracker).Run(ctx)                         — watch, reconcile and resubmit
+//
+// A [Tracker] resubmits transactions that leave the mempool unconfirmed or
+// are rolled back, up to [WithMaxResubmissions] times and only before their
+// TTL. [WithStateChangeHandler] reports every [TxState] transition.
+//
+// Mempool:
+//
+//	NewMempoolObserver(opts...)                 — live view from ReadMempool
+//	                                              and WatchMempool
+//	(*MempoolObserver).Run(ctx) / Resync(ctx)
+//	(*MempoolObserver).Get / Transactions / Filter(pred)
+//	(*MempoolObserver).SpentBy(txHashHex, idx)  — pending spender of a UTxO
+//	(*MempoolObserver).Stats()                  — size, bytes, fees, ages
+//	(*MempoolObserver).Spendable(items, pred)   — mempool-adjusted UTxOs
+//	GetSpendableUtxos(pred)                     — SearchUtxos adjusted by a
+//	                                              ReadMempool snapshot
 //
 // Sync helpers:
 //
@@ -73,10 +198,56 @@
 //	                                              if response is empty or
 //	                                              not a Cardano block.
 //	WatchBlocksByRef(blockHashHex, slot)        — server stream
+//	FindIntersect(points)                       — best of several candidate
+//	                                              points; reports rollbacks
+//	ExponentialPoints(points)                   — thin a point list for FindIntersect
+//
+// Finality:
+//
+//	NewFinalityBuffer(k)                        — holds back the last k blocks
+//	(*Client).NewFinalityBuffer()               — k from the genesis security param
+//	(*FinalityBuffer).Push(resp) / Follow(stream)
+//	(*FinalityBuffer).Volatile()                — blocks that may still roll back
+//
+// Time:
+//
+//	NewEraHistory(summaries, genesis, tipSlot)  — slot/time/epoch converter
+//	(*Client).NewEraHistory()                   — loads summary, genesis and tip
+//	(*EraHistory).SlotToTime / TimeToSlot / SlotToEpoch / TimeToEpoch
+//	(*EraHistory).EpochStartSlot / EpochEndSlot
+//	(*EraHistory).IsStale(tipSlot)              — tip reached the horizon; rebuild
+//
+// Conversions past [EraHistory.Horizon] fail with [ErrBeyondHorizon].
 //
 // Watch helpers:
 //
 //	WatchTransaction(blockHashHex, slot)        — server stream
+//	WatchTransactionsByPredicate(pred, hash, slot) — server stream, filtered
+//	EmulateWatchTransaction(req)                — WatchTx served from FollowTip
+//	WatchTransactionWithFallback(req)           — WatchTx, emulated if the
+//	                                              server lacks the Watch service
+//
+// The emulated and fallback forms return a [TxStream], which the native
+// *connect.ServerStreamForClient also satisfies. The emulation turns a
+// FollowTip Reset into Undo events for the matching blocks it rolls back.
+//
+//	(*Client).NewTxMultiplexer(opts...)         — one upstream stream, many
+//	                                              subscriptions
+//	(*TxMultiplexer).Subscribe / Unsubscribe / Run
+//
+// Predicates:
+//
+//	NewOutputPattern()                          — address / credential / asset
+//	NewTxPredicate()                            — Consumes, Produces, HasAddress,
+//	                                              MovesAsset, MintsAsset
+//	(*TxPredicateBuilder).AllOf / AnyOf / Not   — combinators
+//	(*TxPredicateBuilder).Build()               — *watch.TxPredicate
+//	NewUtxoPredicate()                          — Address, PaymentPart,
+//	                                              DelegationPart, Policy, Asset
+//	(*UtxoPredicateBuilder).AllOf / AnyOf / Not
+//	(*UtxoPredicateBuilder).Build()             — *query.UtxoPredicate
+//	MatchUtxo(pred, output), MatchTx(pred, tx)  — evaluate predicates locally
+//	FilterUtxos(pred, items)                    — post-filter SearchUtxos results
 //
 // # Method-pair convention
 //
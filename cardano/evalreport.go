@@ -0,0 +1,224 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+)
+
+var (
+	// ErrScriptFailed reports that a script failed during evaluation.
+	ErrScriptFailed = errors.New("script evaluation failed")
+	// ErrExUnitsExceeded reports that a transaction needs more execution
+	// units than the protocol parameters allow per transaction.
+	ErrExUnitsExceeded = errors.New("execution units exceed the per-transaction limit")
+)
+
+// RedeemerCost is the evaluated cost of a single redeemer.
+type RedeemerCost struct {
+	Purpose chaincardano.RedeemerPurpose
+	// Index is the 0-based position of the redeemer within its purpose.
+	Index  uint32
+	Memory uint64
+	Steps  uint64
+	// Fee is this redeemer's share of the script fee in lovelace, rounded
+	// up on its own. The shares can sum to slightly more than
+	// [EvalReport.ScriptFee].
+	Fee uint64
+}
+
+func (r RedeemerCost) String() string {
+	return fmt.Sprintf(
+		"%s: mem %d, steps %d, fee %d",
+		redeemerName(r.Purpose, r.Index),
+		r.Memory,
+		r.Steps,
+		r.Fee,
+	)
+}
+
+// EvalMessage is an evaluation error or trace attributed to a redeemer.
+type EvalMessage struct {
+	Purpose chaincardano.RedeemerPurpose
+	Index   uint32
+	Msg     string
+}
+
+func (m EvalMessage) String() string {
+	if m.Purpose == chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_UNSPECIFIED {
+		return m.Msg
+	}
+	return redeemerName(m.Purpose, m.Index) + ": " + m.Msg
+}
+
+// EvalReport interprets a Cardano EvalTx result against the protocol
+// parameters.
+type EvalReport struct {
+	Redeemers []RedeemerCost
+	// TotalMemory and TotalSteps sum the redeemers, or repeat the
+	// server's total when it reports no redeemers.
+	TotalMemory uint64
+	TotalSteps  uint64
+	// MaxMemory and MaxSteps are the per-transaction limits; zero when
+	// the parameters do not set them.
+	MaxMemory uint64
+	MaxSteps  uint64
+	// ScriptFee is ceil(memory price × TotalMemory + step price ×
+	// TotalSteps) in lovelace.
+	ScriptFee uint64
+	// Fee is the fee reported by the server, if any.
+	Fee    uint64
+	Errors []EvalMessage
+	Traces []EvalMessage
+}
+
+// NewEvalReport builds an [EvalReport] from an evaluation and the protocol
+// parameters. params may be nil, in which case limits and fees are zero.
+func NewEvalReport(eval *chaincardano.TxEval, params *chaincardano.PParams) *EvalReport {
+	report := &EvalReport{
+		MaxMemory: params.GetMaxExecutionUnitsPerTransaction().GetMemory(),
+		MaxSteps:  params.GetMaxExecutionUnitsPerTransaction().GetSteps(),
+		Fee:       bigIntUint64(eval.GetFee()),
+	}
+	prices := params.GetPrices()
+	for _, redeemer := range eval.GetRedeemers() {
+		units := redeemer.GetExUnits()
+		report.Redeemers = append(report.Redeemers, RedeemerCost{
+			Purpose: redeemer.GetPurpose(),
+			Index:   redeemer.GetIndex(),
+			Memory:  units.GetMemory(),
+			Steps:   units.GetSteps(),
+			Fee:     scriptFee(prices, units.GetMemory(), units.GetSteps()),
+		})
+		report.TotalMemory += units.GetMemory()
+		report.TotalSteps += units.GetSteps()
+	}
+	if len(report.Redeemers) == 0 {
+		report.TotalMemory = eval.GetExUnits().GetMemory()
+		report.TotalSteps = eval.GetExUnits().GetSteps()
+	}
+	report.ScriptFee = scriptFee(prices, report.TotalMemory, report.TotalSteps)
+	for _, e := range eval.GetErrors() {
+		report.Errors = append(report.Errors, EvalMessage{e.GetPurpose(), e.GetIndex(), e.GetMsg()})
+	}
+	for _, trace := range eval.GetTraces() {
+		report.Traces = append(report.Traces, EvalMessage{trace.GetPurpose(), trace.GetIndex(), trace.GetMsg()})
+	}
+	return report
+}
+
+// WithinLimits reports whether the totals fit the per-transaction limits.
+// Limits the parameters do not set are not checked.
+func (r *EvalReport) WithinLimits() bool {
+	return (r.MaxMemory == 0 || r.TotalMemory <= r.MaxMemory) &&
+		(r.MaxSteps == 0 || r.TotalSteps <= r.MaxSteps)
+}
+
+// Err returns nil if evaluation succeeded within limits. Otherwise the
+// error wraps [ErrScriptFailed] with every evaluation error, and/or
+// [ErrExUnitsExceeded] with the totals and limits.
+func (r *EvalReport) Err() error {
+	var errs []error
+	if len(r.Errors) > 0 {
+		messages := make([]string, 0, len(r.Errors))
+		for _, e := range r.Errors {
+			messages = append(messages, e.String())
+		}
+		errs = append(errs, fmt.Errorf("%w: %s", ErrScriptFailed, strings.Join(messages, "; ")))
+	}
+	if !r.WithinLimits() {
+		errs = append(errs, fmt.Errorf(
+			"%w: mem %d/%d, steps %d/%d",
+			ErrExUnitsExceeded,
+			r.TotalMemory,
+			r.MaxMemory,
+			r.TotalSteps,
+			r.MaxSteps,
+		))
+	}
+	return errors.Join(errs...)
+}
+
+// EvaluateTransactionReport calls
+// [Client.EvaluateTransactionReportWithContext] with a background context.
+func (c *Client) EvaluateTransactionReport(txCbor string) (*EvalReport, error) {
+	return c.EvaluateTransactionReportWithContext(context.Background(), txCbor)
+}
+
+// EvaluateTransactionReportWithContext evaluates a transaction via
+// Submit.EvalTx and interprets the result against the current protocol
+// parameters. txCbor is hex, as for [Client.EvaluateTransaction]. A script
+// failure is part of the report, not the returned error; see
+// [EvalReport.Err].
+func (c *Client) EvaluateTransactionReportWithContext(
+	ctx context.Context,
+	txCbor string,
+) (*EvalReport, error) {
+	txRawBytes, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	resp, err := c.EvaluateTransactionWithContext(ctx, &submit.EvalTxRequest{
+		Tx: &submit.AnyChainTx{Type: &submit.AnyChainTx_Raw{Raw: txRawBytes}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate transaction: %w", err)
+	}
+	eval := resp.Msg.GetReport().GetCardano()
+	if eval == nil {
+		return nil, errors.New("received no cardano evaluation from EvalTx")
+	}
+	params, err := c.cardanoParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewEvalReport(eval, params), nil
+}
+
+// cardanoParams fetches the Cardano protocol parameters.
+func (c *Client) cardanoParams(ctx context.Context) (*chaincardano.PParams, error) {
+	resp, err := c.GetProtocolParametersWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protocol parameters: %w", err)
+	}
+	params := resp.Msg.GetValues().GetCardano()
+	if params == nil {
+		return nil, errors.New("received no cardano protocol parameters from ReadParams")
+	}
+	return params, nil
+}
+
+// scriptFee returns ceil(prices.Memory × memory + prices.Steps × steps).
+func scriptFee(prices *chaincardano.ExPrices, memory, steps uint64) uint64 {
+	fee := new(big.Rat).Mul(ratOf(prices.GetMemory()), new(big.Rat).SetUint64(memory))
+	fee.Add(fee, new(big.Rat).Mul(ratOf(prices.GetSteps()), new(big.Rat).SetUint64(steps)))
+	return ceilRat(fee)
+}
+
+// ratOf converts a RationalNumber, treating a zero denominator as zero.
+func ratOf(r *chaincardano.RationalNumber) *big.Rat {
+	if r.GetDenominator() == 0 {
+		return new(big.Rat)
+	}
+	return big.NewRat(int64(r.GetNumerator()), int64(r.GetDenominator()))
+}
+
+// ceilRat rounds a non-negative rational up to a uint64.
+func ceilRat(r *big.Rat) uint64 {
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q.Uint64()
+}
+
+func redeemerName(purpose chaincardano.RedeemerPurpose, index uint32) string {
+	name := strings.ToLower(strings.TrimPrefix(purpose.String(), "REDEEMER_PURPOSE_"))
+	return fmt.Sprintf("%s[%d]", name, index)
+}
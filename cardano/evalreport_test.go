@@ -0,0 +1,137 @@
+package cardano
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+)
+
+// evalTestParams uses the mainnet execution prices and limits.
+var evalTestParams = &chaincardano.PParams{
+	Prices: &chaincardano.ExPrices{
+		Memory: &chaincardano.RationalNumber{Numerator: 577, Denominator: 10000},
+		Steps:  &chaincardano.RationalNumber{Numerator: 721, Denominator: 10000000},
+	},
+	MaxExecutionUnitsPerTransaction: &chaincardano.ExUnits{
+		Memory: 14000000,
+		Steps:  10000000000,
+	},
+}
+
+func evalRedeemer(
+	purpose chaincardano.RedeemerPurpose,
+	index uint32,
+	memory, steps uint64,
+) *chaincardano.Redeemer {
+	return &chaincardano.Redeemer{
+		Purpose: purpose,
+		Index:   index,
+		ExUnits: &chaincardano.ExUnits{Memory: memory, Steps: steps},
+	}
+}
+
+func TestNewEvalReportComputesScriptFee(t *testing.T) {
+	report := NewEvalReport(&chaincardano.TxEval{
+		Redeemers: []*chaincardano.Redeemer{
+			evalRedeemer(chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_SPEND, 0, 1000000, 300000000),
+			evalRedeemer(chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_MINT, 1, 500000, 200000000),
+		},
+	}, evalTestParams)
+
+	if report.TotalMemory != 1500000 || report.TotalSteps != 500000000 {
+		t.Fatalf("totals = %d/%d, want 1500000/500000000", report.TotalMemory, report.TotalSteps)
+	}
+	// 1.5e6 × 0.0577 + 5e8 × 0.0000721 = 86550 + 36050
+	if report.ScriptFee != 122600 {
+		t.Fatalf("ScriptFee = %d, want 122600", report.ScriptFee)
+	}
+	if got := report.Redeemers[1].String(); got != "mint[1]: mem 500000, steps 200000000, fee 43270" {
+		t.Fatalf("redeemer = %q", got)
+	}
+	if !report.WithinLimits() || report.Err() != nil {
+		t.Fatalf("report unexpectedly failed: %v", report.Err())
+	}
+}
+
+func TestEvalReportErrReportsFailuresAndLimits(t *testing.T) {
+	report := NewEvalReport(&chaincardano.TxEval{
+		Redeemers: []*chaincardano.Redeemer{
+			evalRedeemer(chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_SPEND, 2, 15000000, 1),
+		},
+		Errors: []*chaincardano.EvalReport{{
+			Msg:     "validator returned false",
+			Purpose: chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_SPEND,
+			Index:   2,
+		}},
+	}, evalTestParams)
+
+	err := report.Err()
+	if !errors.Is(err, ErrScriptFailed) || !errors.Is(err, ErrExUnitsExceeded) {
+		t.Fatalf("Err = %v, want ErrScriptFailed and ErrExUnitsExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "spend[2]: validator returned false") {
+		t.Fatalf("Err = %q, want the attributed message", err)
+	}
+}
+
+func TestEvaluateTransactionReportUsesProtocolParameters(t *testing.T) {
+	client := newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return submitconnect.NewSubmitServiceHandler(&evalSubmitHandler{})
+		},
+		func() (string, http.Handler) {
+			return queryconnect.NewQueryServiceHandler(&paramsQueryHandler{params: evalTestParams})
+		},
+	)
+
+	report, err := client.EvaluateTransactionReport("84a0")
+	if err != nil {
+		t.Fatalf("EvaluateTransactionReport returned error: %v", err)
+	}
+	if report.ScriptFee != 57700+21630 || report.MaxMemory != 14000000 {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+type evalSubmitHandler struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+}
+
+func (evalSubmitHandler) EvalTx(
+	context.Context,
+	*connect.Request[submit.EvalTxRequest],
+) (*connect.Response[submit.EvalTxResponse], error) {
+	return connect.NewResponse(&submit.EvalTxResponse{
+		Report: &submit.AnyChainEval{Chain: &submit.AnyChainEval_Cardano{
+			Cardano: &chaincardano.TxEval{Redeemers: []*chaincardano.Redeemer{
+				evalRedeemer(chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_SPEND, 0, 1000000, 300000000),
+			}},
+		}},
+	}), nil
+}
+
+type paramsQueryHandler struct {
+	queryconnect.UnimplementedQueryServiceHandler
+	params *chaincardano.PParams
+}
+
+func (q *paramsQueryHandler) ReadParams(
+	context.Context,
+	*connect.Request[query.ReadParamsRequest],
+) (*connect.Response[query.ReadParamsResponse], error) {
+	return connect.NewResponse(&query.ReadParamsResponse{
+		Values: &query.AnyChainParams{Params: &query.AnyChainParams_Cardano{
+			Cardano: q.params,
+		}},
+	}), nil
+}
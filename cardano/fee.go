@@ -0,0 +1,215 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// refScriptTierSize is the size in bytes of each reference-script fee tier,
+// a Conway ledger constant rather than a protocol parameter.
+const refScriptTierSize = 25 * 1024
+
+// refScriptTierMultiplier is the factor by which the per-byte
+// reference-script price grows with each tier.
+var refScriptTierMultiplier = big.NewRat(6, 5)
+
+// FeeInputs are the transaction properties the minimum fee depends on.
+type FeeInputs struct {
+	// Size is the size of the complete, signed transaction CBOR in bytes,
+	// less the 1-byte is-valid flag of Alonzo and later transactions, which
+	// the ledger does not charge for.
+	Size uint64
+	// Memory and Steps are the execution units of all redeemers.
+	Memory uint64
+	Steps  uint64
+	// RefScriptSize is the total size of the reference scripts in the
+	// outputs spent or referenced by the transaction, counted once per
+	// input.
+	RefScriptSize uint64
+}
+
+// FeeBreakdown is a minimum fee split into its components, in lovelace.
+type FeeBreakdown struct {
+	// SizeFee is the linear fee: min fee coefficient × size + constant.
+	SizeFee uint64
+	// ScriptFee is the execution-unit fee, rounded up.
+	ScriptFee uint64
+	// RefScriptFee is the Conway tiered reference-script fee, rounded
+	// down.
+	RefScriptFee uint64
+	Total        uint64
+}
+
+// CalculateFee computes the Conway minimum fee for inputs under params.
+//
+// The reference-script fee charges MinFeeScriptRefCostPerByte for the first
+// 25 KiB, and 1.2 times the previous tier's price for each further 25 KiB.
+func CalculateFee(inputs FeeInputs, params *chaincardano.PParams) FeeBreakdown {
+	coefficient := bigIntUint64(params.GetMinFeeCoefficient())
+	constant := bigIntUint64(params.GetMinFeeConstant())
+	fee := FeeBreakdown{
+		SizeFee:      coefficient*inputs.Size + constant,
+		ScriptFee:    scriptFee(params.GetPrices(), inputs.Memory, inputs.Steps),
+		RefScriptFee: refScriptFee(params.GetMinFeeScriptRefCostPerByte(), inputs.RefScriptSize),
+	}
+	fee.Total = fee.SizeFee + fee.ScriptFee + fee.RefScriptFee
+	return fee
+}
+
+func refScriptFee(perByte *chaincardano.RationalNumber, size uint64) uint64 {
+	total := new(big.Rat)
+	price := ratOf(perByte)
+	for size > 0 {
+		chunk := min(size, refScriptTierSize)
+		total.Add(total, new(big.Rat).Mul(price, new(big.Rat).SetUint64(chunk)))
+		price = new(big.Rat).Mul(price, refScriptTierMultiplier)
+		size -= chunk
+	}
+	return new(big.Int).Quo(total.Num(), total.Denom()).Uint64()
+}
+
+// CalculateTxFee computes the minimum fee of a transaction under params.
+// txCbor is hex; its size and redeemer execution units are read from it.
+// refScriptSize is as in [FeeInputs]; use [Client.EstimateTransactionFee]
+// to have it resolved from the chain.
+//
+// The size counts the witnesses present, so estimate with a transaction
+// carrying its final witnesses (or same-sized placeholders). As on the
+// ledger, the is-valid flag is not counted.
+func CalculateTxFee(
+	txCbor string,
+	refScriptSize uint64,
+	params *chaincardano.PParams,
+) (FeeBreakdown, error) {
+	raw, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return FeeBreakdown{}, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	tx, err := decodeLedgerTransaction(raw)
+	if err != nil {
+		return FeeBreakdown{}, err
+	}
+	size, err := common.TxSizeForFee(tx)
+	if err != nil {
+		return FeeBreakdown{}, fmt.Errorf("failed to size transaction: %w", err)
+	}
+	// #nosec G115 -- sizes are never negative
+	inputs := FeeInputs{Size: uint64(size), RefScriptSize: refScriptSize}
+	if redeemers := tx.Witnesses().Redeemers(); redeemers != nil {
+		for _, value := range redeemers.Iter() {
+			// #nosec G115 -- execution units are never negative
+			inputs.Memory += uint64(value.ExUnits.Memory)
+			// #nosec G115 -- execution units are never negative
+			inputs.Steps += uint64(value.ExUnits.Steps)
+		}
+	}
+	return CalculateFee(inputs, params), nil
+}
+
+// EstimateTransactionFee calls [Client.EstimateTransactionFeeWithContext]
+// with a background context.
+func (c *Client) EstimateTransactionFee(txCbor string) (FeeBreakdown, error) {
+	return c.EstimateTransactionFeeWithContext(context.Background(), txCbor)
+}
+
+// EstimateTransactionFeeWithContext computes the minimum fee of a
+// transaction with [CalculateTxFee], using the current protocol parameters
+// and reference-script sizes resolved by reading the transaction's inputs
+// and reference inputs via Query.ReadUtxos.
+func (c *Client) EstimateTransactionFeeWithContext(
+	ctx context.Context,
+	txCbor string,
+) (FeeBreakdown, error) {
+	raw, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return FeeBreakdown{}, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	tx, err := decodeLedgerTransaction(raw)
+	if err != nil {
+		return FeeBreakdown{}, err
+	}
+	params, err := c.cardanoParams(ctx)
+	if err != nil {
+		return FeeBreakdown{}, err
+	}
+	refScriptSize, err := c.refScriptSize(ctx, tx)
+	if err != nil {
+		return FeeBreakdown{}, err
+	}
+	return CalculateTxFee(txCbor, refScriptSize, params)
+}
+
+// refScriptSize sums the reference scripts of the outputs a transaction
+// spends or references.
+func (c *Client) refScriptSize(ctx context.Context, tx ledger.Transaction) (uint64, error) {
+	var refs []*query.TxoRef
+	for _, input := range slices.Concat(tx.Inputs(), tx.ReferenceInputs()) {
+		refs = append(refs, &query.TxoRef{Hash: input.Id().Bytes(), Index: input.Index()})
+	}
+	if len(refs) == 0 {
+		return 0, nil
+	}
+	resp, err := c.GetUtxosByRefsWithContext(ctx, refs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read transaction inputs: %w", err)
+	}
+	items := make(map[string]*query.AnyUtxoData, len(resp.Msg.GetItems()))
+	for _, item := range resp.Msg.GetItems() {
+		ref := item.GetTxoRef()
+		items[inputKey(ref.GetHash(), ref.GetIndex())] = item
+	}
+	var total uint64
+	for _, ref := range refs {
+		item, ok := items[inputKey(ref.GetHash(), ref.GetIndex())]
+		if !ok {
+			return 0, fmt.Errorf("input %x#%d not found", ref.GetHash(), ref.GetIndex())
+		}
+		size, err := utxoRefScriptSize(item)
+		if err != nil {
+			return 0, fmt.Errorf("input %x#%d: %w", ref.GetHash(), ref.GetIndex(), err)
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// utxoRefScriptSize returns the size of an output's reference script, from
+// its CBOR when available and otherwise from the parsed Plutus script.
+func utxoRefScriptSize(item *query.AnyUtxoData) (uint64, error) {
+	native := item.GetNativeBytes()
+	if len(native) == 0 {
+		native = item.GetCardano().GetOriginalCbor()
+	}
+	if len(native) > 0 {
+		output, err := ledger.NewTransactionOutputFromCbor(native)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decode output: %w", err)
+		}
+		if script := output.ScriptRef(); script != nil {
+			return uint64(len(script.RawScriptBytes())), nil
+		}
+		return 0, nil
+	}
+	switch script := item.GetCardano().GetScript().GetScript().(type) {
+	case nil:
+		return 0, nil
+	case *chaincardano.Script_PlutusV1:
+		return uint64(len(script.PlutusV1)), nil
+	case *chaincardano.Script_PlutusV2:
+		return uint64(len(script.PlutusV2)), nil
+	case *chaincardano.Script_PlutusV3:
+		return uint64(len(script.PlutusV3)), nil
+	case *chaincardano.Script_PlutusV4:
+		return uint64(len(script.PlutusV4)), nil
+	default:
+		return 0, fmt.Errorf("cannot size a %T reference script without the output CBOR", script)
+	}
+}
@@ -0,0 +1,163 @@
+package cardano
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+)
+
+// mainnetFeeParams holds the mainnet fee parameters of the Conway era.
+var mainnetFeeParams = &chaincardano.PParams{
+	MinFeeCoefficient: &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: 44}},
+	MinFeeConstant:    &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: 155381}},
+	Prices:            evalTestParams.GetPrices(),
+	MinFeeScriptRefCostPerByte: &chaincardano.RationalNumber{
+		Numerator:   15,
+		Denominator: 1,
+	},
+}
+
+func TestCalculateFee(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs FeeInputs
+		want   FeeBreakdown
+	}{
+		{
+			// A typical two-input, two-output payment.
+			name:   "payment",
+			inputs: FeeInputs{Size: 297},
+			want:   FeeBreakdown{SizeFee: 168449, Total: 168449},
+		},
+		{
+			name:   "scripts",
+			inputs: FeeInputs{Size: 1000, Memory: 1500000, Steps: 500000000},
+			want:   FeeBreakdown{SizeFee: 199381, ScriptFee: 122600, Total: 321981},
+		},
+		{
+			// 25600 × 15 + 4400 × 18
+			name:   "reference scripts, two tiers",
+			inputs: FeeInputs{Size: 500, RefScriptSize: 30000},
+			want:   FeeBreakdown{SizeFee: 177381, RefScriptFee: 463200, Total: 640581},
+		},
+		{
+			// 25600 × 15 + 25600 × 18 + 8800 × 21.6
+			name:   "reference scripts, three tiers",
+			inputs: FeeInputs{RefScriptSize: 60000},
+			want:   FeeBreakdown{SizeFee: 155381, RefScriptFee: 1034880, Total: 1190261},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CalculateFee(test.inputs, mainnetFeeParams); got != test.want {
+				t.Fatalf("CalculateFee = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCalculateTxFeeMatchesMainnet(t *testing.T) {
+	// Babbage had no reference-script fee.
+	babbageParams := &chaincardano.PParams{
+		MinFeeCoefficient: mainnetFeeParams.GetMinFeeCoefficient(),
+		MinFeeConstant:    mainnetFeeParams.GetMinFeeConstant(),
+		Prices:            mainnetFeeParams.GetPrices(),
+	}
+	tests := []struct {
+		name   string
+		hash   string
+		params *chaincardano.PParams
+		want   FeeBreakdown
+		// paid is the fee in the transaction body, accepted on chain.
+		paid uint64
+	}{
+		{
+			// A payment in Conway block 27807a70…bf47 (slot 159835207);
+			// 844 bytes, 843 of them charged.
+			name:   "conway payment",
+			hash:   "d97fd8bbfcdc4e49531415b96101323b720c3438eeb0bf19c292506277217cae",
+			params: mainnetFeeParams,
+			want:   FeeBreakdown{SizeFee: 192473, Total: 192473},
+			paid:   192693,
+		},
+		{
+			// A script spend taking its validator from a reference input, in
+			// Babbage block db19fcfa…3f7d (slot 76204984); 866 bytes, 865 of
+			// them charged.
+			name:   "babbage reference script",
+			hash:   "5d212dcf471aa03d0b0ca800df01d90f17fe163ab2a50fcea4a13162cb8b6600",
+			params: babbageParams,
+			want:   FeeBreakdown{SizeFee: 193441, ScriptFee: 66860, Total: 260301},
+			paid:   260521,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			txCbor, err := os.ReadFile(filepath.Join("testdata", test.hash+".hex"))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+			fee, err := CalculateTxFee(strings.TrimSpace(string(txCbor)), 0, test.params)
+			if err != nil {
+				t.Fatalf("CalculateTxFee returned error: %v", err)
+			}
+			if fee != test.want {
+				t.Fatalf("CalculateTxFee = %+v, want %+v", fee, test.want)
+			}
+			// Both were built by a wallet padding the fee by 5 bytes' worth.
+			if test.paid-fee.Total != 220 {
+				t.Fatalf("paid fee %d exceeds the minimum %d by %d, want 220",
+					test.paid, fee.Total, test.paid-fee.Total)
+			}
+		})
+	}
+}
+
+func TestEstimateTransactionFeeResolvesReferenceScripts(t *testing.T) {
+	fakeQuery := &feeQueryHandler{
+		paramsQueryHandler: paramsQueryHandler{params: mainnetFeeParams},
+		items: []*query.AnyUtxoData{{
+			TxoRef: &query.TxoRef{Hash: mustDecodeHex(t, strings.Repeat("00", 32))},
+			ParsedState: &query.AnyUtxoData_Cardano{Cardano: &chaincardano.TxOutput{
+				Script: &chaincardano.Script{Script: &chaincardano.Script_PlutusV3{
+					PlutusV3: make([]byte, 1000),
+				}},
+			}},
+		}},
+	}
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return queryconnect.NewQueryServiceHandler(fakeQuery)
+	})
+
+	fee, err := client.EstimateTransactionFee(trackerTxCbor)
+	if err != nil {
+		t.Fatalf("EstimateTransactionFee returned error: %v", err)
+	}
+	// The is-valid flag is not charged.
+	size := uint64(len(trackerTxCbor)/2 - 1)
+	want := FeeBreakdown{SizeFee: 44*size + 155381, RefScriptFee: 15000}
+	want.Total = want.SizeFee + want.RefScriptFee
+	if fee != want {
+		t.Fatalf("fee = %+v, want %+v", fee, want)
+	}
+}
+
+type feeQueryHandler struct {
+	paramsQueryHandler
+	items []*query.AnyUtxoData
+}
+
+func (q *feeQueryHandler) ReadUtxos(
+	context.Context,
+	*connect.Request[query.ReadUtxosRequest],
+) (*connect.Response[query.ReadUtxosResponse], error) {
+	return connect.NewResponse(&query.ReadUtxosResponse{Items: q.items}), nil
+}
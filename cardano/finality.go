@@ -0,0 +1,184 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	sdk "github.com/utxorpc/go-sdk"
+)
+
+// ErrRollbackBeyondFinality is returned by [FinalityBuffer.Push] when the
+// chain rolls back past a block the buffer has already emitted as final.
+// This only happens if the buffer depth is smaller than the chain's real
+// security parameter.
+var ErrRollbackBeyondFinality = errors.New(
+	"rollback reached a block already emitted as final",
+)
+
+// ErrUnknownUndo is returned by [FinalityBuffer.Push] when the chain undoes
+// a block that is neither buffered nor already emitted, which means the
+// buffer and the stream disagree about the chain.
+var ErrUnknownUndo = errors.New("undo of a block the buffer never applied")
+
+// FinalityBuffer holds back the most recent blocks of a FollowTip stream
+// until they are buried under a configurable number of later blocks.
+// Blocks leave the buffer only once they can no longer be rolled back, so
+// consumers fed from it never observe an Undo.
+//
+// The buffer is not safe for concurrent use.
+type FinalityBuffer struct {
+	depth       int
+	window      []*sync.AnyChainBlock
+	lastEmitted *sync.BlockRef
+}
+
+// NewFinalityBuffer returns a [FinalityBuffer] that emits a block once depth
+// further blocks have been applied on top of it. On Cardano, depth should
+// be the security parameter k (2160 on mainnet).
+func NewFinalityBuffer(depth uint32) *FinalityBuffer {
+	return &FinalityBuffer{depth: int(depth)}
+}
+
+// NewFinalityBuffer calls [Client.NewFinalityBufferWithContext] with a
+// background context.
+func (c *Client) NewFinalityBuffer() (*FinalityBuffer, error) {
+	return c.NewFinalityBufferWithContext(context.Background())
+}
+
+// NewFinalityBufferWithContext returns a [FinalityBuffer] whose depth is the
+// security parameter from the server's genesis configuration.
+func (c *Client) NewFinalityBufferWithContext(
+	ctx context.Context,
+) (*FinalityBuffer, error) {
+	genesis, err := c.GetGenesisWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if genesis.GetSecurityParam() == 0 {
+		return nil, errors.New("genesis does not define a security parameter")
+	}
+	return NewFinalityBuffer(genesis.GetSecurityParam()), nil
+}
+
+// Depth returns the number of blocks that must be applied on top of a block
+// before it is emitted.
+func (b *FinalityBuffer) Depth() int {
+	return b.depth
+}
+
+// Volatile returns the buffered blocks that may still be rolled back,
+// oldest first. The returned slice is a copy; the blocks are shared.
+func (b *FinalityBuffer) Volatile() []*sync.AnyChainBlock {
+	return append([]*sync.AnyChainBlock(nil), b.window...)
+}
+
+// LastFinal returns the reference of the most recently emitted block, or nil
+// if nothing has been emitted yet.
+func (b *FinalityBuffer) LastFinal() *sync.BlockRef {
+	return b.lastEmitted
+}
+
+// Push feeds one FollowTip event into the buffer and returns the blocks that
+// became final as a result, oldest first. Undo and Reset events are absorbed
+// by dropping buffered blocks; if they reach a block that was already
+// emitted, Push returns [ErrRollbackBeyondFinality], and an Undo of a block
+// it never saw returns [ErrUnknownUndo]. Blocks without a header are
+// rejected.
+func (b *FinalityBuffer) Push(
+	resp *sync.FollowTipResponse,
+) ([]*sync.AnyChainBlock, error) {
+	switch action := resp.GetAction().(type) {
+	case *sync.FollowTipResponse_Apply:
+		if sdk.BlockRefOf(action.Apply) == nil {
+			return nil, errors.New("applied block has no header")
+		}
+		b.window = append(b.window, action.Apply)
+		return b.drain(), nil
+	case *sync.FollowTipResponse_Undo:
+		return nil, b.undo(action.Undo)
+	case *sync.FollowTipResponse_Reset_:
+		return nil, b.reset(action.Reset_)
+	default:
+		return nil, nil
+	}
+}
+
+// Follow returns a sequence of the final blocks delivered by stream. It
+// reads until the stream ends, the caller stops iterating, or an error
+// occurs; a stream or rollback error is yielded with a nil block and ends
+// the sequence. The caller remains responsible for closing stream.
+func (b *FinalityBuffer) Follow(
+	stream *connect.ServerStreamForClient[sync.FollowTipResponse],
+) iter.Seq2[*sync.AnyChainBlock, error] {
+	return func(yield func(*sync.AnyChainBlock, error) bool) {
+		for stream.Receive() {
+			final, err := b.Push(stream.Msg())
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, block := range final {
+				if !yield(block, nil) {
+					return
+				}
+			}
+		}
+		if err := stream.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func (b *FinalityBuffer) drain() []*sync.AnyChainBlock {
+	if len(b.window) <= b.depth {
+		return nil
+	}
+	count := len(b.window) - b.depth
+	final := append([]*sync.AnyChainBlock(nil), b.window[:count]...)
+	b.window = append(b.window[:0:0], b.window[count:]...)
+	b.lastEmitted = sdk.BlockRefOf(final[len(final)-1])
+	return final
+}
+
+func (b *FinalityBuffer) undo(block *sync.AnyChainBlock) error {
+	ref := sdk.BlockRefOf(block)
+	if ref == nil {
+		return errors.New("undone block has no header")
+	}
+	for i := len(b.window) - 1; i >= 0; i-- {
+		if bytes.Equal(sdk.BlockRefOf(b.window[i]).GetHash(), ref.GetHash()) {
+			b.window = b.window[:i]
+			return nil
+		}
+	}
+	if b.lastEmitted != nil && ref.GetSlot() <= b.lastEmitted.GetSlot() {
+		return fmt.Errorf(
+			"%w: undo of slot %d",
+			ErrRollbackBeyondFinality,
+			ref.GetSlot(),
+		)
+	}
+	return fmt.Errorf("%w: slot %d", ErrUnknownUndo, ref.GetSlot())
+}
+
+func (b *FinalityBuffer) reset(point *sync.BlockRef) error {
+	if b.lastEmitted != nil && point.GetSlot() < b.lastEmitted.GetSlot() {
+		return fmt.Errorf(
+			"%w: reset to slot %d",
+			ErrRollbackBeyondFinality,
+			point.GetSlot(),
+		)
+	}
+	for i, block := range b.window {
+		if sdk.BlockRefOf(block).GetSlot() > point.GetSlot() {
+			b.window = b.window[:i]
+			break
+		}
+	}
+	return nil
+}
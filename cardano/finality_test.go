@@ -0,0 +1,126 @@
+package cardano
+
+import (
+	"errors"
+	"testing"
+
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+)
+
+func TestFinalityBufferEmitsOnlyBuriedBlocks(t *testing.T) {
+	buffer := NewFinalityBuffer(2)
+
+	steps := []struct {
+		resp      *sync.FollowTipResponse
+		wantFinal []uint64
+	}{
+		{resp: applyBlock(1), wantFinal: nil},
+		{resp: applyBlock(2), wantFinal: nil},
+		{resp: applyBlock(3), wantFinal: []uint64{1}},
+		{resp: undoBlock(3), wantFinal: nil},
+		{resp: applyBlock(4), wantFinal: nil},
+		{resp: applyBlock(5), wantFinal: []uint64{2}},
+	}
+	for i, step := range steps {
+		final, err := buffer.Push(step.resp)
+		if err != nil {
+			t.Fatalf("step %d: Push returned error: %v", i, err)
+		}
+		if got := blockSlots(final); !equalSlots(got, step.wantFinal) {
+			t.Fatalf("step %d: final slots = %v, want %v", i, got, step.wantFinal)
+		}
+	}
+
+	if got := blockSlots(buffer.Volatile()); !equalSlots(got, []uint64{4, 5}) {
+		t.Fatalf("volatile slots = %v, want [4 5]", got)
+	}
+	if got := buffer.LastFinal().GetSlot(); got != 2 {
+		t.Fatalf("last final slot = %d, want 2", got)
+	}
+
+	_, err := buffer.Push(&sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Reset_{
+			Reset_: &sync.BlockRef{Slot: 1},
+		},
+	})
+	if !errors.Is(err, ErrRollbackBeyondFinality) {
+		t.Fatalf("reset past final block error = %v, want %v", err, ErrRollbackBeyondFinality)
+	}
+}
+
+func TestFinalityBufferRejectsUnknownUndoAndHeaderlessBlocks(t *testing.T) {
+	buffer := NewFinalityBuffer(2)
+	for _, slot := range []uint64{1, 2, 3} {
+		if _, err := buffer.Push(applyBlock(slot)); err != nil {
+			t.Fatalf("Push(%d) returned error: %v", slot, err)
+		}
+	}
+
+	if _, err := buffer.Push(undoBlock(7)); !errors.Is(err, ErrUnknownUndo) {
+		t.Fatalf("undo of an unseen block error = %v, want %v", err, ErrUnknownUndo)
+	}
+	if got := blockSlots(buffer.Volatile()); !equalSlots(got, []uint64{2, 3}) {
+		t.Fatalf("volatile slots = %v, want [2 3] after the rejected undo", got)
+	}
+
+	headerless := &sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Apply{Apply: &sync.AnyChainBlock{}},
+	}
+	if _, err := buffer.Push(headerless); err == nil {
+		t.Fatal("Push accepted an applied block without a header")
+	}
+	headerless.Action = &sync.FollowTipResponse_Undo{Undo: &sync.AnyChainBlock{}}
+	if _, err := buffer.Push(headerless); err == nil {
+		t.Fatal("Push accepted an undone block without a header")
+	}
+	if got := blockSlots(buffer.Volatile()); !equalSlots(got, []uint64{2, 3}) {
+		t.Fatalf("volatile slots = %v, want [2 3] after headerless blocks", got)
+	}
+}
+
+func chainBlock(slot uint64) *sync.AnyChainBlock {
+	return &sync.AnyChainBlock{
+		Chain: &sync.AnyChainBlock_Cardano{
+			Cardano: &chaincardano.Block{
+				Header: &chaincardano.BlockHeader{
+					Slot:   slot,
+					Hash:   []byte{byte(slot)},
+					Height: slot,
+				},
+			},
+		},
+	}
+}
+
+func applyBlock(slot uint64) *sync.FollowTipResponse {
+	return &sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Apply{Apply: chainBlock(slot)},
+	}
+}
+
+func undoBlock(slot uint64) *sync.FollowTipResponse {
+	return &sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Undo{Undo: chainBlock(slot)},
+	}
+}
+
+func blockSlots(blocks []*sync.AnyChainBlock) []uint64 {
+	slots := make([]uint64, 0, len(blocks))
+	for _, block := range blocks {
+		slots = append(slots, block.GetCardano().GetHeader().GetSlot())
+	}
+	return slots
+}
+
+func equalSlots(left, right []uint64) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
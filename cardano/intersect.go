@@ -0,0 +1,205 @@
+package cardano
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+)
+
+const blockHashSize = 32
+
+// ErrNoIntersection is returned by [Client.FindIntersect] when none of the
+// candidate points is on the server's chain.
+var ErrNoIntersection = errors.New("no candidate point is on the chain")
+
+// ChainPoint identifies a block by slot and hex-encoded block hash.
+type ChainPoint struct {
+	Slot uint64
+	Hash string
+}
+
+// IntersectResult reports the outcome of [Client.FindIntersect].
+type IntersectResult struct {
+	// Point is the candidate the server intersected at.
+	Point *sync.BlockRef
+	// Index is the position of Point in the candidate list passed to
+	// FindIntersect.
+	Index int
+	// RolledBack is true when the intersection is not the most recent
+	// (highest slot) candidate, i.e. the chain rolled back past the
+	// caller's stored tip.
+	RolledBack bool
+}
+
+// ExponentialPoints picks intersect candidates from points (ordered newest
+// first) at exponentially growing distances: indexes 0, 1, 2, 4, 8, ...
+// The oldest point is always included. The result is suitable for
+// [Client.FindIntersect] when only a long list of recent points is stored.
+func ExponentialPoints(points []ChainPoint) []ChainPoint {
+	if len(points) == 0 {
+		return nil
+	}
+	picked := []ChainPoint{points[0]}
+	for step := 1; step < len(points); step *= 2 {
+		picked = append(picked, points[step])
+	}
+	if last := points[len(points)-1]; picked[len(picked)-1] != last {
+		picked = append(picked, last)
+	}
+	return picked
+}
+
+// FindIntersect calls [Client.FindIntersectWithContext] with a background
+// context.
+func (c *Client) FindIntersect(points []ChainPoint) (*IntersectResult, error) {
+	return c.FindIntersectWithContext(context.Background(), points)
+}
+
+// FindIntersectWithContext asks the server which of the candidate points is
+// the most recent one still on its chain.
+//
+// Every candidate must carry a 32-byte hex block hash; malformed candidates
+// are returned as errors rather than dropped. The server is first asked via
+// FollowTip, whose opening Reset event names the intersection. Servers that
+// do not open with a Reset naming a candidate are probed with FetchBlock,
+// newest candidate first. Returns [ErrNoIntersection] when no candidate is
+// found.
+func (c *Client) FindIntersectWithContext(
+	ctx context.Context,
+	points []ChainPoint,
+) (*IntersectResult, error) {
+	if len(points) == 0 {
+		return nil, errors.New("no intersect candidates provided")
+	}
+	refs := make([]*sync.BlockRef, 0, len(points))
+	for i, point := range points {
+		hash, err := hex.DecodeString(point.Hash)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to decode block hash of candidate %d: %w",
+				i,
+				err,
+			)
+		}
+		if len(hash) != blockHashSize {
+			return nil, fmt.Errorf(
+				"block hash of candidate %d is %d bytes, want %d",
+				i,
+				len(hash),
+				blockHashSize,
+			)
+		}
+		refs = append(refs, &sync.BlockRef{Slot: point.Slot, Hash: hash})
+	}
+
+	order := make([]int, len(refs))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(refs[b].GetSlot(), refs[a].GetSlot())
+	})
+	sorted := make([]*sync.BlockRef, 0, len(refs))
+	for _, i := range order {
+		sorted = append(sorted, refs[i])
+	}
+
+	found, err := c.intersectViaFollowTip(ctx, sorted)
+	if err != nil {
+		return nil, err
+	}
+	rank := candidateRank(sorted, found)
+	if rank < 0 {
+		found, err = c.intersectViaFetchBlock(ctx, sorted)
+		if err != nil {
+			return nil, err
+		}
+		rank = candidateRank(sorted, found)
+	}
+	if rank < 0 {
+		return nil, ErrNoIntersection
+	}
+	return &IntersectResult{
+		Point:      sorted[rank],
+		Index:      order[rank],
+		RolledBack: rank > 0,
+	}, nil
+}
+
+// candidateRank returns the position of point in refs, or -1.
+func candidateRank(refs []*sync.BlockRef, point *sync.BlockRef) int {
+	if point == nil {
+		return -1
+	}
+	return slices.IndexFunc(refs, func(ref *sync.BlockRef) bool {
+		return ref.GetSlot() == point.GetSlot() &&
+			bytes.Equal(ref.GetHash(), point.GetHash())
+	})
+}
+
+// intersectViaFollowTip returns the point named by the stream's opening
+// Reset, or nil if the server opened with something else.
+func (c *Client) intersectViaFollowTip(
+	ctx context.Context,
+	refs []*sync.BlockRef,
+) (*sync.BlockRef, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.WatchBlocksByRefWithContext(
+		ctx,
+		&sync.FollowTipRequest{Intersect: refs},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	if !stream.Receive() {
+		err := stream.Err()
+		switch connect.CodeOf(err) {
+		case connect.CodeNotFound:
+			return nil, ErrNoIntersection
+		case connect.CodeUnimplemented:
+			return nil, nil
+		default:
+			return nil, err
+		}
+	}
+	return stream.Msg().GetReset_(), nil
+}
+
+// intersectViaFetchBlock returns the first ref the server can fetch.
+func (c *Client) intersectViaFetchBlock(
+	ctx context.Context,
+	refs []*sync.BlockRef,
+) (*sync.BlockRef, error) {
+	for _, ref := range refs {
+		resp, err := c.GetBlockByRefWithContext(
+			ctx,
+			&sync.FetchBlockRequest{Ref: []*sync.BlockRef{ref}},
+		)
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range resp.Msg.GetBlock() {
+			if bytes.Equal(
+				block.GetCardano().GetHeader().GetHash(),
+				ref.GetHash(),
+			) {
+				return ref, nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,87 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync/syncconnect"
+)
+
+func TestFindIntersectReportsRollbackPastStoredTip(t *testing.T) {
+	stored := strings.Repeat("aa", blockHashSize)
+	older := strings.Repeat("bb", blockHashSize)
+	olderHash, _ := hex.DecodeString(older)
+
+	fakeSync := &intersectSyncHandler{
+		reset: &sync.BlockRef{Slot: 100, Hash: olderHash},
+	}
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return syncconnect.NewSyncServiceHandler(fakeSync)
+	})
+
+	result, err := client.FindIntersectWithContext(
+		context.Background(),
+		[]ChainPoint{
+			{Slot: 100, Hash: older},
+			{Slot: 200, Hash: stored},
+		},
+	)
+	if err != nil {
+		t.Fatalf("FindIntersectWithContext returned error: %v", err)
+	}
+	if result.Index != 0 {
+		t.Fatalf("intersect index = %d, want 0", result.Index)
+	}
+	if !result.RolledBack {
+		t.Fatal("RolledBack = false, want true")
+	}
+	if got := fakeSync.intersect; len(got) != 2 || got[0].GetSlot() != 200 {
+		t.Fatalf("FollowTip intersect = %v, want newest candidate first", got)
+	}
+}
+
+func TestFindIntersectRejectsInvalidCandidates(t *testing.T) {
+	client := NewClient()
+
+	tests := []struct {
+		name  string
+		point ChainPoint
+	}{
+		{name: "non-hex", point: ChainPoint{Slot: 1, Hash: "not-hex"}},
+		{name: "short hash", point: ChainPoint{Slot: 1, Hash: "abcd"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := client.FindIntersect([]ChainPoint{test.point})
+			if err == nil {
+				t.Fatal("FindIntersect returned nil error for invalid candidate")
+			}
+		})
+	}
+
+	if _, err := client.GetBlockByRef("not-hex", 1); err == nil {
+		t.Fatal("GetBlockByRef returned nil error for invalid hash")
+	}
+}
+
+type intersectSyncHandler struct {
+	syncconnect.UnimplementedSyncServiceHandler
+	reset     *sync.BlockRef
+	intersect []*sync.BlockRef
+}
+
+func (s *intersectSyncHandler) FollowTip(
+	_ context.Context,
+	req *connect.Request[sync.FollowTipRequest],
+	stream *connect.ServerStream[sync.FollowTipResponse],
+) error {
+	s.intersect = req.Msg.GetIntersect()
+	return stream.Send(&sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Reset_{Reset_: s.reset},
+	})
+}
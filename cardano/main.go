@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
 	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
 	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
 	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
@@ -21,6 +22,7 @@ import (
 // that build the appropriate request types and decode common input formats.
 type Client struct {
 	UtxorpcClient *sdk.UtxorpcClient
+	network       networkCache
 }
 
 // NewClient constructs a Cardano [Client] backed by a fresh
@@ -48,6 +50,29 @@ func (c *Client) GetProtocolParametersWithContext(
 	return c.UtxorpcClient.Query.ReadParams(ctx, req)
 }
 
+// GetGenesis calls [Client.GetGenesisWithContext] with a background context.
+func (c *Client) GetGenesis() (*chaincardano.Genesis, error) {
+	return c.GetGenesisWithContext(context.Background())
+}
+
+// GetGenesisWithContext fetches the Cardano genesis configuration via
+// Query.ReadGenesis. Returns an error if the server does not return a
+// Cardano genesis.
+func (c *Client) GetGenesisWithContext(
+	ctx context.Context,
+) (*chaincardano.Genesis, error) {
+	req := connect.NewRequest(&query.ReadGenesisRequest{})
+	resp, err := c.UtxorpcClient.ReadGenesisWithContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis: %w", err)
+	}
+	genesis := resp.Msg.GetCardano()
+	if genesis == nil {
+		return nil, errors.New("received no cardano genesis from ReadGenesis")
+	}
+	return genesis, nil
+}
+
 // GetUtxoByRef calls [Client.GetUtxoByRefWithContext] with a background context.
 func (c *Client) GetUtxoByRef(
 	txHashStr string,
@@ -99,7 +124,7 @@ func (c *Client) EvaluateTransaction(
 	// Decode the transaction data from hex
 	txRawBytes, err := hex.DecodeString(txCbor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode transaction hash: %w", err)
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
 	}
 
 	// Create a EvalTxRequest with the transaction data
@@ -177,17 +202,24 @@ func (c *Client) GetUtxosByAddress(
 	)
 }
 
-// GetUtxosByAddressWithContext searches for UTxOs locked at an exact Cardano
-// address via Query.SearchUtxos. The address must be supplied as raw bytes;
-// bech32 strings must be decoded by the caller. By default, the first page of
-// up to 100 results is returned. Use SearchOption values to configure the page,
-// or [Client.GetUtxosByAddressPages] to iterate all pages.
+// GetUtxosByAddressWithContext searches for UTxOs at a Cardano address via
+// Query.SearchUtxos. The address is supplied as raw bytes; use
+// [ParseAddress] for bech32, base58 or hex strings. Stake addresses are
+// rejected; use [Client.GetUtxosByStakeCredential] for the addresses
+// delegating to one. When the server's genesis names its network, the
+// address must be on it or the search fails with [ErrNetworkMismatch]. By default, the first page of up to 100 results is
+// returned. Use SearchOption values to configure the page, or
+// [Client.GetUtxosByAddressPages] to iterate all pages.
 func (c *Client) GetUtxosByAddressWithContext(
 	ctx context.Context,
 	address []byte,
 	options ...SearchOption,
 ) (*connect.Response[query.SearchUtxosResponse], error) {
-	queryReq := newAddressSearchRequest(address, options...)
+	pattern, err := c.addressPattern(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	queryReq := newAddressSearchRequest(pattern, options...)
 	req := connect.NewRequest(queryReq)
 	return c.UtxorpcClient.SearchUtxosWithContext(ctx, req)
 }
@@ -210,11 +242,13 @@ func (c *Client) GetUtxosByAddressWithAsset(
 }
 
 // GetUtxosByAddressWithAssetWithContext searches for UTxOs at the given
-// address that hold a matching native asset. policyIdBytes and assetNameBytes
-// are raw bytes; either may be empty to widen the match (policy-only,
-// asset-name-only, or both empty for any UTxO at the address). By default, the
-// first page of up to 100 results is returned. Use SearchOption values to
-// configure the page.
+// address, accepted as in [Client.GetUtxosByAddressWithContext], that hold a
+// matching native asset. policyIdBytes and assetNameBytes are raw bytes;
+// either may be empty to widen the match (policy-only, asset-name-only, or
+// both empty for any UTxO at the address). To name the asset by a string
+// or a CIP-14 fingerprint, use [Client.GetUtxosByAddressWithAssetIDWithContext].
+// By default, the first page of up to 100 results is returned. Use
+// SearchOption values to configure the page.
 func (c *Client) GetUtxosByAddressWithAssetWithContext(
 	ctx context.Context,
 	addressBytes []byte,
@@ -222,13 +256,119 @@ func (c *Client) GetUtxosByAddressWithAssetWithContext(
 	assetNameBytes []byte,
 	options ...SearchOption,
 ) (*connect.Response[query.SearchUtxosResponse], error) {
-	queryReq := newAddressAssetSearchRequest(
+	return c.GetUtxosByAddressWithAssetIDWithContext(
+		ctx,
 		addressBytes,
-		policyIdBytes,
-		assetNameBytes,
+		AssetID{PolicyID: policyIdBytes, Name: assetNameBytes},
+		options...,
+	)
+}
+
+// GetUtxosByAddressWithAssetID calls
+// [Client.GetUtxosByAddressWithAssetIDWithContext] with a background
+// context.
+func (c *Client) GetUtxosByAddressWithAssetID(
+	addressBytes []byte,
+	asset AssetID,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	return c.GetUtxosByAddressWithAssetIDWithContext(
+		context.Background(),
+		addressBytes,
+		asset,
 		options...,
 	)
+}
+
+// GetUtxosByAddressWithAssetIDWithContext searches for UTxOs at the given
+// address, accepted as in [Client.GetUtxosByAddressWithContext], that hold
+// the asset, as parsed by [ParseAssetID] or [TextAssetID]. A zero asset
+// matches any UTxO at the address. A fingerprint is matched against the
+// address's UTxOs on the client, so a page may hold fewer items than
+// requested. By default, the first page of up to 100 results is returned.
+// Use SearchOption values to configure the page.
+func (c *Client) GetUtxosByAddressWithAssetIDWithContext(
+	ctx context.Context,
+	addressBytes []byte,
+	asset AssetID,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	pattern, err := c.addressPattern(ctx, addressBytes)
+	if err != nil {
+		return nil, err
+	}
+	queryReq := newAddressAssetSearchRequest(pattern, asset, options...)
 	req := connect.NewRequest(queryReq)
+	resp, err := c.UtxorpcClient.SearchUtxosWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	asset.filter(resp.Msg)
+	return resp, nil
+}
+
+// GetUtxosByPaymentCredential calls
+// [Client.GetUtxosByPaymentCredentialWithContext] with a background context.
+func (c *Client) GetUtxosByPaymentCredential(
+	credential string,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	return c.GetUtxosByPaymentCredentialWithContext(
+		context.Background(),
+		credential,
+		options...,
+	)
+}
+
+// GetUtxosByPaymentCredentialWithContext searches for UTxOs at every
+// address whose payment part is the given credential, whatever its
+// delegation part. The credential is a payment key hash or a script hash,
+// given as hex, bech32 (addr_vkh, script) or an address whose payment part
+// is used; an address must be on the server's network. By default, the
+// first page of up to 100 results is returned. Use SearchOption values to
+// configure the page.
+func (c *Client) GetUtxosByPaymentCredentialWithContext(
+	ctx context.Context,
+	credential string,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	pattern, err := c.credentialPattern(ctx, credential, false)
+	if err != nil {
+		return nil, err
+	}
+	req := connect.NewRequest(newAddressSearchRequest(pattern, options...))
+	return c.UtxorpcClient.SearchUtxosWithContext(ctx, req)
+}
+
+// GetUtxosByStakeCredential calls
+// [Client.GetUtxosByStakeCredentialWithContext] with a background context.
+func (c *Client) GetUtxosByStakeCredential(
+	credential string,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	return c.GetUtxosByStakeCredentialWithContext(
+		context.Background(),
+		credential,
+		options...,
+	)
+}
+
+// GetUtxosByStakeCredentialWithContext searches for UTxOs at every address
+// delegating to the given stake credential, given as hex, bech32
+// (stake_vkh, script), a stake address or an address whose delegation part
+// is used; an address must be on the server's network. By default, the
+// first page of up to 100 results is returned. Use SearchOption values to
+// configure the page.
+func (c *Client) GetUtxosByStakeCredentialWithContext(
+	ctx context.Context,
+	credential string,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	pattern, err := c.credentialPattern(ctx, credential, true)
+	if err != nil {
+		return nil, err
+	}
+	req := connect.NewRequest(newAddressSearchRequest(pattern, options...))
 	return c.UtxorpcClient.SearchUtxosWithContext(ctx, req)
 }
 
@@ -249,24 +389,57 @@ func (c *Client) GetUtxosByAsset(
 // GetUtxosByAssetWithContext searches for UTxOs holding a native asset
 // across all addresses. policyIdBytes and assetNameBytes are raw bytes; at
 // least one must be non-nil — passing nil for both returns an error.
-// By default, the first page of up to 100 results is returned. Use SearchOption
-// values to configure the page.
+// To name the asset by a string or a CIP-14 fingerprint, use
+// [Client.GetUtxosByAssetIDWithContext]. By default, the first page of up to
+// 100 results is returned. Use SearchOption values to configure the page.
 func (c *Client) GetUtxosByAssetWithContext(
 	ctx context.Context,
 	policyIdBytes []byte,
 	assetNameBytes []byte,
 	options ...SearchOption,
 ) (*connect.Response[query.SearchUtxosResponse], error) {
-	queryReq, err := newAssetSearchRequest(
-		policyIdBytes,
-		assetNameBytes,
+	return c.GetUtxosByAssetIDWithContext(
+		ctx,
+		AssetID{PolicyID: policyIdBytes, Name: assetNameBytes},
 		options...,
 	)
+}
+
+// GetUtxosByAssetID calls [Client.GetUtxosByAssetIDWithContext] with a
+// background context.
+func (c *Client) GetUtxosByAssetID(
+	asset AssetID,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	return c.GetUtxosByAssetIDWithContext(context.Background(), asset, options...)
+}
+
+// GetUtxosByAssetIDWithContext searches for UTxOs holding the asset, as
+// parsed by [ParseAssetID] or [TextAssetID], across all addresses. The asset
+// must have a policy ID or a name.
+//
+// A fingerprint is a hash that cannot be turned back into a policy ID and
+// name, so the server cannot search by it: it is matched against the UTxOs
+// of the asset's policy on the client, so a page may hold fewer items than
+// requested. A fingerprint on its own is an error; see [AssetFingerprint] to
+// compute one for display. By default, the first page of up to 100 results
+// is returned. Use SearchOption values to configure the page.
+func (c *Client) GetUtxosByAssetIDWithContext(
+	ctx context.Context,
+	asset AssetID,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	queryReq, err := newAssetSearchRequest(asset, options...)
 	if err != nil {
 		return nil, err
 	}
 	req := connect.NewRequest(queryReq)
-	return c.UtxorpcClient.SearchUtxosWithContext(ctx, req)
+	resp, err := c.UtxorpcClient.SearchUtxosWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	asset.filter(resp.Msg)
+	return resp, nil
 }
 
 // SubmitTransaction broadcasts a signed transaction. txCbor is the full
@@ -282,7 +455,7 @@ func (c *Client) SubmitTransaction(
 	// Decode the transaction data from hex
 	txRawBytes, err := hex.DecodeString(txCbor)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode transaction hash: %w", err)
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
 	}
 
 	// Create a SubmitTxRequest with the transaction data
@@ -372,14 +545,20 @@ func (c *Client) WatchMempoolTransactionsWithContext(ctx context.Context) (
 	return c.UtxorpcClient.Submit.WatchMempool(ctx, req)
 }
 
-func syncIntersect(blockHashStr string, blockIndex int64) []*sync.BlockRef {
-	var intersect []*sync.BlockRef
+func syncIntersect(
+	blockHashStr string,
+	blockIndex int64,
+) ([]*sync.BlockRef, error) {
 	// Construct the BlockRef based on the provided parameters
 	blockRef := &sync.BlockRef{}
 	if blockHashStr != "" {
 		hash, err := hex.DecodeString(blockHashStr)
 		if err != nil {
-			return nil
+			return nil, fmt.Errorf(
+				"failed to decode block hash %s: %w",
+				blockHashStr,
+				err,
+			)
 		}
 		blockRef.Hash = hash
 	}
@@ -389,22 +568,26 @@ func syncIntersect(blockHashStr string, blockIndex int64) []*sync.BlockRef {
 		blockRef.Slot = uint64(blockIndex)
 	}
 	// Only add blockRef to intersect if at least one of blockHashStr or blockIndex is provided
-	if blockHashStr != "" || blockIndex > -1 {
-		intersect = []*sync.BlockRef{blockRef}
+	if blockHashStr == "" && blockIndex < 0 {
+		return nil, nil
 	}
-	return intersect
+	return []*sync.BlockRef{blockRef}, nil
 }
 
 // GetBlockByRef fetches a block via Sync.FetchBlock. blockHashStr is a hex
 // block hash (empty string to omit) and blockIndex is the slot (-1 to omit).
 // If both are omitted the request carries no intersect and the server's
-// default behavior applies.
+// default behavior applies. An invalid hex hash is returned as an error.
 func (c *Client) GetBlockByRef(
 	blockHashStr string,
 	blockIndex int64,
 ) (*connect.Response[sync.FetchBlockResponse], error) {
 	ctx := context.Background()
-	req := &sync.FetchBlockRequest{Ref: syncIntersect(blockHashStr, blockIndex)}
+	refs, err := syncIntersect(blockHashStr, blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	req := &sync.FetchBlockRequest{Ref: refs}
 	return c.GetBlockByRefWithContext(ctx, req)
 }
 
@@ -422,15 +605,18 @@ func (c *Client) GetBlockByRefWithContext(
 // WatchBlocksByRef opens a server stream of chain-tip events
 // (Apply / Undo / Reset) starting from the given intersect point.
 // blockHashStr is a hex block hash (empty string to omit) and blockIndex is
-// the slot (-1 to omit). The caller must close the returned stream.
+// the slot (-1 to omit). An invalid hex hash is returned as an error. The
+// caller must close the returned stream.
 func (c *Client) WatchBlocksByRef(
 	blockHashStr string,
 	blockIndex int64,
 ) (*connect.ServerStreamForClient[sync.FollowTipResponse], error) {
 	ctx := context.Background()
-	req := &sync.FollowTipRequest{
-		Intersect: syncIntersect(blockHashStr, blockIndex),
+	intersect, err := syncIntersect(blockHashStr, blockIndex)
+	if err != nil {
+		return nil, err
 	}
+	req := &sync.FollowTipRequest{Intersect: intersect}
 	return c.WatchBlocksByRefWithContext(ctx, req)
 }
 
@@ -517,41 +703,39 @@ func (c *Client) ReadBlockWithContext(
 	}
 }
 
-func watchIntersect(blockHashStr string, blockIndex int64) []*watch.BlockRef {
-	var intersect []*watch.BlockRef
-	// Construct the BlockRef based on the provided parameters
-	blockRef := &watch.BlockRef{}
-	if blockHashStr != "" {
-		hash, err := hex.DecodeString(blockHashStr)
-		if err != nil {
-			return nil
-		}
-		blockRef.Hash = hash
-	}
-	// We assume blockIndex can be 0 or any positive number, and won't overflow
-	// #nosec G115
-	if blockIndex > -1 {
-		blockRef.Slot = uint64(blockIndex)
+func watchIntersect(
+	blockHashStr string,
+	blockIndex int64,
+) ([]*watch.BlockRef, error) {
+	refs, err := syncIntersect(blockHashStr, blockIndex)
+	if err != nil {
+		return nil, err
 	}
-	// Only add blockRef to intersect if at least one of blockHashStr or blockIndex is provided
-	if blockHashStr != "" || blockIndex > -1 {
-		intersect = []*watch.BlockRef{blockRef}
+	intersect := make([]*watch.BlockRef, 0, len(refs))
+	for _, ref := range refs {
+		intersect = append(intersect, &watch.BlockRef{
+			Slot: ref.GetSlot(),
+			Hash: ref.GetHash(),
+		})
 	}
-	return intersect
+	return intersect, nil
 }
 
 // WatchTransaction opens a server stream of transaction events via
 // Watch.WatchTx, starting from the given intersect point. blockHashStr is a
 // hex block hash (empty string to omit) and blockIndex is the slot (-1 to
-// omit). The caller must close the returned stream.
+// omit). An invalid hex hash is returned as an error. The caller must close
+// the returned stream.
 func (c *Client) WatchTransaction(
 	blockHashStr string,
 	blockIndex int64,
 ) (*connect.ServerStreamForClient[watch.WatchTxResponse], error) {
 	ctx := context.Background()
-	req := &watch.WatchTxRequest{
-		Intersect: watchIntersect(blockHashStr, blockIndex),
+	intersect, err := watchIntersect(blockHashStr, blockIndex)
+	if err != nil {
+		return nil, err
 	}
+	req := &watch.WatchTxRequest{Intersect: intersect}
 	return c.WatchTransactionWithContext(ctx, req)
 }
 
@@ -3,11 +3,19 @@ package cardano
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"connectrpc.com/connect"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
 	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
 	sdk "github.com/utxorpc/go-sdk"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/fieldmaskpb"
 )
 
@@ -123,6 +131,8 @@ func slicesEqual(left, right []string) bool {
 type recordingQueryClient struct {
 	readUtxosReq   *connect.Request[query.ReadUtxosRequest]
 	searchUtxosReq *connect.Request[query.SearchUtxosRequest]
+	genesis        *chaincardano.Genesis
+	genesisReads   int
 }
 
 func (*recordingQueryClient) ReadParams(
@@ -162,11 +172,16 @@ func (*recordingQueryClient) ReadTx(
 	return connect.NewResponse(&query.ReadTxResponse{}), nil
 }
 
-func (*recordingQueryClient) ReadGenesis(
+func (r *recordingQueryClient) ReadGenesis(
 	context.Context,
 	*connect.Request[query.ReadGenesisRequest],
 ) (*connect.Response[query.ReadGenesisResponse], error) {
-	return connect.NewResponse(&query.ReadGenesisResponse{}), nil
+	r.genesisReads++
+	resp := &query.ReadGenesisResponse{}
+	if r.genesis != nil {
+		resp.Config = &query.ReadGenesisResponse_Cardano{Cardano: r.genesis}
+	}
+	return connect.NewResponse(resp), nil
 }
 
 func (*recordingQueryClient) ReadEraSummary(
@@ -184,3 +199,222 @@ func (*recordingQueryClient) ReadState(
 }
 
 var _ sdk.QueryServiceClient = (*recordingQueryClient)(nil)
+
+// newTestServerClient starts an h2c test server for generated Connect
+// handlers and returns a Cardano client pointed at it.
+func newTestServerClient(
+	t *testing.T,
+	handlers ...func() (string, http.Handler),
+) *Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	for _, handler := range handlers {
+		mux.Handle(handler())
+	}
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return NewClient(sdk.WithBaseUrl(server.URL))
+}
+
+func TestGetUtxosByAddressAcceptsAddressForms(t *testing.T) {
+	fakeQuery := &recordingQueryClient{genesis: &chaincardano.Genesis{NetworkId: "Mainnet"}}
+	client := NewClient(sdk.WithBaseUrl("http://example.test"))
+	client.UtxorpcClient.Query = fakeQuery
+	address, raw := testAddress(t, 0x11, 0x22)
+
+	tests := []struct {
+		name    string
+		address string
+		want    *chaincardano.AddressPattern
+	}{
+		{name: "bech32", address: address, want: &chaincardano.AddressPattern{ExactAddress: raw}},
+		{name: "hex", address: hex.EncodeToString(raw), want: &chaincardano.AddressPattern{ExactAddress: raw}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := ParseAddress(tt.address)
+			if err != nil {
+				t.Fatalf("ParseAddress returned error: %v", err)
+			}
+			if _, err := client.GetUtxosByAddress(raw); err != nil {
+				t.Fatalf("GetUtxosByAddress returned error: %v", err)
+			}
+			got := fakeQuery.searchUtxosReq.Msg.GetPredicate().GetMatch().GetCardano().GetAddress()
+			if !proto.Equal(got, tt.want) {
+				t.Fatalf("address pattern = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if fakeQuery.genesisReads != 1 {
+		t.Fatalf("ReadGenesis called %d times, want 1", fakeQuery.genesisReads)
+	}
+	for _, invalid := range []string{"", "addr1invalid", "0102"} {
+		if _, err := ParseAddress(invalid); err == nil {
+			t.Fatalf("ParseAddress(%q) returned no error", invalid)
+		}
+	}
+
+	// A stake address holds no UTxOs; searching it is an error rather than
+	// a search of its delegation part.
+	stake, err := ParseAddress(testStakeAddress(t, common.AddressNetworkMainnet, 0x22))
+	if err != nil {
+		t.Fatalf("ParseAddress returned error for a stake address: %v", err)
+	}
+	fakeQuery.searchUtxosReq = nil
+	if _, err := client.GetUtxosByAddress(stake); err == nil {
+		t.Fatal("GetUtxosByAddress accepted a stake address")
+	}
+	if fakeQuery.searchUtxosReq != nil {
+		t.Fatal("SearchUtxos was called for a stake address")
+	}
+}
+
+func TestAddressSearchesCheckNetwork(t *testing.T) {
+	fakeQuery := &recordingQueryClient{genesis: &chaincardano.Genesis{NetworkMagic: 2}}
+	client := NewClient(sdk.WithBaseUrl("http://example.test"))
+	client.UtxorpcClient.Query = fakeQuery
+	address, raw := testAddress(t, 0x11, 0x22)
+
+	if _, err := client.GetUtxosByAddress(raw); !errors.Is(err, ErrNetworkMismatch) {
+		t.Fatalf("GetUtxosByAddress error = %v, want ErrNetworkMismatch", err)
+	}
+	if _, err := client.GetUtxosByAddressWithAsset(raw, nil, nil); !errors.Is(err, ErrNetworkMismatch) {
+		t.Fatalf("GetUtxosByAddressWithAsset error = %v, want ErrNetworkMismatch", err)
+	}
+	if _, err := client.GetUtxosByStakeCredential(address); !errors.Is(err, ErrNetworkMismatch) {
+		t.Fatalf("GetUtxosByStakeCredential error = %v, want ErrNetworkMismatch", err)
+	}
+	for _, err := range client.GetUtxosByAddressPages(raw) {
+		if !errors.Is(err, ErrNetworkMismatch) {
+			t.Fatalf("GetUtxosByAddressPages error = %v, want ErrNetworkMismatch", err)
+		}
+	}
+	if fakeQuery.searchUtxosReq != nil {
+		t.Fatal("SearchUtxos was called for an address on another network")
+	}
+
+	testnetAddr, err := common.NewAddressFromParts(
+		common.AddressTypeKeyKey,
+		common.AddressNetworkTestnet,
+		bytes.Repeat([]byte{0x11}, credentialHashSize),
+		bytes.Repeat([]byte{0x22}, credentialHashSize),
+	)
+	if err != nil {
+		t.Fatalf("NewAddressFromParts returned error: %v", err)
+	}
+	testnet, err := testnetAddr.Bytes()
+	if err != nil {
+		t.Fatalf("Address.Bytes returned error: %v", err)
+	}
+	if _, err := client.GetUtxosByAddress(testnet); err != nil {
+		t.Fatalf("GetUtxosByAddress returned error for a testnet address: %v", err)
+	}
+}
+
+func TestGetUtxosByCredential(t *testing.T) {
+	fakeQuery := &recordingQueryClient{}
+	client := NewClient(sdk.WithBaseUrl("http://example.test"))
+	client.UtxorpcClient.Query = fakeQuery
+	address, _ := testAddress(t, 0x11, 0x22)
+	payment := bytes.Repeat([]byte{0x11}, credentialHashSize)
+	stake := bytes.Repeat([]byte{0x22}, credentialHashSize)
+	stakeVkh := bech32Credential(t, "stake_vkh", stake)
+
+	tests := []struct {
+		name   string
+		search func() error
+		want   *chaincardano.AddressPattern
+	}{
+		{
+			name: "payment key hash",
+			search: func() error {
+				_, err := client.GetUtxosByPaymentCredential(hex.EncodeToString(payment))
+				return err
+			},
+			want: &chaincardano.AddressPattern{PaymentPart: payment},
+		},
+		{
+			name: "payment part of address",
+			search: func() error {
+				_, err := client.GetUtxosByPaymentCredential(address)
+				return err
+			},
+			want: &chaincardano.AddressPattern{PaymentPart: payment},
+		},
+		{
+			name: "stake address",
+			search: func() error {
+				_, err := client.GetUtxosByStakeCredential(testStakeAddress(t, common.AddressNetworkMainnet, 0x22))
+				return err
+			},
+			want: &chaincardano.AddressPattern{DelegationPart: stake},
+		},
+		{
+			name: "bech32 stake credential",
+			search: func() error {
+				_, err := client.GetUtxosByStakeCredential(stakeVkh)
+				return err
+			},
+			want: &chaincardano.AddressPattern{DelegationPart: stake},
+		},
+		{
+			name: "stake credential pages",
+			search: func() error {
+				for _, err := range client.GetUtxosByStakeCredentialPages(hex.EncodeToString(stake)) {
+					return err
+				}
+				return nil
+			},
+			want: &chaincardano.AddressPattern{DelegationPart: stake},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.search(); err != nil {
+				t.Fatalf("search returned error: %v", err)
+			}
+			got := fakeQuery.searchUtxosReq.Msg.GetPredicate().GetMatch().GetCardano().GetAddress()
+			if !proto.Equal(got, tt.want) {
+				t.Fatalf("address pattern = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, credential := range []string{stakeVkh, bech32Credential(t, "pool", payment)} {
+		if _, err := client.GetUtxosByPaymentCredential(credential); err == nil {
+			t.Fatalf("GetUtxosByPaymentCredential accepted %s", credential)
+		}
+	}
+}
+
+// bech32Credential encodes a credential hash with a CIP-5 prefix.
+func bech32Credential(t *testing.T, hrp string, hash []byte) string {
+	t.Helper()
+	data, err := bech32.ConvertBits(hash, 8, 5, true)
+	if err != nil {
+		t.Fatalf("ConvertBits returned error: %v", err)
+	}
+	encoded, err := bech32.Encode(hrp, data)
+	if err != nil {
+		t.Fatalf("Encode returned error: %v", err)
+	}
+	return encoded
+}
+
+func testStakeAddress(t *testing.T, network uint8, stake byte) string {
+	t.Helper()
+	addr, err := common.NewAddressFromParts(
+		common.AddressTypeNoneKey,
+		network,
+		nil,
+		bytes.Repeat([]byte{stake}, credentialHashSize),
+	)
+	if err != nil {
+		t.Fatalf("NewAddressFromParts returned error: %v", err)
+	}
+	return addr.String()
+}
@@ -0,0 +1,437 @@
+package cardano
+
+import (
+	"bytes"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+	"google.golang.org/protobuf/proto"
+)
+
+// MatchUtxo reports whether output satisfies predicate, evaluated locally
+// with the same rules a UTxO RPC server applies to SearchUtxos: the match
+// pattern (if any) holds, no "not" predicate holds, every "all_of"
+// predicate holds, and at least one "any_of" predicate holds when the list
+// is non-empty. A nil predicate matches everything; a match pattern for a
+// chain other than Cardano matches nothing.
+func MatchUtxo(predicate *query.UtxoPredicate, output *chaincardano.TxOutput) bool {
+	if predicate == nil {
+		return true
+	}
+	if match := predicate.GetMatch(); match != nil &&
+		!matchOutputPattern(match.GetCardano(), output) {
+		return false
+	}
+	for _, not := range predicate.GetNot() {
+		if MatchUtxo(not, output) {
+			return false
+		}
+	}
+	for _, all := range predicate.GetAllOf() {
+		if !MatchUtxo(all, output) {
+			return false
+		}
+	}
+	if len(predicate.GetAnyOf()) == 0 {
+		return true
+	}
+	for _, alternative := range predicate.GetAnyOf() {
+		if MatchUtxo(alternative, output) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterUtxos returns the items whose Cardano output satisfies predicate,
+// as decided by [MatchUtxo]. Items without a parsed Cardano output are
+// dropped.
+func FilterUtxos(
+	predicate *query.UtxoPredicate,
+	items []*query.AnyUtxoData,
+) []*query.AnyUtxoData {
+	var matched []*query.AnyUtxoData
+	for _, item := range items {
+		if output := item.GetCardano(); output != nil && MatchUtxo(predicate, output) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// MatchTx reports whether tx satisfies predicate, evaluated locally with
+// the same rules a UTxO RPC server applies to WatchTx. The boolean
+// combinators behave as in [MatchUtxo]. Within the Cardano tx pattern every
+// set field must hold:
+//
+//   - consumes: some input's resolved output (TxInput.AsOutput) matches;
+//     inputs the server did not resolve never match.
+//   - produces: some output matches.
+//   - has_address: some address among resolved inputs, outputs, resolved
+//     collateral inputs and the collateral return matches.
+//   - moves_asset: some asset among resolved inputs and outputs matches.
+//   - mints_asset: some minted or burned asset matches.
+//   - has_certificate: some certificate matches.
+func MatchTx(predicate *watch.TxPredicate, tx *chaincardano.Tx) bool {
+	if predicate == nil {
+		return true
+	}
+	if match := predicate.GetMatch(); match != nil &&
+		!matchTxPattern(match.GetCardano(), tx) {
+		return false
+	}
+	for _, not := range predicate.GetNot() {
+		if MatchTx(not, tx) {
+			return false
+		}
+	}
+	for _, all := range predicate.GetAllOf() {
+		if !MatchTx(all, tx) {
+			return false
+		}
+	}
+	if len(predicate.GetAnyOf()) == 0 {
+		return true
+	}
+	for _, alternative := range predicate.GetAnyOf() {
+		if MatchTx(alternative, tx) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOutputPattern(
+	pattern *chaincardano.TxOutputPattern,
+	output *chaincardano.TxOutput,
+) bool {
+	if pattern == nil || output == nil {
+		return false
+	}
+	if address := pattern.GetAddress(); address != nil &&
+		!matchAddressPattern(address, output.GetAddress()) {
+		return false
+	}
+	if asset := pattern.GetAsset(); asset != nil &&
+		!matchAssetPattern(asset, output.GetAssets()) {
+		return false
+	}
+	return true
+}
+
+func matchAddressPattern(pattern *chaincardano.AddressPattern, address []byte) bool {
+	if exact := pattern.GetExactAddress(); len(exact) > 0 &&
+		!bytes.Equal(exact, address) {
+		return false
+	}
+	payment, delegation := pattern.GetPaymentPart(), pattern.GetDelegationPart()
+	if len(payment) == 0 && len(delegation) == 0 {
+		return true
+	}
+	addr, err := common.NewAddressFromBytes(address)
+	if err != nil {
+		return false
+	}
+	if len(payment) > 0 {
+		switch addr.PayloadPayload().(type) {
+		case common.AddressPayloadKeyHash, common.AddressPayloadScriptHash:
+		default:
+			return false
+		}
+		if !bytes.Equal(payment, addr.PaymentKeyHash().Bytes()) {
+			return false
+		}
+	}
+	if len(delegation) > 0 {
+		cred, ok := addr.StakeCredential()
+		if !ok || !bytes.Equal(delegation, cred.Credential.Bytes()) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAssetPattern(
+	pattern *chaincardano.AssetPattern,
+	assets []*chaincardano.Multiasset,
+) bool {
+	policy, name := pattern.GetPolicyId(), pattern.GetAssetName()
+	for _, multiasset := range assets {
+		if len(policy) > 0 && !bytes.Equal(policy, multiasset.GetPolicyId()) {
+			continue
+		}
+		for _, asset := range multiasset.GetAssets() {
+			if len(name) == 0 || bytes.Equal(name, asset.GetName()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchTxPattern(pattern *chaincardano.TxPattern, tx *chaincardano.Tx) bool {
+	if pattern == nil || tx == nil {
+		return false
+	}
+	if consumes := pattern.GetConsumes(); consumes != nil &&
+		!anyOutput(resolvedInputs(tx.GetInputs()), func(o *chaincardano.TxOutput) bool {
+			return matchOutputPattern(consumes, o)
+		}) {
+		return false
+	}
+	if produces := pattern.GetProduces(); produces != nil &&
+		!anyOutput(tx.GetOutputs(), func(o *chaincardano.TxOutput) bool {
+			return matchOutputPattern(produces, o)
+		}) {
+		return false
+	}
+	if address := pattern.GetHasAddress(); address != nil {
+		touched := append(resolvedInputs(tx.GetInputs()), tx.GetOutputs()...)
+		touched = append(touched, resolvedInputs(tx.GetCollateral().GetCollateral())...)
+		if ret := tx.GetCollateral().GetCollateralReturn(); ret != nil {
+			touched = append(touched, ret)
+		}
+		if !anyOutput(touched, func(o *chaincardano.TxOutput) bool {
+			return matchAddressPattern(address, o.GetAddress())
+		}) {
+			return false
+		}
+	}
+	if asset := pattern.GetMovesAsset(); asset != nil {
+		moved := append(resolvedInputs(tx.GetInputs()), tx.GetOutputs()...)
+		if !anyOutput(moved, func(o *chaincardano.TxOutput) bool {
+			return matchAssetPattern(asset, o.GetAssets())
+		}) {
+			return false
+		}
+	}
+	if asset := pattern.GetMintsAsset(); asset != nil &&
+		!matchAssetPattern(asset, tx.GetMint()) {
+		return false
+	}
+	if cert := pattern.GetHasCertificate(); cert != nil {
+		matched := false
+		for _, certificate := range tx.GetCertificates() {
+			if matchCertificatePattern(cert, certificate) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func resolvedInputs(inputs []*chaincardano.TxInput) []*chaincardano.TxOutput {
+	outputs := make([]*chaincardano.TxOutput, 0, len(inputs))
+	for _, input := range inputs {
+		if output := input.GetAsOutput(); output != nil {
+			outputs = append(outputs, output)
+		}
+	}
+	return outputs
+}
+
+func anyOutput(
+	outputs []*chaincardano.TxOutput,
+	match func(*chaincardano.TxOutput) bool,
+) bool {
+	for _, output := range outputs {
+		if match(output) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchCertificatePattern treats Conway certificates that register,
+// deregister or delegate a stake credential the same as their Shelley
+// counterparts.
+func matchCertificatePattern(
+	pattern *chaincardano.CertificatePattern,
+	cert *chaincardano.Certificate,
+) bool {
+	switch p := pattern.GetCertificateType().(type) {
+	case *chaincardano.CertificatePattern_StakeRegistration:
+		cred, ok := registeredCredential(cert)
+		return ok && matchStakeCredential(p.StakeRegistration, cred)
+	case *chaincardano.CertificatePattern_StakeDeregistration:
+		var cred *chaincardano.StakeCredential
+		switch c := cert.GetCertificate().(type) {
+		case *chaincardano.Certificate_StakeDeregistration:
+			cred = c.StakeDeregistration
+		case *chaincardano.Certificate_UnregCert:
+			cred = c.UnregCert.GetStakeCredential()
+		default:
+			return false
+		}
+		return matchStakeCredential(p.StakeDeregistration, cred)
+	case *chaincardano.CertificatePattern_StakeDelegation:
+		cred, pool, ok := poolDelegation(cert)
+		return ok &&
+			matchStakeCredential(p.StakeDelegation.GetStakeCredential(), cred) &&
+			matchOptionalBytes(p.StakeDelegation.GetPoolKeyhash(), pool)
+	case *chaincardano.CertificatePattern_PoolRegistration:
+		reg := cert.GetPoolRegistration()
+		return reg != nil &&
+			matchOptionalBytes(p.PoolRegistration.GetOperator(), reg.GetOperator()) &&
+			matchOptionalBytes(p.PoolRegistration.GetPoolKeyhash(), reg.GetOperator())
+	case *chaincardano.CertificatePattern_PoolRetirement:
+		ret := cert.GetPoolRetirement()
+		epoch := p.PoolRetirement.GetEpoch()
+		return ret != nil &&
+			matchOptionalBytes(p.PoolRetirement.GetPoolKeyhash(), ret.GetPoolKeyhash()) &&
+			(epoch == 0 || epoch == ret.GetEpoch())
+	case *chaincardano.CertificatePattern_AnyStakeCredential:
+		for _, cred := range certificateStakeCredentials(cert) {
+			if bytes.Equal(p.AnyStakeCredential, credentialHash(cred)) {
+				return true
+			}
+		}
+		return false
+	case *chaincardano.CertificatePattern_AnyPoolKeyhash:
+		for _, pool := range certificatePools(cert) {
+			if bytes.Equal(p.AnyPoolKeyhash, pool) {
+				return true
+			}
+		}
+		return false
+	case *chaincardano.CertificatePattern_AnyDrep:
+		for _, drep := range certificateDReps(cert) {
+			if bytes.Equal(p.AnyDrep, drep) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func registeredCredential(
+	cert *chaincardano.Certificate,
+) (*chaincardano.StakeCredential, bool) {
+	switch c := cert.GetCertificate().(type) {
+	case *chaincardano.Certificate_StakeRegistration:
+		return c.StakeRegistration, true
+	case *chaincardano.Certificate_RegCert:
+		return c.RegCert.GetStakeCredential(), true
+	case *chaincardano.Certificate_StakeRegDelegCert:
+		return c.StakeRegDelegCert.GetStakeCredential(), true
+	case *chaincardano.Certificate_VoteRegDelegCert:
+		return c.VoteRegDelegCert.GetStakeCredential(), true
+	case *chaincardano.Certificate_StakeVoteRegDelegCert:
+		return c.StakeVoteRegDelegCert.GetStakeCredential(), true
+	}
+	return nil, false
+}
+
+func poolDelegation(
+	cert *chaincardano.Certificate,
+) (*chaincardano.StakeCredential, []byte, bool) {
+	switch c := cert.GetCertificate().(type) {
+	case *chaincardano.Certificate_StakeDelegation:
+		return c.StakeDelegation.GetStakeCredential(), c.StakeDelegation.GetPoolKeyhash(), true
+	case *chaincardano.Certificate_StakeVoteDelegCert:
+		return c.StakeVoteDelegCert.GetStakeCredential(), c.StakeVoteDelegCert.GetPoolKeyhash(), true
+	case *chaincardano.Certificate_StakeRegDelegCert:
+		return c.StakeRegDelegCert.GetStakeCredential(), c.StakeRegDelegCert.GetPoolKeyhash(), true
+	case *chaincardano.Certificate_StakeVoteRegDelegCert:
+		return c.StakeVoteRegDelegCert.GetStakeCredential(), c.StakeVoteRegDelegCert.GetPoolKeyhash(), true
+	}
+	return nil, nil, false
+}
+
+func certificateStakeCredentials(
+	cert *chaincardano.Certificate,
+) []*chaincardano.StakeCredential {
+	switch c := cert.GetCertificate().(type) {
+	case *chaincardano.Certificate_StakeRegistration:
+		return []*chaincardano.StakeCredential{c.StakeRegistration}
+	case *chaincardano.Certificate_StakeDeregistration:
+		return []*chaincardano.StakeCredential{c.StakeDeregistration}
+	case *chaincardano.Certificate_UnregCert:
+		return []*chaincardano.StakeCredential{c.UnregCert.GetStakeCredential()}
+	case *chaincardano.Certificate_VoteDelegCert:
+		return []*chaincardano.StakeCredential{c.VoteDelegCert.GetStakeCredential()}
+	case *chaincardano.Certificate_MirCert:
+		creds := make([]*chaincardano.StakeCredential, 0, len(c.MirCert.GetTo()))
+		for _, target := range c.MirCert.GetTo() {
+			creds = append(creds, target.GetStakeCredential())
+		}
+		return creds
+	}
+	if cred, ok := registeredCredential(cert); ok {
+		return []*chaincardano.StakeCredential{cred}
+	}
+	if cred, _, ok := poolDelegation(cert); ok {
+		return []*chaincardano.StakeCredential{cred}
+	}
+	return nil
+}
+
+func certificatePools(cert *chaincardano.Certificate) [][]byte {
+	switch c := cert.GetCertificate().(type) {
+	case *chaincardano.Certificate_PoolRegistration:
+		return [][]byte{c.PoolRegistration.GetOperator()}
+	case *chaincardano.Certificate_PoolRetirement:
+		return [][]byte{c.PoolRetirement.GetPoolKeyhash()}
+	}
+	if _, pool, ok := poolDelegation(cert); ok {
+		return [][]byte{pool}
+	}
+	return nil
+}
+
+func certificateDReps(cert *chaincardano.Certificate) [][]byte {
+	var drep *chaincardano.DRep
+	switch c := cert.GetCertificate().(type) {
+	case *chaincardano.Certificate_VoteDelegCert:
+		drep = c.VoteDelegCert.GetDrep()
+	case *chaincardano.Certificate_StakeVoteDelegCert:
+		drep = c.StakeVoteDelegCert.GetDrep()
+	case *chaincardano.Certificate_VoteRegDelegCert:
+		drep = c.VoteRegDelegCert.GetDrep()
+	case *chaincardano.Certificate_StakeVoteRegDelegCert:
+		drep = c.StakeVoteRegDelegCert.GetDrep()
+	case *chaincardano.Certificate_RegDrepCert:
+		return [][]byte{credentialHash(c.RegDrepCert.GetDrepCredential())}
+	case *chaincardano.Certificate_UnregDrepCert:
+		return [][]byte{credentialHash(c.UnregDrepCert.GetDrepCredential())}
+	case *chaincardano.Certificate_UpdateDrepCert:
+		return [][]byte{credentialHash(c.UpdateDrepCert.GetDrepCredential())}
+	default:
+		return nil
+	}
+	switch d := drep.GetDrep().(type) {
+	case *chaincardano.DRep_AddrKeyHash:
+		return [][]byte{d.AddrKeyHash}
+	case *chaincardano.DRep_ScriptHash:
+		return [][]byte{d.ScriptHash}
+	}
+	return nil
+}
+
+func credentialHash(cred *chaincardano.StakeCredential) []byte {
+	switch c := cred.GetStakeCredential().(type) {
+	case *chaincardano.StakeCredential_AddrKeyHash:
+		return c.AddrKeyHash
+	case *chaincardano.StakeCredential_ScriptHash:
+		return c.ScriptHash
+	}
+	return nil
+}
+
+// matchStakeCredential treats an unset pattern credential as a wildcard.
+func matchStakeCredential(pattern, cred *chaincardano.StakeCredential) bool {
+	return pattern.GetStakeCredential() == nil || proto.Equal(pattern, cred)
+}
+
+// matchOptionalBytes treats an empty pattern as a wildcard.
+func matchOptionalBytes(pattern, value []byte) bool {
+	return len(pattern) == 0 || bytes.Equal(pattern, value)
+}
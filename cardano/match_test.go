@@ -0,0 +1,262 @@
+package cardano
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+)
+
+func hash28(b byte) []byte {
+	return bytes.Repeat([]byte{b}, credentialHashSize)
+}
+
+func utxoMatch(pattern *chaincardano.TxOutputPattern) *query.UtxoPredicate {
+	return &query.UtxoPredicate{
+		Match: &query.AnyUtxoPattern{
+			UtxoPattern: &query.AnyUtxoPattern_Cardano{Cardano: pattern},
+		},
+	}
+}
+
+func txMatch(pattern *chaincardano.TxPattern) *watch.TxPredicate {
+	return &watch.TxPredicate{
+		Match: &watch.AnyChainTxPattern{
+			Chain: &watch.AnyChainTxPattern_Cardano{Cardano: pattern},
+		},
+	}
+}
+
+func stakeKey(b byte) *chaincardano.StakeCredential {
+	return &chaincardano.StakeCredential{
+		StakeCredential: &chaincardano.StakeCredential_AddrKeyHash{AddrKeyHash: hash28(b)},
+	}
+}
+
+func TestMatchUtxo(t *testing.T) {
+	_, baseAddress := testAddress(t, 0x11, 0x22)
+	enterprise, err := common.NewAddressFromParts(
+		common.AddressTypeKeyNone,
+		common.AddressNetworkMainnet,
+		hash28(0x11),
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("NewAddressFromParts returned error: %v", err)
+	}
+	enterpriseAddress, _ := enterprise.Bytes()
+
+	assets := []*chaincardano.Multiasset{{
+		PolicyId: hash28(0xaa),
+		Assets:   []*chaincardano.Asset{{Name: []byte("tok")}},
+	}}
+	output := &chaincardano.TxOutput{Address: baseAddress, Assets: assets}
+	enterpriseOutput := &chaincardano.TxOutput{Address: enterpriseAddress}
+
+	address := func(p *chaincardano.AddressPattern) *query.UtxoPredicate {
+		return utxoMatch(&chaincardano.TxOutputPattern{Address: p})
+	}
+	asset := func(p *chaincardano.AssetPattern) *query.UtxoPredicate {
+		return utxoMatch(&chaincardano.TxOutputPattern{Asset: p})
+	}
+	matching := address(&chaincardano.AddressPattern{PaymentPart: hash28(0x11)})
+	failing := address(&chaincardano.AddressPattern{PaymentPart: hash28(0x99)})
+
+	tests := []struct {
+		name      string
+		predicate *query.UtxoPredicate
+		output    *chaincardano.TxOutput
+		want      bool
+	}{
+		{name: "nil predicate", predicate: nil, output: output, want: true},
+		{name: "empty predicate", predicate: &query.UtxoPredicate{}, output: output, want: true},
+		{name: "non-cardano pattern", predicate: &query.UtxoPredicate{Match: &query.AnyUtxoPattern{}}, output: output, want: false},
+		{name: "exact address", predicate: address(&chaincardano.AddressPattern{ExactAddress: baseAddress}), output: output, want: true},
+		{name: "exact address mismatch", predicate: address(&chaincardano.AddressPattern{ExactAddress: enterpriseAddress}), output: output, want: false},
+		{name: "payment part", predicate: matching, output: output, want: true},
+		{name: "payment part mismatch", predicate: failing, output: output, want: false},
+		{name: "delegation part", predicate: address(&chaincardano.AddressPattern{DelegationPart: hash28(0x22)}), output: output, want: true},
+		{name: "delegation part mismatch", predicate: address(&chaincardano.AddressPattern{DelegationPart: hash28(0x11)}), output: output, want: false},
+		{name: "delegation part without stake", predicate: address(&chaincardano.AddressPattern{DelegationPart: hash28(0x22)}), output: enterpriseOutput, want: false},
+		{name: "payment part enterprise", predicate: matching, output: enterpriseOutput, want: true},
+		{name: "policy", predicate: asset(&chaincardano.AssetPattern{PolicyId: hash28(0xaa)}), output: output, want: true},
+		{name: "policy mismatch", predicate: asset(&chaincardano.AssetPattern{PolicyId: hash28(0xbb)}), output: output, want: false},
+		{name: "asset name", predicate: asset(&chaincardano.AssetPattern{AssetName: []byte("tok")}), output: output, want: true},
+		{name: "asset name mismatch", predicate: asset(&chaincardano.AssetPattern{PolicyId: hash28(0xaa), AssetName: []byte("other")}), output: output, want: false},
+		{name: "asset on output without assets", predicate: asset(&chaincardano.AssetPattern{PolicyId: hash28(0xaa)}), output: enterpriseOutput, want: false},
+		{name: "not excludes", predicate: &query.UtxoPredicate{Not: []*query.UtxoPredicate{matching}}, output: output, want: false},
+		{name: "not passes", predicate: &query.UtxoPredicate{Not: []*query.UtxoPredicate{failing}}, output: output, want: true},
+		{name: "all of", predicate: &query.UtxoPredicate{AllOf: []*query.UtxoPredicate{matching, matching}}, output: output, want: true},
+		{name: "all of with failure", predicate: &query.UtxoPredicate{AllOf: []*query.UtxoPredicate{matching, failing}}, output: output, want: false},
+		{name: "any of", predicate: &query.UtxoPredicate{AnyOf: []*query.UtxoPredicate{failing, matching}}, output: output, want: true},
+		{name: "any of none", predicate: &query.UtxoPredicate{AnyOf: []*query.UtxoPredicate{failing}}, output: output, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := MatchUtxo(test.predicate, test.output); got != test.want {
+				t.Fatalf("MatchUtxo = %t, want %t", got, test.want)
+			}
+		})
+	}
+
+	items := []*query.AnyUtxoData{
+		{ParsedState: &query.AnyUtxoData_Cardano{Cardano: output}},
+		{ParsedState: &query.AnyUtxoData_Cardano{Cardano: enterpriseOutput}},
+		{},
+	}
+	if got := FilterUtxos(asset(&chaincardano.AssetPattern{PolicyId: hash28(0xaa)}), items); len(got) != 1 {
+		t.Fatalf("len(FilterUtxos) = %d, want 1", len(got))
+	}
+}
+
+func TestMatchTx(t *testing.T) {
+	_, inputAddress := testAddress(t, 0x11, 0x22)
+	_, outputAddress := testAddress(t, 0x33, 0x44)
+	_, collateralAddress := testAddress(t, 0x55, 0x66)
+	policy := hash28(0xaa)
+
+	tx := &chaincardano.Tx{
+		Inputs: []*chaincardano.TxInput{
+			{AsOutput: &chaincardano.TxOutput{
+				Address: inputAddress,
+				Assets: []*chaincardano.Multiasset{{
+					PolicyId: policy,
+					Assets:   []*chaincardano.Asset{{Name: []byte("in")}},
+				}},
+			}},
+			{TxHash: hash28(0x01)},
+		},
+		Outputs: []*chaincardano.TxOutput{{Address: outputAddress}},
+		Collateral: &chaincardano.Collateral{
+			CollateralReturn: &chaincardano.TxOutput{Address: collateralAddress},
+		},
+		Mint: []*chaincardano.Multiasset{{
+			PolicyId: hash28(0xbb),
+			Assets:   []*chaincardano.Asset{{Name: []byte("minted")}},
+		}},
+		Certificates: []*chaincardano.Certificate{
+			{Certificate: &chaincardano.Certificate_RegCert{
+				RegCert: &chaincardano.RegCert{StakeCredential: stakeKey(0x01)},
+			}},
+			{Certificate: &chaincardano.Certificate_StakeDeregistration{
+				StakeDeregistration: stakeKey(0x02),
+			}},
+			{Certificate: &chaincardano.Certificate_StakeVoteDelegCert{
+				StakeVoteDelegCert: &chaincardano.StakeVoteDelegCert{
+					StakeCredential: stakeKey(0x03),
+					PoolKeyhash:     hash28(0xc1),
+					Drep: &chaincardano.DRep{
+						Drep: &chaincardano.DRep_AddrKeyHash{AddrKeyHash: hash28(0xd1)},
+					},
+				},
+			}},
+			{Certificate: &chaincardano.Certificate_PoolRegistration{
+				PoolRegistration: &chaincardano.PoolRegistrationCert{Operator: hash28(0xc2)},
+			}},
+			{Certificate: &chaincardano.Certificate_PoolRetirement{
+				PoolRetirement: &chaincardano.PoolRetirementCert{PoolKeyhash: hash28(0xc3), Epoch: 500},
+			}},
+			{Certificate: &chaincardano.Certificate_MirCert{
+				MirCert: &chaincardano.MirCert{
+					To: []*chaincardano.MirTarget{{StakeCredential: stakeKey(0x04)}},
+				},
+			}},
+		},
+	}
+
+	output := func(address []byte) *chaincardano.TxOutputPattern {
+		return &chaincardano.TxOutputPattern{
+			Address: &chaincardano.AddressPattern{ExactAddress: address},
+		}
+	}
+	cert := func(p *chaincardano.CertificatePattern) *watch.TxPredicate {
+		return txMatch(&chaincardano.TxPattern{HasCertificate: p})
+	}
+
+	tests := []struct {
+		name      string
+		predicate *watch.TxPredicate
+		want      bool
+	}{
+		{name: "nil predicate", predicate: nil, want: true},
+		{name: "consumes", predicate: txMatch(&chaincardano.TxPattern{Consumes: output(inputAddress)}), want: true},
+		{name: "consumes mismatch", predicate: txMatch(&chaincardano.TxPattern{Consumes: output(outputAddress)}), want: false},
+		{name: "produces", predicate: txMatch(&chaincardano.TxPattern{Produces: output(outputAddress)}), want: true},
+		{name: "produces mismatch", predicate: txMatch(&chaincardano.TxPattern{Produces: output(inputAddress)}), want: false},
+		{name: "has address input", predicate: txMatch(&chaincardano.TxPattern{HasAddress: &chaincardano.AddressPattern{PaymentPart: hash28(0x11)}}), want: true},
+		{name: "has address collateral return", predicate: txMatch(&chaincardano.TxPattern{HasAddress: &chaincardano.AddressPattern{DelegationPart: hash28(0x66)}}), want: true},
+		{name: "has address mismatch", predicate: txMatch(&chaincardano.TxPattern{HasAddress: &chaincardano.AddressPattern{PaymentPart: hash28(0x99)}}), want: false},
+		{name: "moves asset", predicate: txMatch(&chaincardano.TxPattern{MovesAsset: &chaincardano.AssetPattern{PolicyId: policy}}), want: true},
+		{name: "moves minted-only asset", predicate: txMatch(&chaincardano.TxPattern{MovesAsset: &chaincardano.AssetPattern{PolicyId: hash28(0xbb)}}), want: false},
+		{name: "mints asset", predicate: txMatch(&chaincardano.TxPattern{MintsAsset: &chaincardano.AssetPattern{AssetName: []byte("minted")}}), want: true},
+		{name: "mints asset mismatch", predicate: txMatch(&chaincardano.TxPattern{MintsAsset: &chaincardano.AssetPattern{PolicyId: policy}}), want: false},
+		{name: "all fields together", predicate: txMatch(&chaincardano.TxPattern{
+			Consumes:   output(inputAddress),
+			Produces:   output(outputAddress),
+			MintsAsset: &chaincardano.AssetPattern{PolicyId: hash28(0xbb)},
+		}), want: true},
+		{name: "stake registration (conway)", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_StakeRegistration{StakeRegistration: stakeKey(0x01)},
+		}), want: true},
+		{name: "stake registration mismatch", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_StakeRegistration{StakeRegistration: stakeKey(0x02)},
+		}), want: false},
+		{name: "stake deregistration", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_StakeDeregistration{StakeDeregistration: stakeKey(0x02)},
+		}), want: true},
+		{name: "stake delegation to pool", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_StakeDelegation{
+				StakeDelegation: &chaincardano.StakeDelegationPattern{PoolKeyhash: hash28(0xc1)},
+			},
+		}), want: true},
+		{name: "stake delegation wrong credential", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_StakeDelegation{
+				StakeDelegation: &chaincardano.StakeDelegationPattern{StakeCredential: stakeKey(0x01)},
+			},
+		}), want: false},
+		{name: "pool registration", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_PoolRegistration{
+				PoolRegistration: &chaincardano.PoolRegistrationPattern{Operator: hash28(0xc2)},
+			},
+		}), want: true},
+		{name: "pool retirement epoch", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_PoolRetirement{
+				PoolRetirement: &chaincardano.PoolRetirementPattern{PoolKeyhash: hash28(0xc3), Epoch: 500},
+			},
+		}), want: true},
+		{name: "pool retirement wrong epoch", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_PoolRetirement{
+				PoolRetirement: &chaincardano.PoolRetirementPattern{Epoch: 501},
+			},
+		}), want: false},
+		{name: "any stake credential in mir", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_AnyStakeCredential{AnyStakeCredential: hash28(0x04)},
+		}), want: true},
+		{name: "any pool keyhash", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_AnyPoolKeyhash{AnyPoolKeyhash: hash28(0xc3)},
+		}), want: true},
+		{name: "any drep", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_AnyDrep{AnyDrep: hash28(0xd1)},
+		}), want: true},
+		{name: "any drep mismatch", predicate: cert(&chaincardano.CertificatePattern{
+			CertificateType: &chaincardano.CertificatePattern_AnyDrep{AnyDrep: hash28(0xd2)},
+		}), want: false},
+		{name: "not", predicate: &watch.TxPredicate{Not: []*watch.TxPredicate{
+			txMatch(&chaincardano.TxPattern{Produces: output(outputAddress)}),
+		}}, want: false},
+		{name: "any of", predicate: &watch.TxPredicate{AnyOf: []*watch.TxPredicate{
+			txMatch(&chaincardano.TxPattern{Produces: output(inputAddress)}),
+			txMatch(&chaincardano.TxPattern{Consumes: output(inputAddress)}),
+		}}, want: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := MatchTx(test.predicate, tx); got != test.want {
+				t.Fatalf("MatchTx = %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+package cardano
+
+import (
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	gosync "sync"
+	"time"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+)
+
+const defaultMempoolResync = 30 * time.Second
+
+// MempoolTx is a pending transaction held by a [MempoolObserver].
+type MempoolTx struct {
+	Hash  []byte
+	Stage submit.Stage
+	// Tx is the decoded transaction: the server's parsed form when sent,
+	// otherwise decoded locally from the native bytes. It is nil if neither
+	// was available.
+	Tx *chaincardano.Tx
+	// Size is the length of the transaction CBOR, or 0 if the server did
+	// not send it.
+	Size int
+	Fee  uint64
+	// FirstSeen is when the observer first saw the transaction.
+	FirstSeen time.Time
+}
+
+// MempoolStats summarises the transactions held by a [MempoolObserver].
+// Fee and age figures are zero when the mempool is empty.
+type MempoolStats struct {
+	Count int
+	Bytes int
+	// Fees are in lovelace; the median and 90th percentile use the nearest
+	// rank.
+	TotalFee  uint64
+	MinFee    uint64
+	MedianFee uint64
+	P90Fee    uint64
+	MaxFee    uint64
+	// Ages are measured from FirstSeen.
+	MedianAge time.Duration
+	OldestAge time.Duration
+}
+
+// MempoolObserverOption configures a [MempoolObserver] during
+// [Client.NewMempoolObserver].
+type MempoolObserverOption func(*MempoolObserver)
+
+// WithMempoolResync sets how often the observer replaces its view with a
+// fresh Submit.ReadMempool snapshot, dropping transactions that left the
+// mempool without a WatchMempool event. Values below 1 disable resyncing
+// after the initial snapshot. The default is 30 seconds.
+func WithMempoolResync(interval time.Duration) MempoolObserverOption {
+	return func(o *MempoolObserver) {
+		o.resync = interval
+	}
+}
+
+// MempoolObserver maintains a live view of the server's mempool. Its Run
+// loads a Submit.ReadMempool snapshot and then applies Submit.WatchMempool
+// events: transactions reported as confirmed or with an unspecified stage
+// leave the view, any other stage adds or updates them. Servers without
+// WatchMempool are served by the periodic snapshot alone.
+//
+// A MempoolObserver is safe for concurrent use.
+type MempoolObserver struct {
+	client *Client
+	resync time.Duration
+
+	mu  gosync.Mutex
+	txs map[string]*MempoolTx
+	// spends maps an input ("hash#index") to the pending tx spending it.
+	spends map[string]string
+}
+
+// NewMempoolObserver returns an empty [MempoolObserver]. Call
+// [MempoolObserver.Run] to populate it.
+func (c *Client) NewMempoolObserver(
+	options ...MempoolObserverOption,
+) *MempoolObserver {
+	o := &MempoolObserver{
+		client: c,
+		resync: defaultMempoolResync,
+		txs:    make(map[string]*MempoolTx),
+		spends: make(map[string]string),
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// Run keeps the view up to date until ctx is done or an RPC fails with an
+// error other than Unimplemented.
+func (o *MempoolObserver) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Open the stream before the snapshot so no change falls in between.
+	stream, err := o.client.WatchMempoolTransactionsWithContext(ctx)
+	if err != nil && connect.CodeOf(err) != connect.CodeUnimplemented {
+		return fmt.Errorf("failed to watch mempool: %w", err)
+	}
+	if err := o.Resync(ctx); err != nil {
+		return err
+	}
+
+	errs := make(chan error, 1)
+	if stream != nil {
+		go func() {
+			defer stream.Close()
+			for stream.Receive() {
+				o.apply(stream.Msg().GetTx(), time.Now())
+			}
+			err := stream.Err()
+			if err != nil && connect.CodeOf(err) != connect.CodeUnimplemented {
+				errs <- fmt.Errorf("failed to watch mempool: %w", err)
+			}
+		}()
+	}
+
+	var tick <-chan time.Time
+	if o.resync > 0 {
+		ticker := time.NewTicker(o.resync)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		case <-tick:
+			if err := o.Resync(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Resync replaces the view with a Submit.ReadMempool snapshot. Transactions
+// already in the view keep their FirstSeen time.
+func (o *MempoolObserver) Resync(ctx context.Context) error {
+	resp, err := o.client.GetMempoolTransactionsWithContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read mempool: %w", err)
+	}
+	now := time.Now()
+	snapshot := make(map[string]*MempoolTx, len(resp.Msg.GetItems()))
+	for _, item := range resp.Msg.GetItems() {
+		tx := newMempoolTx(item, now)
+		snapshot[hex.EncodeToString(tx.Hash)] = tx
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for key, tx := range snapshot {
+		if known, ok := o.txs[key]; ok {
+			tx.FirstSeen = known.FirstSeen
+		}
+	}
+	o.txs = snapshot
+	o.spends = make(map[string]string)
+	for key, tx := range o.txs {
+		o.index(key, tx)
+	}
+	return nil
+}
+
+// apply merges one WatchMempool event into the view.
+func (o *MempoolObserver) apply(item *submit.TxInMempool, now time.Time) {
+	key := hex.EncodeToString(item.GetRef())
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	known, ok := o.txs[key]
+	if ok {
+		o.unindex(key, known)
+	}
+	switch item.GetStage() {
+	case submit.Stage_STAGE_UNSPECIFIED, submit.Stage_STAGE_CONFIRMED:
+		delete(o.txs, key)
+		return
+	case submit.Stage_STAGE_ACKNOWLEDGED, submit.Stage_STAGE_MEMPOOL, submit.Stage_STAGE_NETWORK:
+		// Still pending: keep or update the transaction below.
+	}
+	tx := newMempoolTx(item, now)
+	if ok {
+		tx.FirstSeen = known.FirstSeen
+		// Stage-only updates may omit the transaction body.
+		if tx.Tx == nil {
+			tx.Tx, tx.Size, tx.Fee = known.Tx, known.Size, known.Fee
+		}
+	}
+	o.txs[key] = tx
+	o.index(key, tx)
+}
+
+func (o *MempoolObserver) index(key string, tx *MempoolTx) {
+	for _, input := range tx.Tx.GetInputs() {
+		o.spends[inputKey(input.GetTxHash(), input.GetOutputIndex())] = key
+	}
+}
+
+// unindex drops the inputs of tx, keyed key, from the spends index. Inputs
+// since claimed by a conflicting transaction keep pointing at it.
+func (o *MempoolObserver) unindex(key string, tx *MempoolTx) {
+	for _, input := range tx.Tx.GetInputs() {
+		spent := inputKey(input.GetTxHash(), input.GetOutputIndex())
+		if o.spends[spent] == key {
+			delete(o.spends, spent)
+		}
+	}
+}
+
+func inputKey(txHash []byte, index uint32) string {
+	return fmt.Sprintf("%x#%d", txHash, index)
+}
+
+func newMempoolTx(item *submit.TxInMempool, now time.Time) *MempoolTx {
+	tx := &MempoolTx{
+		Hash:      item.GetRef(),
+		Stage:     item.GetStage(),
+		Tx:        item.GetCardano(),
+		Size:      len(item.GetNativeBytes()),
+		FirstSeen: now,
+	}
+	if tx.Tx == nil && len(item.GetNativeBytes()) > 0 {
+		if decoded, err := decodeLedgerTransaction(item.GetNativeBytes()); err == nil {
+			tx.Tx = txToUtxorpc(decoded)
+		}
+	}
+	tx.Fee = bigIntUint64(tx.Tx.GetFee())
+	return tx
+}
+
+// bigIntUint64 returns b as a uint64, or 0 if it is negative or too large.
+func bigIntUint64(b *chaincardano.BigInt) uint64 {
+	switch v := b.GetBigInt().(type) {
+	case *chaincardano.BigInt_Int:
+		if v.Int > 0 {
+			// #nosec G115 -- positive int64 fits in uint64
+			return uint64(v.Int)
+		}
+	case *chaincardano.BigInt_BigUInt:
+		if len(v.BigUInt) <= 8 {
+			var buf [8]byte
+			copy(buf[8-len(v.BigUInt):], v.BigUInt)
+			return binary.BigEndian.Uint64(buf[:])
+		}
+	}
+	return 0
+}
+
+// Get returns the pending transaction with the given hex hash.
+func (o *MempoolObserver) Get(txHash string) (MempoolTx, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	tx, ok := o.txs[txHash]
+	if !ok {
+		return MempoolTx{}, false
+	}
+	return *tx, true
+}
+
+// Transactions returns every pending transaction, oldest first.
+func (o *MempoolObserver) Transactions() []MempoolTx {
+	return o.Filter(nil)
+}
+
+// Filter returns the pending transactions matching pred, oldest first,
+// evaluated locally with [MatchTx]. A nil pred matches every transaction;
+// otherwise transactions that could not be decoded never match.
+func (o *MempoolObserver) Filter(pred *watch.TxPredicate) []MempoolTx {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var txs []MempoolTx
+	for _, tx := range o.txs {
+		if pred == nil || (tx.Tx != nil && MatchTx(pred, tx.Tx)) {
+			txs = append(txs, *tx)
+		}
+	}
+	slices.SortFunc(txs, func(a, b MempoolTx) int {
+		return a.FirstSeen.Compare(b.FirstSeen)
+	})
+	return txs
+}
+
+// SpentBy reports the pending transaction spending the output index of
+// the transaction with the given hex hash, if any.
+func (o *MempoolObserver) SpentBy(txHash string, index uint32) (MempoolTx, bool) {
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return MempoolTx{}, false
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	key, ok := o.spends[inputKey(hash, index)]
+	if !ok {
+		return MempoolTx{}, false
+	}
+	return *o.txs[key], true
+}
+
+// Stats summarises the current view.
+func (o *MempoolObserver) Stats() MempoolStats {
+	now := time.Now()
+	o.mu.Lock()
+	var stats MempoolStats
+	fees := make([]uint64, 0, len(o.txs))
+	ages := make([]time.Duration, 0, len(o.txs))
+	for _, tx := range o.txs {
+		stats.Count++
+		stats.Bytes += tx.Size
+		stats.TotalFee += tx.Fee
+		fees = append(fees, tx.Fee)
+		ages = append(ages, now.Sub(tx.FirstSeen))
+	}
+	o.mu.Unlock()
+
+	if stats.Count == 0 {
+		return stats
+	}
+	slices.Sort(fees)
+	slices.Sort(ages)
+	stats.MinFee = fees[0]
+	stats.MedianFee = nearestRank(fees, 50)
+	stats.P90Fee = nearestRank(fees, 90)
+	stats.MaxFee = fees[len(fees)-1]
+	stats.MedianAge = nearestRank(ages, 50)
+	stats.OldestAge = ages[len(ages)-1]
+	return stats
+}
+
+// nearestRank returns the p-th percentile of the non-empty sorted values.
+func nearestRank[T cmp.Ordered](sorted []T, p int) T {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
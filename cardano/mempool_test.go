@@ -0,0 +1,145 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+)
+
+func TestMempoolObserverMergesSnapshotAndEvents(t *testing.T) {
+	native, _ := hex.DecodeString(trackerTxCbor)
+	fakeSubmit := &mempoolSubmitHandler{items: []*submit.TxInMempool{
+		parsedMempoolTx("aa", 200, "11"),
+		{
+			Ref:         mustDecodeHex(t, trackerTxHash),
+			NativeBytes: native,
+			Stage:       submit.Stage_STAGE_MEMPOOL,
+		},
+	}}
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return submitconnect.NewSubmitServiceHandler(fakeSubmit)
+	})
+
+	observer := client.NewMempoolObserver()
+	if err := observer.Resync(context.Background()); err != nil {
+		t.Fatalf("Resync returned error: %v", err)
+	}
+	decoded, ok := observer.Get(trackerTxHash)
+	if !ok || decoded.Tx == nil || decoded.Size != len(native) {
+		t.Fatalf("native-only transaction = %+v, want it decoded", decoded)
+	}
+	spender, ok := observer.SpentBy(strings.Repeat("00", 32), 0)
+	if !ok || hex.EncodeToString(spender.Hash) != trackerTxHash {
+		t.Fatalf("SpentBy = %x, %v; want the decoded transaction", spender.Hash, ok)
+	}
+
+	observer.apply(&submit.TxInMempool{
+		Ref:   mustDecodeHex(t, "aa"),
+		Stage: submit.Stage_STAGE_CONFIRMED,
+	}, time.Now())
+	observer.apply(parsedMempoolTx("bb", 300, "22"), time.Now())
+	observer.apply(&submit.TxInMempool{
+		Ref:   mustDecodeHex(t, "bb"),
+		Stage: submit.Stage_STAGE_NETWORK,
+	}, time.Now())
+
+	if _, ok := observer.SpentBy(strings.Repeat("11", 32), 0); ok {
+		t.Fatal("SpentBy still reports an input of the confirmed transaction")
+	}
+	updated, ok := observer.Get("bb")
+	if !ok || updated.Stage != submit.Stage_STAGE_NETWORK || updated.Fee != 300 {
+		t.Fatalf("stage-only update = %+v, want NETWORK keeping fee 300", updated)
+	}
+	if _, ok := observer.SpentBy(strings.Repeat("22", 32), 0); !ok {
+		t.Fatal("SpentBy lost the input of a stage-only update")
+	}
+
+	stats := observer.Stats()
+	if stats.Count != 2 || stats.Bytes != len(native) || stats.TotalFee != 300 ||
+		stats.MinFee != 0 || stats.MaxFee != 300 || stats.MedianFee != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+}
+
+func TestMempoolObserverKeepsConflictingSpender(t *testing.T) {
+	observer := NewClient().NewMempoolObserver()
+	observer.apply(parsedMempoolTx("cc", 200, "33"), time.Now())
+	observer.apply(parsedMempoolTx("dd", 300, "33"), time.Now())
+	observer.apply(&submit.TxInMempool{
+		Ref:   mustDecodeHex(t, "cc"),
+		Stage: submit.Stage_STAGE_UNSPECIFIED,
+	}, time.Now())
+
+	spender, ok := observer.SpentBy(strings.Repeat("33", 32), 0)
+	if !ok || hex.EncodeToString(spender.Hash) != "dd" {
+		t.Fatalf("SpentBy = %x, %v; want the remaining conflicting transaction", spender.Hash, ok)
+	}
+}
+
+func TestMempoolObserverFiltersByPredicate(t *testing.T) {
+	observer := NewClient().NewMempoolObserver()
+	observer.apply(parsedMempoolTx("aa", 100, "11"), time.Now())
+	bech32, raw := testAddress(t, 1, 2)
+	paying := parsedMempoolTx("bb", 100, "22")
+	paying.GetCardano().Outputs = []*chaincardano.TxOutput{{Address: raw}}
+	observer.apply(paying, time.Now())
+
+	pred, err := NewTxPredicate().HasAddress(bech32).Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	txs := observer.Filter(pred)
+	if len(txs) != 1 || hex.EncodeToString(txs[0].Hash) != "bb" {
+		t.Fatalf("Filter = %v, want only bb", txs)
+	}
+	if got := len(observer.Transactions()); got != 2 {
+		t.Fatalf("Transactions returned %d entries, want 2", got)
+	}
+}
+
+// parsedMempoolTx returns a mempool entry with a parsed body spending
+// output 0 of a transaction whose hash repeats the byte spent.
+func parsedMempoolTx(ref string, fee int64, spent string) *submit.TxInMempool {
+	hash, _ := hex.DecodeString(ref)
+	input, _ := hex.DecodeString(spent)
+	return &submit.TxInMempool{
+		Ref:   hash,
+		Stage: submit.Stage_STAGE_MEMPOOL,
+		ParsedState: &submit.TxInMempool_Cardano{Cardano: &chaincardano.Tx{
+			Hash: hash,
+			Fee:  &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: fee}},
+			Inputs: []*chaincardano.TxInput{{
+				TxHash: []byte(strings.Repeat(string(input), 32)),
+			}},
+		}},
+	}
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+type mempoolSubmitHandler struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+	items []*submit.TxInMempool
+}
+
+func (s *mempoolSubmitHandler) ReadMempool(
+	context.Context,
+	*connect.Request[submit.ReadMempoolRequest],
+) (*connect.Response[submit.ReadMempoolResponse], error) {
+	return connect.NewResponse(&submit.ReadMempoolResponse{Items: s.items}), nil
+}
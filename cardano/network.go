@@ -0,0 +1,110 @@
+package cardano
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	gosync "sync"
+
+	"connectrpc.com/connect"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// ErrNetworkMismatch is returned by the address and credential searches
+// when an address belongs to another network than the connected server.
+var ErrNetworkMismatch = errors.New("address network does not match the server")
+
+// mainnetNetworkMagic is the network magic of Cardano mainnet.
+const mainnetNetworkMagic = 764824073
+
+// networkCache holds the network ID read from the server's genesis, so it
+// is fetched once per client.
+type networkCache struct {
+	mu     gosync.Mutex
+	loaded bool
+	known  bool
+	id     uint
+}
+
+// serverNetwork returns the address network ID (mainnet or testnet) of the
+// connected server. known is false when the server does not serve a
+// Cardano genesis, the genesis does not tell, or ReadGenesis fails: the
+// network check is best-effort and never fails a search itself. Transient
+// failures are not remembered, so a later call reads the genesis again.
+//
+// The lock is not held during the RPC, so concurrent first calls may each
+// read the genesis; they store the same result.
+func (c *Client) serverNetwork(ctx context.Context) (id uint, known bool) {
+	c.network.mu.Lock()
+	id, known, loaded := c.network.id, c.network.known, c.network.loaded
+	c.network.mu.Unlock()
+	if loaded {
+		return id, known
+	}
+	resp, err := c.UtxorpcClient.ReadGenesisWithContext(
+		ctx,
+		connect.NewRequest(&query.ReadGenesisRequest{}),
+	)
+	if err != nil {
+		switch connect.CodeOf(err) {
+		case connect.CodeCanceled, connect.CodeDeadlineExceeded,
+			connect.CodeUnavailable, connect.CodeResourceExhausted,
+			connect.CodeAborted:
+			return 0, false
+		}
+	} else {
+		id, known = genesisNetwork(resp.Msg.GetCardano())
+	}
+	c.network.mu.Lock()
+	c.network.id, c.network.known, c.network.loaded = id, known, true
+	c.network.mu.Unlock()
+	return id, known
+}
+
+// checkNetwork fails with [ErrNetworkMismatch] if addr is not on the
+// server's network.
+func (c *Client) checkNetwork(ctx context.Context, addr *common.Address) error {
+	id, known := c.serverNetwork(ctx)
+	if !known {
+		return nil
+	}
+	if got := addr.NetworkId(); got != id {
+		return fmt.Errorf(
+			"%w: %s is a %s address, the server is on %s",
+			ErrNetworkMismatch,
+			addr.String(),
+			networkName(got),
+			networkName(id),
+		)
+	}
+	return nil
+}
+
+// genesisNetwork returns the address network ID named by a genesis, from
+// its network ID or else its network magic.
+func genesisNetwork(genesis *chaincardano.Genesis) (uint, bool) {
+	switch strings.ToLower(genesis.GetNetworkId()) {
+	case "mainnet":
+		return common.AddressNetworkMainnet, true
+	case "testnet":
+		return common.AddressNetworkTestnet, true
+	}
+	switch genesis.GetNetworkMagic() {
+	case 0:
+		return 0, false
+	case mainnetNetworkMagic:
+		return common.AddressNetworkMainnet, true
+	default:
+		return common.AddressNetworkTestnet, true
+	}
+}
+
+func networkName(id uint) string {
+	if id == common.AddressNetworkMainnet {
+		return "mainnet"
+	}
+	return "testnet"
+}
@@ -0,0 +1,108 @@
+package cardano
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+)
+
+func TestGenesisNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		genesis *chaincardano.Genesis
+		want    uint
+		known   bool
+	}{
+		{name: "mainnet ID", genesis: &chaincardano.Genesis{NetworkId: "Mainnet", NetworkMagic: 1}, want: common.AddressNetworkMainnet, known: true},
+		{name: "testnet ID", genesis: &chaincardano.Genesis{NetworkId: "Testnet", NetworkMagic: mainnetNetworkMagic}, want: common.AddressNetworkTestnet, known: true},
+		{name: "mainnet magic", genesis: &chaincardano.Genesis{NetworkMagic: mainnetNetworkMagic}, want: common.AddressNetworkMainnet, known: true},
+		{name: "preview magic", genesis: &chaincardano.Genesis{NetworkMagic: 2}, want: common.AddressNetworkTestnet, known: true},
+		{name: "unknown", genesis: &chaincardano.Genesis{}},
+		{name: "missing", genesis: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, known := genesisNetwork(tt.genesis)
+			if got != tt.want || known != tt.known {
+				t.Fatalf("genesisNetwork = %d, %v, want %d, %v", got, known, tt.want, tt.known)
+			}
+		})
+	}
+}
+
+func TestServerNetworkWithoutGenesis(t *testing.T) {
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return queryconnect.NewQueryServiceHandler(queryconnect.UnimplementedQueryServiceHandler{})
+	})
+	if _, known := client.serverNetwork(context.Background()); known {
+		t.Fatal("serverNetwork reports a network without a genesis")
+	}
+	address, _ := testAddress(t, 0x11, 0x22)
+	addr, err := common.NewAddress(address)
+	if err != nil {
+		t.Fatalf("NewAddress returned error: %v", err)
+	}
+	if err := client.checkNetwork(context.Background(), &addr); err != nil {
+		t.Fatalf("checkNetwork returned error: %v", err)
+	}
+}
+
+func TestAddressSearchSkipsNetworkCheckWhenGenesisFails(t *testing.T) {
+	for _, code := range []connect.Code{connect.CodePermissionDenied, connect.CodeUnavailable} {
+		t.Run(code.String(), func(t *testing.T) {
+			fakeQuery := &genesisFailingQueryHandler{code: code}
+			client := newTestServerClient(t, func() (string, http.Handler) {
+				return queryconnect.NewQueryServiceHandler(fakeQuery)
+			})
+			_, raw := testAddress(t, 0x11, 0x22)
+			for range 2 {
+				if _, err := client.GetUtxosByAddress(raw); err != nil {
+					t.Fatalf("GetUtxosByAddress returned error: %v", err)
+				}
+			}
+			if fakeQuery.searches != 2 {
+				t.Fatalf("SearchUtxos called %d times, want 2", fakeQuery.searches)
+			}
+			// Only transient failures are retried on the next search.
+			want := 1
+			if code == connect.CodeUnavailable {
+				want = 2
+			}
+			if fakeQuery.genesisReads != want {
+				t.Fatalf("ReadGenesis called %d times, want %d", fakeQuery.genesisReads, want)
+			}
+		})
+	}
+}
+
+// genesisFailingQueryHandler fails ReadGenesis with code and answers
+// SearchUtxos with no UTxOs.
+type genesisFailingQueryHandler struct {
+	queryconnect.UnimplementedQueryServiceHandler
+	code         connect.Code
+	genesisReads int
+	searches     int
+}
+
+func (q *genesisFailingQueryHandler) ReadGenesis(
+	context.Context,
+	*connect.Request[query.ReadGenesisRequest],
+) (*connect.Response[query.ReadGenesisResponse], error) {
+	q.genesisReads++
+	return nil, connect.NewError(q.code, errors.New("genesis unavailable"))
+}
+
+func (q *genesisFailingQueryHandler) SearchUtxos(
+	context.Context,
+	*connect.Request[query.SearchUtxosRequest],
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	q.searches++
+	return connect.NewResponse(&query.SearchUtxosResponse{}), nil
+}
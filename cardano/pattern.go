@@ -0,0 +1,145 @@
+package cardano
+
+import (
+	"errors"
+	"fmt"
+
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"google.golang.org/protobuf/proto"
+)
+
+// OutputPattern builds a [chaincardano.TxOutputPattern] from human-readable
+// inputs. Each setter validates its argument; the first failure is kept and
+// returned by [OutputPattern.Build]. All fields set on one pattern must
+// match together, and each may be set once: Policy and Asset both set the
+// asset field.
+//
+//	pattern := cardano.NewOutputPattern().
+//	    Address("addr1...").
+//	    Policy("29d222ce763455e3d7a09a665ce554f00ac89d2e99a1a83d267170c6")
+type OutputPattern struct {
+	address *chaincardano.AddressPattern
+	asset   *chaincardano.AssetPattern
+	err     error
+}
+
+// NewOutputPattern returns an empty [OutputPattern].
+func NewOutputPattern() *OutputPattern {
+	return &OutputPattern{}
+}
+
+// Address matches outputs locked at an exact address, given as bech32,
+// base58 (Byron) or hex.
+func (p *OutputPattern) Address(address string) *OutputPattern {
+	p.setAddress("address", address, ParseAddress, func(a *chaincardano.AddressPattern) *[]byte {
+		return &a.ExactAddress
+	})
+	return p
+}
+
+// PaymentPart matches outputs whose address has the given payment
+// credential: a 28-byte hex hash, a bech32 credential, or an address whose
+// payment part is used.
+func (p *OutputPattern) PaymentPart(credential string) *OutputPattern {
+	p.setAddress("payment part", credential, decodePaymentPart, func(a *chaincardano.AddressPattern) *[]byte {
+		return &a.PaymentPart
+	})
+	return p
+}
+
+// DelegationPart matches outputs whose address has the given stake
+// credential: a 28-byte hex hash, a bech32 credential, or an address (such
+// as a stake address) whose delegation part is used.
+func (p *OutputPattern) DelegationPart(credential string) *OutputPattern {
+	p.setAddress("delegation part", credential, decodeDelegationPart, func(a *chaincardano.AddressPattern) *[]byte {
+		return &a.DelegationPart
+	})
+	return p
+}
+
+// Policy matches outputs holding any asset of a hex policy ID.
+func (p *OutputPattern) Policy(policyID string) *OutputPattern {
+	p.setAsset(policyID, "")
+	return p
+}
+
+// Asset matches outputs holding a specific asset. Both arguments are hex;
+// either may be empty to match on the other alone.
+func (p *OutputPattern) Asset(policyID, assetName string) *OutputPattern {
+	p.setAsset(policyID, assetName)
+	return p
+}
+
+// Build returns the pattern, or the first validation error. A pattern with
+// no fields set is an error, since it would match every output.
+func (p *OutputPattern) Build() (*chaincardano.TxOutputPattern, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.address == nil && p.asset == nil {
+		return nil, errors.New("output pattern has no fields set")
+	}
+	pattern := &chaincardano.TxOutputPattern{Address: p.address, Asset: p.asset}
+	return proto.Clone(pattern).(*chaincardano.TxOutputPattern), nil
+}
+
+func (p *OutputPattern) setAddress(
+	field string,
+	value string,
+	decode func(string) ([]byte, error),
+	target func(*chaincardano.AddressPattern) *[]byte,
+) {
+	if p.err != nil {
+		return
+	}
+	if p.address != nil && *target(p.address) != nil {
+		p.err = errPatternFieldSet(field)
+		return
+	}
+	raw, err := decode(value)
+	if err != nil {
+		p.err = err
+		return
+	}
+	if p.address == nil {
+		p.address = &chaincardano.AddressPattern{}
+	}
+	*target(p.address) = raw
+}
+
+func (p *OutputPattern) setAsset(policyID, assetName string) {
+	if p.err != nil {
+		return
+	}
+	if p.asset != nil {
+		p.err = errPatternFieldSet("asset")
+		return
+	}
+	p.asset, p.err = newAssetPattern(policyID, assetName)
+}
+
+// newAssetPattern builds an asset pattern from hex inputs. At least one of
+// policyID and assetName must be non-empty.
+func newAssetPattern(policyID, assetName string) (*chaincardano.AssetPattern, error) {
+	if policyID == "" && assetName == "" {
+		return nil, errors.New("at least one of policyId or assetName must be provided")
+	}
+	pattern := &chaincardano.AssetPattern{}
+	var err error
+	if policyID != "" {
+		if pattern.PolicyId, err = decodePolicyID(policyID); err != nil {
+			return nil, err
+		}
+	}
+	if assetName != "" {
+		if pattern.AssetName, err = decodeAssetName(assetName); err != nil {
+			return nil, err
+		}
+	}
+	return pattern, nil
+}
+
+// errPatternFieldSet reports a pattern field that was assigned twice.
+func errPatternFieldSet(field string) error {
+	return fmt.Errorf("%s is already set; combine patterns with AllOf instead", field)
+}
@@ -3,10 +3,12 @@ package cardano
 import (
 	"context"
 	"errors"
+	"fmt"
 	"iter"
 	"slices"
 
 	"connectrpc.com/connect"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
 	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
 	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
 	"google.golang.org/protobuf/proto"
@@ -60,8 +62,59 @@ func newSearchRequest(
 	return req
 }
 
+// addressPattern resolves raw address bytes into the pattern matching the
+// UTxOs at exactly that address. Stake addresses hold no UTxOs and are
+// rejected. The address must be on the server's network.
+func (c *Client) addressPattern(
+	ctx context.Context,
+	raw []byte,
+) (*chaincardano.AddressPattern, error) {
+	addr, err := common.NewAddressFromBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %x: %w", raw, err)
+	}
+	switch addr.Type() {
+	case common.AddressTypeNoneKey, common.AddressTypeNoneScript:
+		return nil, fmt.Errorf(
+			"invalid address %s: a stake address holds no UTxOs, search by stake credential instead",
+			addr.String(),
+		)
+	}
+	if err := c.checkNetwork(ctx, &addr); err != nil {
+		return nil, err
+	}
+	return &chaincardano.AddressPattern{ExactAddress: raw}, nil
+}
+
+// credentialPattern resolves a payment or stake credential into the
+// pattern matching every address that embeds it. A credential given as an
+// address must be on the server's network.
+func (c *Client) credentialPattern(
+	ctx context.Context,
+	credential string,
+	delegation bool,
+) (*chaincardano.AddressPattern, error) {
+	kind := paymentCredentialKind
+	if delegation {
+		kind = stakeCredentialKind
+	}
+	hash, addr, err := decodeCredential(credential, kind)
+	if err != nil {
+		return nil, err
+	}
+	if addr != nil {
+		if err := c.checkNetwork(ctx, addr); err != nil {
+			return nil, err
+		}
+	}
+	if delegation {
+		return &chaincardano.AddressPattern{DelegationPart: hash}, nil
+	}
+	return &chaincardano.AddressPattern{PaymentPart: hash}, nil
+}
+
 func newAddressSearchRequest(
-	address []byte,
+	address *chaincardano.AddressPattern,
 	options ...SearchOption,
 ) *query.SearchUtxosRequest {
 	return newSearchRequest(
@@ -69,9 +122,7 @@ func newAddressSearchRequest(
 			Match: &query.AnyUtxoPattern{
 				UtxoPattern: &query.AnyUtxoPattern_Cardano{
 					Cardano: &chaincardano.TxOutputPattern{
-						Address: &chaincardano.AddressPattern{
-							ExactAddress: address,
-						},
+						Address: address,
 					},
 				},
 			},
@@ -81,38 +132,18 @@ func newAddressSearchRequest(
 }
 
 func newAddressAssetSearchRequest(
-	addressBytes []byte,
-	policyIDBytes []byte,
-	assetNameBytes []byte,
+	address *chaincardano.AddressPattern,
+	asset AssetID,
 	options ...SearchOption,
 ) *query.SearchUtxosRequest {
-	pattern := &chaincardano.TxOutputPattern{
-		Address: &chaincardano.AddressPattern{
-			ExactAddress: addressBytes,
-		},
-	}
-
-	switch {
-	case len(policyIDBytes) > 0 && len(assetNameBytes) > 0:
-		pattern.Asset = &chaincardano.AssetPattern{
-			PolicyId:  policyIDBytes,
-			AssetName: assetNameBytes,
-		}
-	case len(policyIDBytes) > 0:
-		pattern.Asset = &chaincardano.AssetPattern{
-			PolicyId: policyIDBytes,
-		}
-	case len(assetNameBytes) > 0:
-		pattern.Asset = &chaincardano.AssetPattern{
-			AssetName: assetNameBytes,
-		}
-	}
-
 	return newSearchRequest(
 		&query.UtxoPredicate{
 			Match: &query.AnyUtxoPattern{
 				UtxoPattern: &query.AnyUtxoPattern_Cardano{
-					Cardano: pattern,
+					Cardano: &chaincardano.TxOutputPattern{
+						Address: address,
+						Asset:   asset.pattern(),
+					},
 				},
 			},
 		},
@@ -121,24 +152,24 @@ func newAddressAssetSearchRequest(
 }
 
 func newAssetSearchRequest(
-	policyIDBytes []byte,
-	assetNameBytes []byte,
+	asset AssetID,
 	options ...SearchOption,
 ) (*query.SearchUtxosRequest, error) {
-	if policyIDBytes == nil && assetNameBytes == nil {
+	assetPattern := asset.pattern()
+	if assetPattern == nil {
+		if asset.Fingerprint != "" {
+			return nil, fmt.Errorf(
+				"asset fingerprint %s cannot be searched on its own: it is a hash "+
+					"that does not reveal the policy ID; pass the policy ID too or "+
+					"search within an address",
+				asset.Fingerprint,
+			)
+		}
 		return nil, errors.New(
 			"at least one of policyId or assetName must be provided",
 		)
 	}
 
-	assetPattern := &chaincardano.AssetPattern{}
-	if policyIDBytes != nil {
-		assetPattern.PolicyId = policyIDBytes
-	}
-	if assetNameBytes != nil {
-		assetPattern.AssetName = assetNameBytes
-	}
-
 	return newSearchRequest(
 		&query.UtxoPredicate{
 			Match: &query.AnyUtxoPattern{
@@ -153,6 +184,37 @@ func newAssetSearchRequest(
 	), nil
 }
 
+// searchPages lazily returns all SearchUtxos pages for the request built
+// by newRequest, which runs when iteration starts, with each page filtered
+// by asset. The error of newRequest is yielded as the sequence's first error
+// without making a search RPC.
+func (c *Client) searchPages(
+	ctx context.Context,
+	asset AssetID,
+	newRequest func() (*query.SearchUtxosRequest, error),
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return func(yield func(
+		*connect.Response[query.SearchUtxosResponse],
+		error,
+	) bool,
+	) {
+		req, err := newRequest()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		pages := c.UtxorpcClient.SearchUtxosPagesWithContext(ctx, connect.NewRequest(req))
+		for resp, err := range pages {
+			if resp != nil {
+				asset.filter(resp.Msg)
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
+
 // GetUtxosByAddressPages calls
 // [Client.GetUtxosByAddressPagesWithContext] with a background context.
 func (c *Client) GetUtxosByAddressPages(
@@ -167,14 +229,20 @@ func (c *Client) GetUtxosByAddressPages(
 }
 
 // GetUtxosByAddressPagesWithContext lazily returns all SearchUtxos pages for
-// an exact Cardano address.
+// an address, accepted as in [Client.GetUtxosByAddressWithContext]. An
+// invalid address is yielded as the sequence's first error.
 func (c *Client) GetUtxosByAddressPagesWithContext(
 	ctx context.Context,
 	address []byte,
 	options ...SearchOption,
 ) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
-	req := connect.NewRequest(newAddressSearchRequest(address, options...))
-	return c.UtxorpcClient.SearchUtxosPagesWithContext(ctx, req)
+	return c.searchPages(ctx, AssetID{}, func() (*query.SearchUtxosRequest, error) {
+		pattern, err := c.addressPattern(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		return newAddressSearchRequest(pattern, options...), nil
+	})
 }
 
 // GetUtxosByAddressWithAssetPages calls
@@ -196,7 +264,9 @@ func (c *Client) GetUtxosByAddressWithAssetPages(
 }
 
 // GetUtxosByAddressWithAssetPagesWithContext lazily returns all SearchUtxos
-// pages for an exact Cardano address and optional native asset.
+// pages for an address and optional native asset, accepted as in
+// [Client.GetUtxosByAddressWithAssetWithContext]. An invalid address is
+// yielded as the sequence's first error.
 func (c *Client) GetUtxosByAddressWithAssetPagesWithContext(
 	ctx context.Context,
 	addressBytes []byte,
@@ -204,15 +274,109 @@ func (c *Client) GetUtxosByAddressWithAssetPagesWithContext(
 	assetNameBytes []byte,
 	options ...SearchOption,
 ) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
-	req := connect.NewRequest(
-		newAddressAssetSearchRequest(
-			addressBytes,
-			policyIDBytes,
-			assetNameBytes,
-			options...,
-		),
+	return c.GetUtxosByAddressWithAssetIDPagesWithContext(
+		ctx,
+		addressBytes,
+		AssetID{PolicyID: policyIDBytes, Name: assetNameBytes},
+		options...,
+	)
+}
+
+// GetUtxosByAddressWithAssetIDPages calls
+// [Client.GetUtxosByAddressWithAssetIDPagesWithContext] with a background
+// context.
+func (c *Client) GetUtxosByAddressWithAssetIDPages(
+	addressBytes []byte,
+	asset AssetID,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.GetUtxosByAddressWithAssetIDPagesWithContext(
+		context.Background(),
+		addressBytes,
+		asset,
+		options...,
+	)
+}
+
+// GetUtxosByAddressWithAssetIDPagesWithContext lazily returns all
+// SearchUtxos pages for an address and asset, accepted as in
+// [Client.GetUtxosByAddressWithAssetIDWithContext]. An invalid address is
+// yielded as the sequence's first error.
+func (c *Client) GetUtxosByAddressWithAssetIDPagesWithContext(
+	ctx context.Context,
+	addressBytes []byte,
+	asset AssetID,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.searchPages(ctx, asset, func() (*query.SearchUtxosRequest, error) {
+		pattern, err := c.addressPattern(ctx, addressBytes)
+		if err != nil {
+			return nil, err
+		}
+		return newAddressAssetSearchRequest(pattern, asset, options...), nil
+	})
+}
+
+// GetUtxosByPaymentCredentialPages calls
+// [Client.GetUtxosByPaymentCredentialPagesWithContext] with a background
+// context.
+func (c *Client) GetUtxosByPaymentCredentialPages(
+	credential string,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.GetUtxosByPaymentCredentialPagesWithContext(
+		context.Background(),
+		credential,
+		options...,
+	)
+}
+
+// GetUtxosByPaymentCredentialPagesWithContext lazily returns all
+// SearchUtxos pages for a payment credential, accepted as in
+// [Client.GetUtxosByPaymentCredentialWithContext].
+func (c *Client) GetUtxosByPaymentCredentialPagesWithContext(
+	ctx context.Context,
+	credential string,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.searchPages(ctx, AssetID{}, func() (*query.SearchUtxosRequest, error) {
+		pattern, err := c.credentialPattern(ctx, credential, false)
+		if err != nil {
+			return nil, err
+		}
+		return newAddressSearchRequest(pattern, options...), nil
+	})
+}
+
+// GetUtxosByStakeCredentialPages calls
+// [Client.GetUtxosByStakeCredentialPagesWithContext] with a background
+// context.
+func (c *Client) GetUtxosByStakeCredentialPages(
+	credential string,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.GetUtxosByStakeCredentialPagesWithContext(
+		context.Background(),
+		credential,
+		options...,
 	)
-	return c.UtxorpcClient.SearchUtxosPagesWithContext(ctx, req)
+}
+
+// GetUtxosByStakeCredentialPagesWithContext lazily returns all SearchUtxos
+// pages for a stake credential, accepted as in
+// [Client.GetUtxosByStakeCredentialWithContext].
+func (c *Client) GetUtxosByStakeCredentialPagesWithContext(
+	ctx context.Context,
+	credential string,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.searchPages(ctx, AssetID{}, func() (*query.SearchUtxosRequest, error) {
+		pattern, err := c.credentialPattern(ctx, credential, true)
+		if err != nil {
+			return nil, err
+		}
+		return newAddressSearchRequest(pattern, options...), nil
+	})
 }
 
 // GetUtxosByAssetPages calls [Client.GetUtxosByAssetPagesWithContext] with a
@@ -239,22 +403,31 @@ func (c *Client) GetUtxosByAssetPagesWithContext(
 	assetNameBytes []byte,
 	options ...SearchOption,
 ) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
-	req, err := newAssetSearchRequest(
-		policyIDBytes,
-		assetNameBytes,
-		options...,
-	)
-	if err != nil {
-		return func(yield func(
-			*connect.Response[query.SearchUtxosResponse],
-			error,
-		) bool,
-		) {
-			yield(nil, err)
-		}
-	}
-	return c.UtxorpcClient.SearchUtxosPagesWithContext(
+	return c.GetUtxosByAssetIDPagesWithContext(
 		ctx,
-		connect.NewRequest(req),
+		AssetID{PolicyID: policyIDBytes, Name: assetNameBytes},
+		options...,
 	)
 }
+
+// GetUtxosByAssetIDPages calls [Client.GetUtxosByAssetIDPagesWithContext]
+// with a background context.
+func (c *Client) GetUtxosByAssetIDPages(
+	asset AssetID,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.GetUtxosByAssetIDPagesWithContext(context.Background(), asset, options...)
+}
+
+// GetUtxosByAssetIDPagesWithContext lazily returns all SearchUtxos pages for
+// an asset, accepted as in [Client.GetUtxosByAssetIDWithContext]. An invalid
+// asset is yielded as the sequence's first error without making an RPC.
+func (c *Client) GetUtxosByAssetIDPagesWithContext(
+	ctx context.Context,
+	asset AssetID,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.searchPages(ctx, asset, func() (*query.SearchUtxosRequest, error) {
+		return newAssetSearchRequest(asset, options...)
+	})
+}
@@ -0,0 +1,263 @@
+package cardano
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// setTagPrefix is the CBOR header of tag 258, which Conway allows around the
+// vkey witness array to mark it as a set.
+var setTagPrefix = []byte{0xd9, 0x01, 0x02}
+
+// witnessSetVkeyKey is the witness set map key holding vkey witnesses.
+const witnessSetVkeyKey = 0
+
+// Signer signs transaction body hashes with an Ed25519 key. Implementations
+// may hold the key in memory or delegate to a device or remote service.
+type Signer interface {
+	// PublicKey returns the 32-byte Ed25519 verification key.
+	PublicKey() []byte
+	// Sign returns the 64-byte Ed25519 signature of message.
+	Sign(message []byte) ([]byte, error)
+}
+
+// KeyHash returns the Blake2b-224 hash of the signer's verification key: its
+// payment or stake credential, and the value to list as a required signer.
+func KeyHash(signer Signer) []byte {
+	return common.Blake2b224Hash(signer.PublicKey()).Bytes()
+}
+
+// Ed25519Signer is a [Signer] holding a plain Ed25519 key in memory, such as
+// a key generated by cardano-cli address key-gen.
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewEd25519Signer returns a signer for a 32-byte Ed25519 seed (the
+// cborHex payload of a cardano-cli signing key, without its 5820 prefix) or
+// a 64-byte seed and public key as used by crypto/ed25519.
+func NewEd25519Signer(key []byte) (*Ed25519Signer, error) {
+	switch len(key) {
+	case ed25519.SeedSize:
+		return &Ed25519Signer{key: ed25519.NewKeyFromSeed(key)}, nil
+	case ed25519.PrivateKeySize:
+		priv := ed25519.NewKeyFromSeed(key[:ed25519.SeedSize])
+		if !bytes.Equal(priv[ed25519.SeedSize:], key[ed25519.SeedSize:]) {
+			return nil, errors.New("public key does not match the seed")
+		}
+		return &Ed25519Signer{key: priv}, nil
+	default:
+		return nil, fmt.Errorf("ed25519 key is %d bytes, want 32 or 64", len(key))
+	}
+}
+
+func (s *Ed25519Signer) PublicKey() []byte {
+	return bytes.Clone(s.key.Public().(ed25519.PublicKey))
+}
+
+func (s *Ed25519Signer) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, message), nil
+}
+
+// ExtendedSigner is a [Signer] holding a BIP32-Ed25519 extended key in
+// memory, as derived by Cardano HD wallets (CIP-1852). Derivation is left
+// to the wallet; the signer only needs the derived key.
+type ExtendedSigner struct {
+	kl  *edwards25519.Scalar
+	kr  []byte
+	pub []byte
+}
+
+// NewExtendedSigner returns a signer for an extended private key: 64 bytes
+// kL‖kR, optionally followed by further bytes such as the chain code (96
+// bytes, as in cardano-serialization-lib) or the public key and chain code
+// (128 bytes, the cborHex payload of a cardano-cli extended signing key),
+// which are ignored.
+func NewExtendedSigner(key []byte) (*ExtendedSigner, error) {
+	if len(key) != 64 && len(key) != 96 && len(key) != 128 {
+		return nil, fmt.Errorf("extended key is %d bytes, want 64, 96 or 128", len(key))
+	}
+	if key[0]&0b111 != 0 || key[31]&0x80 != 0 {
+		return nil, errors.New("extended key is not a valid BIP32-Ed25519 scalar")
+	}
+	// kL is below 2^255; reducing it as a 512-bit value keeps it exact.
+	wide := make([]byte, 64)
+	copy(wide, key[:32])
+	kl, err := edwards25519.NewScalar().SetUniformBytes(wide)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load extended key: %w", err)
+	}
+	return &ExtendedSigner{
+		kl:  kl,
+		kr:  bytes.Clone(key[32:64]),
+		pub: new(edwards25519.Point).ScalarBaseMult(kl).Bytes(),
+	}, nil
+}
+
+func (s *ExtendedSigner) PublicKey() []byte {
+	return bytes.Clone(s.pub)
+}
+
+// Sign signs message as in RFC 8032, with kL as the secret scalar and kR as
+// the nonce prefix.
+func (s *ExtendedSigner) Sign(message []byte) ([]byte, error) {
+	h := sha512.New()
+	h.Write(s.kr)
+	h.Write(message)
+	r, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	rPoint := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	h.Reset()
+	h.Write(rPoint)
+	h.Write(s.pub)
+	h.Write(message)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	sig := edwards25519.NewScalar().MultiplyAdd(k, s.kl, r)
+	return append(rPoint, sig.Bytes()...), nil
+}
+
+// VkeyWitness is a verification key and its signature of a transaction
+// body hash.
+type VkeyWitness struct {
+	Vkey      []byte
+	Signature []byte
+}
+
+// CreateVkeyWitness signs the body hash of a transaction with signer,
+// without attaching the witness. txCbor is hex. Use it to collect
+// signatures from several parties, then merge them with
+// [AddVkeyWitnesses].
+func CreateVkeyWitness(txCbor string, signer Signer) (VkeyWitness, error) {
+	parts, err := splitTransaction(txCbor)
+	if err != nil {
+		return VkeyWitness{}, err
+	}
+	return createVkeyWitness(parts[0], signer)
+}
+
+// SignTransaction signs the body hash of a transaction with each signer
+// and attaches the witnesses, returning the signed transaction as hex.
+// See [AddVkeyWitnesses] for how witnesses are merged.
+func SignTransaction(txCbor string, signers ...Signer) (string, error) {
+	parts, err := splitTransaction(txCbor)
+	if err != nil {
+		return "", err
+	}
+	witnesses := make([]VkeyWitness, 0, len(signers))
+	for _, signer := range signers {
+		witness, err := createVkeyWitness(parts[0], signer)
+		if err != nil {
+			return "", err
+		}
+		witnesses = append(witnesses, witness)
+	}
+	return joinWithWitnesses(parts, witnesses)
+}
+
+// AddVkeyWitnesses merges witnesses into the witness set of a transaction
+// given as hex, returning the result as hex. A witness replaces an existing
+// one for the same key. Other witnesses (scripts, datums, redeemers,
+// bootstrap witnesses) and the body are carried over byte for byte, so the
+// transaction id and script data hash are unchanged.
+func AddVkeyWitnesses(txCbor string, witnesses ...VkeyWitness) (string, error) {
+	parts, err := splitTransaction(txCbor)
+	if err != nil {
+		return "", err
+	}
+	return joinWithWitnesses(parts, witnesses)
+}
+
+func createVkeyWitness(body []byte, signer Signer) (VkeyWitness, error) {
+	hash := common.Blake2b256Hash(body)
+	signature, err := signer.Sign(hash.Bytes())
+	if err != nil {
+		return VkeyWitness{}, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return VkeyWitness{Vkey: signer.PublicKey(), Signature: signature}, nil
+}
+
+// splitTransaction decodes hex transaction CBOR into its top-level items:
+// body, witness set, and for Alonzo onwards the validity flag and auxiliary
+// data, each as the original bytes.
+func splitTransaction(txCbor string) ([]cbor.RawMessage, error) {
+	raw, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	var parts []cbor.RawMessage
+	if _, err := cbor.Decode(raw, &parts); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("failed to decode transaction: %d top-level items, want at least 2", len(parts))
+	}
+	return parts, nil
+}
+
+// joinWithWitnesses re-encodes a split transaction with witnesses merged
+// into its witness set.
+func joinWithWitnesses(parts []cbor.RawMessage, witnesses []VkeyWitness) (string, error) {
+	var witnessSet map[uint64]cbor.RawMessage
+	if _, err := cbor.Decode(parts[1], &witnessSet); err != nil {
+		return "", fmt.Errorf("failed to decode witness set: %w", err)
+	}
+	if witnessSet == nil {
+		witnessSet = make(map[uint64]cbor.RawMessage)
+	}
+
+	existing := witnessSet[witnessSetVkeyKey]
+	tagged := bytes.HasPrefix(existing, setTagPrefix)
+	var vkeys []common.VkeyWitness
+	if len(existing) > 0 {
+		if _, err := cbor.Decode(bytes.TrimPrefix(existing, setTagPrefix), &vkeys); err != nil {
+			return "", fmt.Errorf("failed to decode vkey witnesses: %w", err)
+		}
+	}
+	for _, witness := range witnesses {
+		merged := common.VkeyWitness{Vkey: witness.Vkey, Signature: witness.Signature}
+		i := 0
+		for i < len(vkeys) && !bytes.Equal(vkeys[i].Vkey, witness.Vkey) {
+			i++
+		}
+		if i < len(vkeys) {
+			vkeys[i] = merged
+		} else {
+			vkeys = append(vkeys, merged)
+		}
+	}
+	if len(vkeys) > 0 {
+		encoded, err := cbor.Encode(vkeys)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode vkey witnesses: %w", err)
+		}
+		if tagged {
+			encoded = append(bytes.Clone(setTagPrefix), encoded...)
+		}
+		witnessSet[witnessSetVkeyKey] = encoded
+	}
+
+	encodedSet, err := cbor.Encode(witnessSet)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode witness set: %w", err)
+	}
+	parts = append([]cbor.RawMessage{parts[0], encodedSet}, parts[2:]...)
+	encoded, err := cbor.Encode(parts)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	return hex.EncodeToString(encoded), nil
+}
@@ -0,0 +1,118 @@
+package cardano
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// testSeed is the RFC 8032 test 1 secret key.
+const testSeed = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+
+func TestExtendedSignerMatchesEd25519(t *testing.T) {
+	plain, err := NewEd25519Signer(mustDecodeHex(t, testSeed))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer returned error: %v", err)
+	}
+	// An Ed25519 seed expands to the extended key kL‖kR.
+	expanded := sha512.Sum512(mustDecodeHex(t, testSeed))
+	expanded[0] &= 248
+	expanded[31] &= 127
+	expanded[31] |= 64
+	extended, err := NewExtendedSigner(expanded[:])
+	if err != nil {
+		t.Fatalf("NewExtendedSigner returned error: %v", err)
+	}
+
+	if !bytes.Equal(plain.PublicKey(), extended.PublicKey()) {
+		t.Fatalf("public keys differ: %x vs %x", plain.PublicKey(), extended.PublicKey())
+	}
+	message := []byte("body hash")
+	want, _ := plain.Sign(message)
+	got, err := extended.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign returned error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("signature = %x, want %x", got, want)
+	}
+}
+
+func TestNewExtendedSignerRejectsInvalidKeys(t *testing.T) {
+	if _, err := NewExtendedSigner(make([]byte, 32)); err == nil {
+		t.Fatal("NewExtendedSigner accepted a 32-byte key")
+	}
+	key := make([]byte, 64)
+	key[0] = 1
+	if _, err := NewExtendedSigner(key); err == nil {
+		t.Fatal("NewExtendedSigner accepted an unclamped scalar")
+	}
+}
+
+func TestSignTransaction(t *testing.T) {
+	signer, err := NewEd25519Signer(mustDecodeHex(t, testSeed))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer returned error: %v", err)
+	}
+	// A transaction with a redeemer, which must survive signing.
+	unsigned := validationTx("1a001e8480", "a10581840000008200"+"00")
+	body := unsigned[2:strings.Index(unsigned, "a10581")]
+
+	signed, err := SignTransaction(unsigned, signer)
+	if err != nil {
+		t.Fatalf("SignTransaction returned error: %v", err)
+	}
+	if !strings.HasPrefix(signed, "84"+body) {
+		t.Fatal("signing re-encoded the transaction body")
+	}
+	// Signing again replaces the witness rather than adding a second one.
+	signed, err = SignTransaction(signed, signer)
+	if err != nil {
+		t.Fatalf("SignTransaction returned error: %v", err)
+	}
+
+	tx, err := DecodeTransaction(signed)
+	if err != nil {
+		t.Fatalf("DecodeTransaction returned error: %v", err)
+	}
+	vkeys := tx.Ledger.Witnesses().Vkey()
+	if len(vkeys) != 1 {
+		t.Fatalf("%d vkey witnesses, want 1", len(vkeys))
+	}
+	hash := mustDecodeHex(t, tx.Hash)
+	if !ed25519.Verify(vkeys[0].Vkey, hash, vkeys[0].Signature) {
+		t.Fatal("witness signature does not verify against the body hash")
+	}
+	redeemers := 0
+	for range tx.Ledger.Witnesses().Redeemers().Iter() {
+		redeemers++
+	}
+	if redeemers != 1 {
+		t.Fatalf("%d redeemers after signing, want 1", redeemers)
+	}
+}
+
+func TestAddVkeyWitnessesKeepsSetTag(t *testing.T) {
+	signer, err := NewEd25519Signer(mustDecodeHex(t, testSeed))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer returned error: %v", err)
+	}
+	unsigned := validationTx("1a001e8480", "a1"+"00"+"d9010280")
+	witness, err := CreateVkeyWitness(unsigned, signer)
+	if err != nil {
+		t.Fatalf("CreateVkeyWitness returned error: %v", err)
+	}
+	signed, err := AddVkeyWitnesses(unsigned, witness)
+	if err != nil {
+		t.Fatalf("AddVkeyWitnesses returned error: %v", err)
+	}
+	want := "a1" + "00" + "d90102" + "81" + "82" +
+		"5820" + hex.EncodeToString(witness.Vkey) +
+		"5840" + hex.EncodeToString(witness.Signature)
+	if !strings.Contains(signed, want) {
+		t.Fatalf("signed transaction %s lacks the tagged witness set %s", signed, want)
+	}
+}
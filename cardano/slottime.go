@@ -0,0 +1,303 @@
+package cardano
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// ErrBeyondHorizon is returned by [EraHistory] conversions for slots, times
+// or epochs past the point up to which the era summary can be trusted.
+var ErrBeyondHorizon = errors.New("beyond the era summary forecast horizon")
+
+// GetEraSummary calls [Client.GetEraSummaryWithContext] with a background
+// context.
+func (c *Client) GetEraSummary() (*chaincardano.EraSummaries, error) {
+	return c.GetEraSummaryWithContext(context.Background())
+}
+
+// GetEraSummaryWithContext fetches the Cardano era summaries via
+// Query.ReadEraSummary. Returns an error if the server does not return a
+// Cardano summary.
+func (c *Client) GetEraSummaryWithContext(
+	ctx context.Context,
+) (*chaincardano.EraSummaries, error) {
+	req := connect.NewRequest(&query.ReadEraSummaryRequest{})
+	resp, err := c.UtxorpcClient.ReadEraSummaryWithContext(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read era summary: %w", err)
+	}
+	summaries := resp.Msg.GetCardano()
+	if summaries == nil || len(summaries.GetSummaries()) == 0 {
+		return nil, errors.New("received no cardano era summary")
+	}
+	return summaries, nil
+}
+
+type eraSpan struct {
+	name        string
+	startSlot   uint64
+	startEpoch  uint64
+	startTime   time.Time
+	slotLength  time.Duration
+	epochLength uint64
+	// endSlot is the first slot after the era; math.MaxUint64 if open.
+	endSlot uint64
+}
+
+// EraHistory converts between slots, wall-clock times and epochs across all
+// Cardano eras, using an era summary and genesis configuration loaded once.
+//
+// Conversions are valid up to the horizon: the end of the last era when the
+// summary already fixes it, otherwise the tip plus the stability window
+// (3k/f slots) within which no hard fork can take effect unannounced.
+type EraHistory struct {
+	systemStart time.Time
+	eras        []eraSpan
+	horizon     uint64
+}
+
+// NewEraHistory calls [Client.NewEraHistoryWithContext] with a background
+// context.
+func (c *Client) NewEraHistory() (*EraHistory, error) {
+	return c.NewEraHistoryWithContext(context.Background())
+}
+
+// NewEraHistoryWithContext loads the era summary, genesis configuration and
+// current tip from the server and builds an [EraHistory] from them.
+func (c *Client) NewEraHistoryWithContext(
+	ctx context.Context,
+) (*EraHistory, error) {
+	summaries, err := c.GetEraSummaryWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := c.GetGenesisWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := c.GetTipWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewEraHistory(summaries, genesis, tip.Msg.GetTip().GetSlot())
+}
+
+// NewEraHistory builds an [EraHistory] from an era summary, the genesis
+// configuration and the current tip slot.
+//
+// Era boundary times are read as milliseconds since the genesis system
+// start, as in the node's era summary. Slot and epoch lengths of closed eras
+// are derived from their boundaries. The open (last) era uses the Shelley
+// genesis slot length, in seconds, and epoch length.
+func NewEraHistory(
+	summaries *chaincardano.EraSummaries,
+	genesis *chaincardano.Genesis,
+	tipSlot uint64,
+) (*EraHistory, error) {
+	systemStart, err := time.Parse(time.RFC3339, genesis.GetSystemStart())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse genesis system start: %w", err)
+	}
+	if len(summaries.GetSummaries()) == 0 {
+		return nil, errors.New("era summary contains no eras")
+	}
+
+	h := &EraHistory{systemStart: systemStart}
+	boundaryTime := func(b *chaincardano.EraBoundary) time.Time {
+		// #nosec G115 -- era boundary times fit comfortably in int64 ms
+		return systemStart.Add(time.Duration(b.GetTime()) * time.Millisecond)
+	}
+
+	for i, summary := range summaries.GetSummaries() {
+		start, end := summary.GetStart(), summary.GetEnd()
+		if start == nil {
+			return nil, fmt.Errorf("era %q has no start boundary", summary.GetName())
+		}
+		span := eraSpan{
+			name:       summary.GetName(),
+			startSlot:  start.GetSlot(),
+			startEpoch: start.GetEpoch(),
+			startTime:  boundaryTime(start),
+			endSlot:    math.MaxUint64,
+		}
+		if end != nil {
+			slots := end.GetSlot() - start.GetSlot()
+			epochs := end.GetEpoch() - start.GetEpoch()
+			if end.GetSlot() <= start.GetSlot() || epochs == 0 {
+				return nil, fmt.Errorf("era %q has an empty range", span.name)
+			}
+			span.endSlot = end.GetSlot()
+			span.slotLength = boundaryTime(end).Sub(span.startTime) /
+				time.Duration(slots)
+			span.epochLength = slots / epochs
+		} else {
+			if i != len(summaries.GetSummaries())-1 {
+				return nil, fmt.Errorf("era %q has no end boundary", span.name)
+			}
+			span.slotLength = time.Duration(genesis.GetSlotLength()) * time.Second
+			span.epochLength = uint64(genesis.GetEpochLength())
+			if i > 0 && span.slotLength == 0 {
+				span.slotLength = h.eras[i-1].slotLength
+			}
+			if i > 0 && span.epochLength == 0 {
+				span.epochLength = h.eras[i-1].epochLength
+			}
+			if span.slotLength <= 0 || span.epochLength == 0 {
+				return nil, fmt.Errorf(
+					"cannot determine slot or epoch length of era %q",
+					span.name,
+				)
+			}
+		}
+		h.eras = append(h.eras, span)
+	}
+
+	last := h.eras[len(h.eras)-1]
+	switch {
+	case last.endSlot != math.MaxUint64:
+		h.horizon = last.endSlot
+	case genesis.GetSecurityParam() > 0 &&
+		genesis.GetActiveSlotsCoeff().GetNumerator() > 0:
+		coeff := genesis.GetActiveSlotsCoeff()
+		// 3k/f = 3k * denominator / numerator
+		window := 3 * uint64(genesis.GetSecurityParam()) *
+			uint64(coeff.GetDenominator()) /
+			uint64(coeff.GetNumerator())
+		h.horizon = max(tipSlot, last.startSlot) + window
+	default:
+		h.horizon = math.MaxUint64
+	}
+	return h, nil
+}
+
+// SystemStart returns the wall-clock time of slot 0.
+func (h *EraHistory) SystemStart() time.Time {
+	return h.systemStart
+}
+
+// Horizon returns the first slot that can no longer be converted.
+func (h *EraHistory) Horizon() uint64 {
+	return h.horizon
+}
+
+// IsStale reports whether tipSlot has reached the horizon, past which a
+// hard fork may have happened since the summary was loaded. The
+// [EraHistory] should then be rebuilt.
+func (h *EraHistory) IsStale(tipSlot uint64) bool {
+	return tipSlot >= h.horizon
+}
+
+// EraName returns the name of the era containing slot.
+func (h *EraHistory) EraName(slot uint64) (string, error) {
+	era, err := h.eraForSlot(slot)
+	if err != nil {
+		return "", err
+	}
+	return era.name, nil
+}
+
+// SlotToTime returns the wall-clock start time of slot.
+func (h *EraHistory) SlotToTime(slot uint64) (time.Time, error) {
+	era, err := h.eraForSlot(slot)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// #nosec G115 -- slot offsets within an era fit in int64
+	offset := time.Duration(slot-era.startSlot) * era.slotLength
+	return era.startTime.Add(offset), nil
+}
+
+// TimeToSlot returns the slot in progress at t.
+func (h *EraHistory) TimeToSlot(t time.Time) (uint64, error) {
+	if t.Before(h.systemStart) {
+		return 0, fmt.Errorf("time %s is before system start", t)
+	}
+	for i := len(h.eras) - 1; i >= 0; i-- {
+		era := h.eras[i]
+		if t.Before(era.startTime) {
+			continue
+		}
+		// #nosec G115 -- elapsed is non-negative here
+		slot := era.startSlot + uint64(t.Sub(era.startTime)/era.slotLength)
+		if slot >= era.endSlot || slot >= h.horizon {
+			return 0, fmt.Errorf("%w: time %s", ErrBeyondHorizon, t)
+		}
+		return slot, nil
+	}
+	return 0, fmt.Errorf("time %s is before the first era", t)
+}
+
+// SlotToEpoch returns the epoch containing slot.
+func (h *EraHistory) SlotToEpoch(slot uint64) (uint64, error) {
+	era, err := h.eraForSlot(slot)
+	if err != nil {
+		return 0, err
+	}
+	return era.startEpoch + (slot-era.startSlot)/era.epochLength, nil
+}
+
+// TimeToEpoch returns the epoch in progress at t.
+func (h *EraHistory) TimeToEpoch(t time.Time) (uint64, error) {
+	slot, err := h.TimeToSlot(t)
+	if err != nil {
+		return 0, err
+	}
+	return h.SlotToEpoch(slot)
+}
+
+// EpochStartSlot returns the first slot of epoch.
+func (h *EraHistory) EpochStartSlot(epoch uint64) (uint64, error) {
+	for i := len(h.eras) - 1; i >= 0; i-- {
+		era := h.eras[i]
+		if epoch < era.startEpoch {
+			continue
+		}
+		slot := era.startSlot + (epoch-era.startEpoch)*era.epochLength
+		if slot >= era.endSlot || slot >= h.horizon {
+			return 0, fmt.Errorf("%w: epoch %d", ErrBeyondHorizon, epoch)
+		}
+		return slot, nil
+	}
+	return 0, fmt.Errorf("epoch %d is before the first era", epoch)
+}
+
+// EpochEndSlot returns the last slot of epoch.
+func (h *EraHistory) EpochEndSlot(epoch uint64) (uint64, error) {
+	start, err := h.EpochStartSlot(epoch)
+	if err != nil {
+		return 0, err
+	}
+	era, err := h.eraForSlot(start)
+	if err != nil {
+		return 0, err
+	}
+	end := start + era.epochLength - 1
+	if end >= h.horizon {
+		return 0, fmt.Errorf("%w: end of epoch %d", ErrBeyondHorizon, epoch)
+	}
+	return end, nil
+}
+
+func (h *EraHistory) eraForSlot(slot uint64) (eraSpan, error) {
+	if slot >= h.horizon {
+		return eraSpan{}, fmt.Errorf(
+			"%w: slot %d (horizon %d)",
+			ErrBeyondHorizon,
+			slot,
+			h.horizon,
+		)
+	}
+	for i := len(h.eras) - 1; i >= 0; i-- {
+		if slot >= h.eras[i].startSlot {
+			return h.eras[i], nil
+		}
+	}
+	return eraSpan{}, fmt.Errorf("slot %d is before the first era", slot)
+}
@@ -0,0 +1,151 @@
+package cardano
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+)
+
+// mainnetEraHistory models mainnet's Byron (20s slots, 21600-slot epochs)
+// followed by an open Shelley-based era (1s slots, 432000-slot epochs).
+func mainnetEraHistory(t *testing.T, tipSlot uint64) *EraHistory {
+	t.Helper()
+	summaries := &chaincardano.EraSummaries{
+		Summaries: []*chaincardano.EraSummary{
+			{
+				Name:  "byron",
+				Start: &chaincardano.EraBoundary{},
+				End: &chaincardano.EraBoundary{
+					Time:  89856000000,
+					Slot:  4492800,
+					Epoch: 208,
+				},
+			},
+			{
+				Name: "shelley",
+				Start: &chaincardano.EraBoundary{
+					Time:  89856000000,
+					Slot:  4492800,
+					Epoch: 208,
+				},
+			},
+		},
+	}
+	genesis := &chaincardano.Genesis{
+		SystemStart:      "2017-09-23T21:44:51Z",
+		SlotLength:       1,
+		EpochLength:      432000,
+		SecurityParam:    2160,
+		ActiveSlotsCoeff: &chaincardano.RationalNumber{Numerator: 1, Denominator: 20},
+	}
+	history, err := NewEraHistory(summaries, genesis, tipSlot)
+	if err != nil {
+		t.Fatalf("NewEraHistory returned error: %v", err)
+	}
+	return history
+}
+
+func TestEraHistoryConvertsAcrossEras(t *testing.T) {
+	history := mainnetEraHistory(t, 100_000_000)
+
+	tests := []struct {
+		name  string
+		slot  uint64
+		time  string
+		epoch uint64
+	}{
+		{name: "genesis", slot: 0, time: "2017-09-23T21:44:51Z", epoch: 0},
+		{name: "byron", slot: 21601, time: "2017-09-28T21:45:11Z", epoch: 1},
+		{name: "shelley start", slot: 4492800, time: "2020-07-29T21:44:51Z", epoch: 208},
+		{name: "shelley", slot: 4924900, time: "2020-08-03T21:46:31Z", epoch: 209},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			want, _ := time.Parse(time.RFC3339, test.time)
+			got, err := history.SlotToTime(test.slot)
+			if err != nil {
+				t.Fatalf("SlotToTime returned error: %v", err)
+			}
+			if !got.Equal(want) {
+				t.Fatalf("SlotToTime(%d) = %s, want %s", test.slot, got, want)
+			}
+			slot, err := history.TimeToSlot(want)
+			if err != nil {
+				t.Fatalf("TimeToSlot returned error: %v", err)
+			}
+			if slot != test.slot {
+				t.Fatalf("TimeToSlot(%s) = %d, want %d", want, slot, test.slot)
+			}
+			epoch, err := history.SlotToEpoch(test.slot)
+			if err != nil {
+				t.Fatalf("SlotToEpoch returned error: %v", err)
+			}
+			if epoch != test.epoch {
+				t.Fatalf("SlotToEpoch(%d) = %d, want %d", test.slot, epoch, test.epoch)
+			}
+		})
+	}
+
+	for epoch, want := range map[uint64][2]uint64{
+		1:   {21600, 43199},
+		207: {4471200, 4492799},
+		209: {4924800, 5356799},
+	} {
+		start, err := history.EpochStartSlot(epoch)
+		if err != nil {
+			t.Fatalf("EpochStartSlot(%d) returned error: %v", epoch, err)
+		}
+		end, err := history.EpochEndSlot(epoch)
+		if err != nil {
+			t.Fatalf("EpochEndSlot(%d) returned error: %v", epoch, err)
+		}
+		if start != want[0] || end != want[1] {
+			t.Fatalf("epoch %d = [%d, %d], want %v", epoch, start, end, want)
+		}
+	}
+}
+
+func TestEraHistoryHorizonAndStaleness(t *testing.T) {
+	history := mainnetEraHistory(t, 10_000_000)
+
+	// 3k/f = 3 * 2160 * 20 slots past the tip.
+	if got, want := history.Horizon(), uint64(10_000_000+129_600); got != want {
+		t.Fatalf("Horizon() = %d, want %d", got, want)
+	}
+	if _, err := history.SlotToTime(history.Horizon()); !errors.Is(err, ErrBeyondHorizon) {
+		t.Fatalf("SlotToTime past horizon error = %v, want ErrBeyondHorizon", err)
+	}
+	if _, err := history.EpochStartSlot(1000); !errors.Is(err, ErrBeyondHorizon) {
+		t.Fatalf("EpochStartSlot past horizon error = %v, want ErrBeyondHorizon", err)
+	}
+	if history.IsStale(10_100_000) {
+		t.Fatal("IsStale = true for a tip within the horizon")
+	}
+	if !history.IsStale(history.Horizon()) {
+		t.Fatal("IsStale = false for a tip at the horizon")
+	}
+
+	closed := &chaincardano.EraSummaries{
+		Summaries: []*chaincardano.EraSummary{{
+			Name:  "byron",
+			Start: &chaincardano.EraBoundary{},
+			End:   &chaincardano.EraBoundary{Time: 432000000, Slot: 21600, Epoch: 1},
+		}},
+	}
+	byronOnly, err := NewEraHistory(
+		closed,
+		&chaincardano.Genesis{SystemStart: "2017-09-23T21:44:51Z"},
+		0,
+	)
+	if err != nil {
+		t.Fatalf("NewEraHistory returned error: %v", err)
+	}
+	if !byronOnly.IsStale(21600) {
+		t.Fatal("IsStale = false for tip past the last era end")
+	}
+	if _, err := byronOnly.SlotToEpoch(21600); !errors.Is(err, ErrBeyondHorizon) {
+		t.Fatalf("SlotToEpoch past era end error = %v, want ErrBeyondHorizon", err)
+	}
+}
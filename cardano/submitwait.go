@@ -0,0 +1,316 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+)
+
+const defaultDepthPollInterval = 5 * time.Second
+
+var (
+	// ErrTxRejected reports that the server refused a submitted transaction.
+	ErrTxRejected = errors.New("transaction rejected")
+	// ErrTxDropped reports that a transaction left the server's view (the
+	// mempool or, after confirming, the chain) before reaching its target.
+	ErrTxDropped = errors.New("transaction dropped")
+	// ErrTxDeadline reports that the context ended before a transaction
+	// reached its target.
+	ErrTxDeadline = errors.New("deadline passed before transaction reached target")
+	// ErrTxRefMismatch reports that the reference returned by SubmitTx
+	// differs from the transaction id computed locally.
+	ErrTxRefMismatch = errors.New("submitted transaction reference mismatch")
+)
+
+// TxWaitError is returned by [Client.SubmitAndWait]. Err is one of
+// [ErrTxRejected], [ErrTxDropped], [ErrTxDeadline] or [ErrTxRefMismatch]; Cause, when set, is
+// the underlying RPC or context error. Both are visible to [errors.Is] and
+// [errors.As], so connect.CodeOf still reports the server's code.
+type TxWaitError struct {
+	// Result holds the progress made before the failure.
+	Result *SubmitResult
+	Err    error
+	Cause  error
+}
+
+func (e *TxWaitError) Error() string {
+	if e.Cause == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: %v", e.Err, e.Cause)
+}
+
+func (e *TxWaitError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Cause}
+}
+
+// SubmitResult describes how far a transaction submitted by
+// [Client.SubmitAndWait] progressed.
+type SubmitResult struct {
+	// Ref is the transaction reference returned by SubmitTx, or the locally
+	// computed transaction id if the server returned none.
+	Ref []byte
+	// Stages maps each stage reached to the time it was first observed.
+	// Stages the server skipped are recorded at the time a later stage was
+	// observed.
+	Stages map[submit.Stage]time.Time
+	// Block is the block containing the transaction, and Depth the number
+	// of blocks on top of and including it, when a confirmation depth was
+	// requested.
+	Block *query.ChainPoint
+	Depth uint64
+}
+
+// Reached reports whether stage has been observed.
+func (r *SubmitResult) Reached(stage submit.Stage) bool {
+	_, ok := r.Stages[stage]
+	return ok
+}
+
+func (r *SubmitResult) record(stage submit.Stage, at time.Time) {
+	for s := submit.Stage_STAGE_ACKNOWLEDGED; s <= stage; s++ {
+		if _, ok := r.Stages[s]; !ok {
+			r.Stages[s] = at
+		}
+	}
+}
+
+// SubmitWaitOption configures [Client.SubmitAndWait].
+type SubmitWaitOption func(*submitWaitConfig)
+
+type submitWaitConfig struct {
+	stage        submit.Stage
+	depth        uint64
+	pollInterval time.Duration
+}
+
+// WithWaitStage sets the stage SubmitAndWait waits for. The default is
+// submit.Stage_STAGE_CONFIRMED.
+func WithWaitStage(stage submit.Stage) SubmitWaitOption {
+	return func(c *submitWaitConfig) {
+		c.stage = stage
+	}
+}
+
+// WithConfirmationDepth makes SubmitAndWait wait, after confirmation, until
+// the transaction's block is buried under depth-1 further blocks. It
+// implies submit.Stage_STAGE_CONFIRMED.
+func WithConfirmationDepth(depth uint64) SubmitWaitOption {
+	return func(c *submitWaitConfig) {
+		c.depth = depth
+	}
+}
+
+// WithDepthPollInterval sets how often Query.ReadTx is polled while waiting
+// for a confirmation depth. A transaction ReadTx does not find is only
+// reported dropped once it has been missing for a whole interval, giving a
+// lagging index time to catch up. The default is 5 seconds.
+func WithDepthPollInterval(interval time.Duration) SubmitWaitOption {
+	return func(c *submitWaitConfig) {
+		if interval > 0 {
+			c.pollInterval = interval
+		}
+	}
+}
+
+// SubmitAndWait calls [Client.SubmitAndWaitWithContext] with a background
+// context.
+func (c *Client) SubmitAndWait(
+	txCbor string,
+	options ...SubmitWaitOption,
+) (*SubmitResult, error) {
+	return c.SubmitAndWaitWithContext(context.Background(), txCbor, options...)
+}
+
+// SubmitAndWaitWithContext submits a signed transaction (hex CBOR, as for
+// [Client.SubmitTransaction]) and follows Submit.WaitForTx until it reaches
+// the target stage (see [WithWaitStage]) and, if requested, a confirmation
+// depth (see [WithConfirmationDepth]). A successful SubmitTx counts as
+// submit.Stage_STAGE_ACKNOWLEDGED.
+//
+// When the transaction can be decoded locally (see [DecodeTransaction]),
+// its id is compared with the reference SubmitTx returns; a difference
+// stops the wait with [ErrTxRefMismatch].
+//
+// Failures after the transaction could be decoded are returned as a
+// [*TxWaitError]: [ErrTxRejected] if SubmitTx refuses the transaction
+// (InvalidArgument or FailedPrecondition), [ErrTxDropped] if the server
+// stops tracking the transaction or it disappears from the chain while
+// waiting for depth, and [ErrTxDeadline] when ctx or the server deadline
+// ends first. Other RPC and stream errors are wrapped and returned as is.
+func (c *Client) SubmitAndWaitWithContext(
+	ctx context.Context,
+	txCbor string,
+	options ...SubmitWaitOption,
+) (*SubmitResult, error) {
+	cfg := submitWaitConfig{
+		stage:        submit.Stage_STAGE_CONFIRMED,
+		pollInterval: defaultDepthPollInterval,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+	if cfg.depth > 0 {
+		cfg.stage = submit.Stage_STAGE_CONFIRMED
+	}
+
+	txRawBytes, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	// Transactions this SDK cannot decode are still submitted; they just
+	// skip the local cross-check.
+	var localRef []byte
+	if tx, err := decodeLedgerTransaction(txRawBytes); err == nil {
+		localRef = tx.Hash().Bytes()
+	}
+	resp, err := c.SubmitTransactionWithContext(ctx, &submit.SubmitTxRequest{
+		Tx: &submit.AnyChainTx{Type: &submit.AnyChainTx_Raw{Raw: txRawBytes}},
+	})
+	if err != nil {
+		return nil, submitFailure(ctx, err)
+	}
+	result := &SubmitResult{
+		Ref:    resp.Msg.GetRef(),
+		Stages: make(map[submit.Stage]time.Time),
+	}
+	result.record(submit.Stage_STAGE_ACKNOWLEDGED, time.Now())
+	switch {
+	case localRef == nil:
+	case len(result.Ref) == 0:
+		result.Ref = localRef
+	case !bytes.Equal(result.Ref, localRef):
+		return result, &TxWaitError{
+			Result: result,
+			Err:    ErrTxRefMismatch,
+			Cause:  fmt.Errorf("server returned %x, computed %x", result.Ref, localRef),
+		}
+	}
+
+	if cfg.stage > submit.Stage_STAGE_ACKNOWLEDGED {
+		if err := c.waitForStage(ctx, result, cfg.stage); err != nil {
+			return result, err
+		}
+	}
+	if cfg.depth > 0 {
+		if err := c.waitForDepth(ctx, result, cfg.depth, cfg.pollInterval); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) waitForStage(
+	ctx context.Context,
+	result *SubmitResult,
+	target submit.Stage,
+) error {
+	stream, err := c.WaitForTransactionWithContext(ctx, &submit.WaitForTxRequest{
+		Ref: [][]byte{result.Ref},
+	})
+	if err != nil {
+		return waitFailure(ctx, result, err)
+	}
+	defer stream.Close()
+
+	for stream.Receive() {
+		stage := stream.Msg().GetStage()
+		if stage == submit.Stage_STAGE_UNSPECIFIED {
+			return &TxWaitError{Result: result, Err: ErrTxDropped}
+		}
+		result.record(stage, time.Now())
+		if stage >= target {
+			return nil
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return waitFailure(ctx, result, err)
+	}
+	if ctx.Err() != nil {
+		return waitFailure(ctx, result, ctx.Err())
+	}
+	return &TxWaitError{Result: result, Err: ErrTxDropped}
+}
+
+func (c *Client) waitForDepth(
+	ctx context.Context,
+	result *SubmitResult,
+	depth uint64,
+	interval time.Duration,
+) error {
+	// missingSince is when ReadTx last started failing to find the
+	// transaction; its index may lag the WaitForTx confirmation.
+	var missingSince time.Time
+	for {
+		resp, err := c.UtxorpcClient.ReadTxWithContext(
+			ctx,
+			connect.NewRequest(&query.ReadTxRequest{Hash: result.Ref}),
+		)
+		if err != nil && connect.CodeOf(err) != connect.CodeNotFound {
+			return waitFailure(ctx, result, err)
+		}
+		var block, tip *query.ChainPoint
+		if err == nil {
+			block, tip = resp.Msg.GetTx().GetBlockRef(), resp.Msg.GetLedgerTip()
+		}
+		switch {
+		case block != nil:
+			missingSince = time.Time{}
+			result.Block = block
+			if tip.GetHeight() >= block.GetHeight() {
+				result.Depth = tip.GetHeight() - block.GetHeight() + 1
+			}
+			if result.Depth >= depth {
+				return nil
+			}
+		case missingSince.IsZero():
+			missingSince = time.Now()
+		case time.Since(missingSince) >= interval:
+			return &TxWaitError{Result: result, Err: ErrTxDropped, Cause: err}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return waitFailure(ctx, result, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// submitFailure classifies a SubmitTx error: [ErrTxRejected] when the
+// server refused the transaction, [ErrTxDeadline] when time ran out.
+func submitFailure(ctx context.Context, err error) error {
+	switch code := connect.CodeOf(err); {
+	case code == connect.CodeInvalidArgument || code == connect.CodeFailedPrecondition:
+		return &TxWaitError{Err: ErrTxRejected, Cause: err}
+	case code == connect.CodeDeadlineExceeded || ctx.Err() != nil:
+		return &TxWaitError{Err: ErrTxDeadline, Cause: err}
+	default:
+		return fmt.Errorf("failed to submit transaction: %w", err)
+	}
+}
+
+// waitFailure classifies err, reporting context expiry and server deadlines
+// as [ErrTxDeadline].
+func waitFailure(ctx context.Context, result *SubmitResult, err error) error {
+	switch {
+	case ctx.Err() != nil:
+		return &TxWaitError{Result: result, Err: ErrTxDeadline, Cause: ctx.Err()}
+	case connect.CodeOf(err) == connect.CodeDeadlineExceeded:
+		return &TxWaitError{Result: result, Err: ErrTxDeadline, Cause: err}
+	default:
+		return fmt.Errorf("failed to wait for transaction: %w", err)
+	}
+}
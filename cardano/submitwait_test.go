@@ -0,0 +1,241 @@
+package cardano
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+)
+
+func TestSubmitAndWaitReachesConfirmationDepth(t *testing.T) {
+	fakeSubmit := &scriptedSubmitHandler{
+		stages: []submit.Stage{submit.Stage_STAGE_MEMPOOL, submit.Stage_STAGE_CONFIRMED},
+	}
+	fakeQuery := &depthQueryHandler{block: 10, tips: []uint64{10, 12}}
+	client := newSubmitTestClient(t, fakeSubmit, fakeQuery)
+
+	result, err := client.SubmitAndWait(
+		"84a0",
+		WithConfirmationDepth(3),
+		WithDepthPollInterval(time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("SubmitAndWait returned error: %v", err)
+	}
+	if string(result.Ref) != "ref" {
+		t.Fatalf("Ref = %q, want %q", result.Ref, "ref")
+	}
+	for _, stage := range []submit.Stage{
+		submit.Stage_STAGE_ACKNOWLEDGED,
+		submit.Stage_STAGE_MEMPOOL,
+		submit.Stage_STAGE_NETWORK,
+		submit.Stage_STAGE_CONFIRMED,
+	} {
+		if !result.Reached(stage) {
+			t.Fatalf("stage %v not recorded", stage)
+		}
+	}
+	if result.Depth != 3 || result.Block.GetHeight() != 10 {
+		t.Fatalf("depth = %d at height %d, want 3 at 10", result.Depth, result.Block.GetHeight())
+	}
+}
+
+func TestSubmitAndWaitWaitsForLaggingReadTx(t *testing.T) {
+	confirmed := []submit.Stage{submit.Stage_STAGE_CONFIRMED}
+
+	// ReadTx may not know a transaction WaitForTx just confirmed.
+	lagging := &depthQueryHandler{block: 10, tips: []uint64{12}, missing: 1}
+	client := newSubmitTestClient(t, &scriptedSubmitHandler{stages: confirmed}, lagging)
+	result, err := client.SubmitAndWait(
+		"84a0",
+		WithConfirmationDepth(3),
+		WithDepthPollInterval(20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("SubmitAndWait returned error: %v", err)
+	}
+	if result.Depth != 3 || lagging.calls != 2 {
+		t.Fatalf("depth = %d after %d calls, want 3 after 2", result.Depth, lagging.calls)
+	}
+
+	// A transaction still missing after a whole interval was dropped.
+	gone := &depthQueryHandler{block: 10, tips: []uint64{12}, missing: 100}
+	client = newSubmitTestClient(t, &scriptedSubmitHandler{stages: confirmed}, gone)
+	_, err = client.SubmitAndWait(
+		"84a0",
+		WithConfirmationDepth(3),
+		WithDepthPollInterval(time.Millisecond),
+	)
+	if !errors.Is(err, ErrTxDropped) {
+		t.Fatalf("error = %v, want %v", err, ErrTxDropped)
+	}
+	if gone.calls < 2 {
+		t.Fatalf("ReadTx called %d times, want a retry before the drop", gone.calls)
+	}
+}
+
+func TestSubmitAndWaitReportsTypedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		submit  *scriptedSubmitHandler
+		timeout time.Duration
+		want    error
+	}{
+		{
+			name:   "rejected",
+			submit: &scriptedSubmitHandler{reject: true},
+			want:   ErrTxRejected,
+		},
+		{
+			name: "dropped",
+			submit: &scriptedSubmitHandler{stages: []submit.Stage{
+				submit.Stage_STAGE_MEMPOOL,
+				submit.Stage_STAGE_UNSPECIFIED,
+			}},
+			want: ErrTxDropped,
+		},
+		{
+			name:    "deadline",
+			submit:  &scriptedSubmitHandler{hold: true},
+			timeout: 50 * time.Millisecond,
+			want:    ErrTxDeadline,
+		},
+		{
+			name:   "server deadline",
+			submit: &scriptedSubmitHandler{fail: connect.CodeDeadlineExceeded},
+			want:   ErrTxDeadline,
+		},
+		{
+			name:   "server wait deadline",
+			submit: &scriptedSubmitHandler{waitFail: connect.CodeDeadlineExceeded},
+			want:   ErrTxDeadline,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newSubmitTestClient(t, test.submit, &depthQueryHandler{})
+			ctx := context.Background()
+			if test.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, test.timeout)
+				defer cancel()
+			}
+			_, err := client.SubmitAndWaitWithContext(ctx, "84a0")
+			if !errors.Is(err, test.want) {
+				t.Fatalf("error = %v, want %v", err, test.want)
+			}
+			var waitErr *TxWaitError
+			if !errors.As(err, &waitErr) {
+				t.Fatalf("error %T is not a *TxWaitError", err)
+			}
+		})
+	}
+
+	client := newSubmitTestClient(t, &scriptedSubmitHandler{reject: true}, &depthQueryHandler{})
+	_, err := client.SubmitAndWait("84a0")
+	if code := connect.CodeOf(err); code != connect.CodeInvalidArgument {
+		t.Fatalf("connect.CodeOf = %v, want %v", code, connect.CodeInvalidArgument)
+	}
+
+	// Other failures are not reported as rejections.
+	client = newSubmitTestClient(t, &scriptedSubmitHandler{fail: connect.CodeUnavailable}, &depthQueryHandler{})
+	_, err = client.SubmitAndWait("84a0")
+	var waitErr *TxWaitError
+	if errors.As(err, &waitErr) || connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("error = %v, want a plain Unavailable error", err)
+	}
+}
+
+func newSubmitTestClient(
+	t *testing.T,
+	submitHandler submitconnect.SubmitServiceHandler,
+	queryHandler queryconnect.QueryServiceHandler,
+) *Client {
+	t.Helper()
+	return newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return submitconnect.NewSubmitServiceHandler(submitHandler)
+		},
+		func() (string, http.Handler) {
+			return queryconnect.NewQueryServiceHandler(queryHandler)
+		},
+	)
+}
+
+type scriptedSubmitHandler struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+	reject   bool
+	fail     connect.Code
+	waitFail connect.Code
+	stages   []submit.Stage
+	hold     bool
+}
+
+func (s *scriptedSubmitHandler) SubmitTx(
+	context.Context,
+	*connect.Request[submit.SubmitTxRequest],
+) (*connect.Response[submit.SubmitTxResponse], error) {
+	if s.reject {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("bad tx"))
+	}
+	if s.fail != 0 {
+		return nil, connect.NewError(s.fail, errors.New("submit failed"))
+	}
+	return connect.NewResponse(&submit.SubmitTxResponse{Ref: []byte("ref")}), nil
+}
+
+func (s *scriptedSubmitHandler) WaitForTx(
+	ctx context.Context,
+	req *connect.Request[submit.WaitForTxRequest],
+	stream *connect.ServerStream[submit.WaitForTxResponse],
+) error {
+	for _, stage := range s.stages {
+		if err := stream.Send(&submit.WaitForTxResponse{
+			Ref:   req.Msg.GetRef()[0],
+			Stage: stage,
+		}); err != nil {
+			return err
+		}
+	}
+	if s.hold {
+		<-ctx.Done()
+	}
+	if s.waitFail != 0 {
+		return connect.NewError(s.waitFail, errors.New("wait failed"))
+	}
+	return nil
+}
+
+// depthQueryHandler answers ReadTx with a transaction at height block and
+// a ledger tip taken from tips, one per call (the last one repeats). The
+// first missing calls answer NotFound instead.
+type depthQueryHandler struct {
+	queryconnect.UnimplementedQueryServiceHandler
+	block   uint64
+	tips    []uint64
+	missing int
+	calls   int
+}
+
+func (q *depthQueryHandler) ReadTx(
+	context.Context,
+	*connect.Request[query.ReadTxRequest],
+) (*connect.Response[query.ReadTxResponse], error) {
+	q.calls++
+	if q.calls <= q.missing {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("no such tx"))
+	}
+	tip := q.tips[min(q.calls-q.missing-1, len(q.tips)-1)]
+	return connect.NewResponse(&query.ReadTxResponse{
+		Tx:        &query.AnyChainTx{BlockRef: &query.ChainPoint{Height: q.block}},
+		LedgerTip: &query.ChainPoint{Height: tip},
+	}), nil
+}
@@ -0,0 +1,622 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	gosync "sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+)
+
+const (
+	defaultMaxResubmissions = 3
+	defaultTrackerInterval  = 20 * time.Second
+	// defaultTrackerFinality is the Cardano mainnet security parameter k.
+	defaultTrackerFinality uint64 = 2160
+)
+
+// TxState is the lifecycle state of a transaction followed by a [Tracker].
+type TxState int
+
+const (
+	// TxPending: submitted, not yet seen in the mempool or on chain.
+	TxPending TxState = iota
+	// TxInMempool: reported in the mempool (or propagating).
+	TxInMempool
+	// TxConfirmed: included in a block that may still roll back.
+	TxConfirmed
+	// TxFinal: buried deeper than the finality depth; no longer followed.
+	TxFinal
+	// TxExpired: dropped after its TTL passed; cannot be resubmitted.
+	TxExpired
+	// TxFailed: dropped and not resubmitted, because the resubmission
+	// limit was reached or the server rejected the resubmission
+	// (InvalidArgument or FailedPrecondition).
+	TxFailed
+)
+
+func (s TxState) String() string {
+	switch s {
+	case TxPending:
+		return "pending"
+	case TxInMempool:
+		return "in-mempool"
+	case TxConfirmed:
+		return "confirmed"
+	case TxFinal:
+		return "final"
+	case TxExpired:
+		return "expired"
+	case TxFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("TxState(%d)", int(s))
+	}
+}
+
+// terminal reports whether the tracker stops following a tx in state s.
+func (s TxState) terminal() bool {
+	return s == TxFinal || s == TxExpired || s == TxFailed
+}
+
+// TrackedTx is a snapshot of a transaction followed by a [Tracker].
+type TrackedTx struct {
+	Hash  []byte
+	State TxState
+	// Stage is the last stage reported by WaitForTx or WatchMempool.
+	Stage submit.Stage
+	// TTL is the slot after which the transaction is invalid; 0 if none.
+	TTL           uint64
+	Resubmissions int
+	// Block is the block containing the transaction once confirmed.
+	Block *query.ChainPoint
+	// Err explains a TxFailed or TxExpired state.
+	Err       error
+	UpdatedAt time.Time
+}
+
+// TxStateHandler is called by a [Tracker] after a transaction changes
+// state. It runs on a tracker goroutine and must not block for long; calls
+// are serialized and made in the order the changes happened. It may call
+// the Tracker's other methods.
+type TxStateHandler func(tx TrackedTx, previous TxState)
+
+// TrackerOption configures a [Tracker] during [Client.NewTracker].
+type TrackerOption func(*Tracker)
+
+// WithMaxResubmissions caps how often a dropped or rolled back transaction
+// is resubmitted. The default is 3.
+func WithMaxResubmissions(n int) TrackerOption {
+	return func(t *Tracker) {
+		t.maxResubmissions = n
+	}
+}
+
+// WithTrackerInterval sets how often the tracker reconciles each
+// transaction against Query.ReadTx and Submit.ReadMempool. A transaction
+// must also be missing from the mempool for at least this long after a
+// submission before it counts as dropped. The default is 20 seconds.
+func WithTrackerInterval(interval time.Duration) TrackerOption {
+	return func(t *Tracker) {
+		if interval > 0 {
+			t.interval = interval
+		}
+	}
+}
+
+// WithTrackerFinality sets how many blocks must be on top of and including
+// a transaction's block before it is reported as TxFinal and no longer
+// followed. The default is 2160.
+func WithTrackerFinality(depth uint64) TrackerOption {
+	return func(t *Tracker) {
+		t.finality = depth
+	}
+}
+
+// WithStateChangeHandler registers fn to be called on every state change.
+func WithStateChangeHandler(fn TxStateHandler) TrackerOption {
+	return func(t *Tracker) {
+		t.onChange = fn
+	}
+}
+
+// Tracker follows submitted transactions until they are final, resubmitting
+// those that are dropped from the mempool or rolled back after confirming.
+//
+// Stage updates arrive from Submit.WaitForTx and Submit.WatchMempool while
+// [Tracker.Run] is active; servers that do not implement one of them are
+// tolerated. Every interval (see [WithTrackerInterval]) each transaction is
+// reconciled: Query.ReadTx decides whether it is on chain and how deep, and
+// Submit.ReadMempool whether it is still pending. A transaction that
+// vanished is resubmitted up to the limit (see [WithMaxResubmissions]) and
+// only while the current slot is below its TTL. Before a transaction is
+// failed or expired it is read again, so one that reached the chain while
+// the ReadTx index lagged is reported as confirmed instead. Submission
+// errors other than a rejection leave it pending for the next interval.
+//
+// Transactions in a terminal state (TxFinal, TxExpired or TxFailed) are no
+// longer followed, but their last snapshot stays available from
+// [Tracker.State] and [Tracker.States] until [Tracker.Forget] removes it.
+// Long-running callers must Forget them, for example from the
+// state-change handler, or the tracker grows without bound.
+//
+// A Tracker is safe for concurrent use.
+type Tracker struct {
+	client           *Client
+	maxResubmissions int
+	interval         time.Duration
+	finality         uint64
+	onChange         TxStateHandler
+
+	// changeMu serializes state changes with their onChange calls.
+	changeMu gosync.Mutex
+
+	mu   gosync.Mutex
+	txs  map[string]*trackedTx
+	wake chan struct{}
+}
+
+type trackedTx struct {
+	TrackedTx
+	cbor        []byte
+	submittedAt time.Time
+	busy        bool
+}
+
+// NewTracker returns a [Tracker] with no transactions.
+func (c *Client) NewTracker(options ...TrackerOption) *Tracker {
+	t := &Tracker{
+		client:           c,
+		maxResubmissions: defaultMaxResubmissions,
+		interval:         defaultTrackerInterval,
+		finality:         defaultTrackerFinality,
+		txs:              make(map[string]*trackedTx),
+		wake:             make(chan struct{}, 1),
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// Track registers an already submitted transaction. txHash and txCbor are
+// hex; the CBOR is kept for resubmission and its TTL is read from the
+// body. Returns an error if the CBOR cannot be decoded or does not hash to
+// txHash.
+func (t *Tracker) Track(txHash string, txCbor string) error {
+	hash, err := hex.DecodeString(txHash)
+	if err != nil {
+		return fmt.Errorf("failed to decode transaction hash: %w", err)
+	}
+	raw, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	tx, err := decodeLedgerTransaction(raw)
+	if err != nil {
+		return err
+	}
+	if txID := tx.Hash(); !bytes.Equal(txID.Bytes(), hash) {
+		return fmt.Errorf(
+			"transaction hash mismatch: CBOR hashes to %s, not %s",
+			txID,
+			txHash,
+		)
+	}
+
+	now := time.Now()
+	t.mu.Lock()
+	key := hex.EncodeToString(hash)
+	if _, ok := t.txs[key]; !ok {
+		t.txs[key] = &trackedTx{
+			TrackedTx: TrackedTx{
+				Hash:      hash,
+				State:     TxPending,
+				TTL:       tx.TTL(),
+				UpdatedAt: now,
+			},
+			cbor:        raw,
+			submittedAt: now,
+		}
+	}
+	t.mu.Unlock()
+	t.notify()
+	return nil
+}
+
+// Forget stops tracking the transaction with the given hex hash and drops
+// its snapshot. It may be called from the state-change handler.
+func (t *Tracker) Forget(txHash string) {
+	t.mu.Lock()
+	delete(t.txs, txHash)
+	t.mu.Unlock()
+	t.notify()
+}
+
+// State returns a snapshot of the transaction with the given hex hash.
+func (t *Tracker) State(txHash string) (TrackedTx, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tx, ok := t.txs[txHash]
+	if !ok {
+		return TrackedTx{}, false
+	}
+	return tx.TrackedTx, true
+}
+
+// States returns snapshots of every tracked transaction.
+func (t *Tracker) States() []TrackedTx {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	states := make([]TrackedTx, 0, len(t.txs))
+	for _, tx := range t.txs {
+		states = append(states, tx.TrackedTx)
+	}
+	return states
+}
+
+// Run follows the tracked transactions until ctx is done or an RPC fails
+// with an error other than Unimplemented.
+func (t *Tracker) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, 2)
+	go func() { errs <- t.watchMempool(ctx) }()
+	go func() { errs <- t.waitForTxs(ctx) }()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-errs:
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := t.reconcile(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (t *Tracker) notify() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// watchMempool feeds Submit.WatchMempool stages into the tracker.
+func (t *Tracker) watchMempool(ctx context.Context) error {
+	stream, err := t.client.WatchMempoolTransactionsWithContext(ctx)
+	if err == nil {
+		defer stream.Close()
+		for stream.Receive() {
+			tx := stream.Msg().GetTx()
+			t.observeStage(tx.GetRef(), tx.GetStage())
+		}
+		err = stream.Err()
+	}
+	if err == nil || connect.CodeOf(err) == connect.CodeUnimplemented {
+		return nil
+	}
+	return fmt.Errorf("failed to watch mempool: %w", err)
+}
+
+// waitForTxs keeps one Submit.WaitForTx stream open for the transactions
+// still being followed, reopening it when the set changes.
+func (t *Tracker) waitForTxs(ctx context.Context) error {
+	for {
+		refs := t.activeRefs()
+		if len(refs) == 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-t.wake:
+				continue
+			}
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		restarted := make(chan struct{})
+		go func() {
+			select {
+			case <-t.wake:
+				close(restarted)
+				cancel()
+			case <-streamCtx.Done():
+			}
+		}()
+		err := t.followStages(streamCtx, refs)
+		cancel()
+		select {
+		case <-restarted:
+			continue
+		default:
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connect.CodeOf(err) == connect.CodeUnimplemented {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to wait for transactions: %w", err)
+		}
+		// The server ended the stream; reopen it once the set changes.
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.wake:
+		}
+	}
+}
+
+func (t *Tracker) followStages(ctx context.Context, refs [][]byte) error {
+	stream, err := t.client.WaitForTransactionWithContext(ctx, &submit.WaitForTxRequest{
+		Ref: refs,
+	})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	for stream.Receive() {
+		t.observeStage(stream.Msg().GetRef(), stream.Msg().GetStage())
+	}
+	return stream.Err()
+}
+
+func (t *Tracker) activeRefs() [][]byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var refs [][]byte
+	for _, tx := range t.txs {
+		if !tx.State.terminal() {
+			refs = append(refs, tx.Hash)
+		}
+	}
+	slices.SortFunc(refs, bytes.Compare)
+	return refs
+}
+
+// observeStage applies a streamed stage report. Disappearances are left
+// to reconcile, which double-checks them against the chain.
+func (t *Tracker) observeStage(ref []byte, stage submit.Stage) {
+	t.changeMu.Lock()
+	defer t.changeMu.Unlock()
+	t.mu.Lock()
+	tx, ok := t.txs[hex.EncodeToString(ref)]
+	if !ok || tx.State.terminal() || stage == submit.Stage_STAGE_UNSPECIFIED {
+		t.mu.Unlock()
+		return
+	}
+	tx.Stage = stage
+	previous := tx.State
+	switch {
+	case stage == submit.Stage_STAGE_CONFIRMED:
+		tx.State = TxConfirmed
+	case previous == TxPending:
+		tx.State = TxInMempool
+	}
+	snapshot := tx.TrackedTx
+	t.mu.Unlock()
+	t.changed(snapshot, previous)
+}
+
+// changed reports a state change; the caller holds changeMu.
+func (t *Tracker) changed(tx TrackedTx, previous TxState) {
+	if tx.State != previous && t.onChange != nil {
+		t.onChange(tx, previous)
+	}
+}
+
+// reconcile checks every followed transaction against the chain and the
+// mempool, resubmitting the ones that vanished.
+func (t *Tracker) reconcile(ctx context.Context) error {
+	mempool, err := t.mempoolRefs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, key := range t.activeKeys() {
+		if err := t.reconcileTx(ctx, key, mempool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Tracker) activeKeys() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var keys []string
+	for key, tx := range t.txs {
+		if !tx.State.terminal() && !tx.busy {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// mempoolRefs returns the hex refs in the mempool, or nil if the server
+// does not implement ReadMempool.
+func (t *Tracker) mempoolRefs(ctx context.Context) (map[string]bool, error) {
+	resp, err := t.client.GetMempoolTransactionsWithContext(ctx)
+	if connect.CodeOf(err) == connect.CodeUnimplemented {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mempool: %w", err)
+	}
+	refs := make(map[string]bool, len(resp.Msg.GetItems()))
+	for _, item := range resp.Msg.GetItems() {
+		refs[hex.EncodeToString(item.GetRef())] = true
+	}
+	return refs, nil
+}
+
+func (t *Tracker) reconcileTx(
+	ctx context.Context,
+	key string,
+	mempool map[string]bool,
+) error {
+	t.mu.Lock()
+	tx, ok := t.txs[key]
+	if !ok {
+		t.mu.Unlock()
+		return nil
+	}
+	hash, state, submittedAt := tx.Hash, tx.State, tx.submittedAt
+	t.mu.Unlock()
+
+	found, err := t.confirmOnChain(ctx, key, hash)
+	if err != nil || found {
+		return err
+	}
+
+	var reason string
+	switch {
+	case state == TxConfirmed:
+		reason = "rolled back"
+	case mempool != nil && !mempool[key] && time.Since(submittedAt) >= t.interval:
+		reason = "dropped from mempool"
+	default:
+		return nil
+	}
+	return t.resubmit(ctx, key, reason)
+}
+
+// confirmOnChain reads the transaction with Query.ReadTx and, if it is in
+// a block, records it as confirmed or final. It reports whether it was
+// found.
+func (t *Tracker) confirmOnChain(
+	ctx context.Context,
+	key string,
+	hash []byte,
+) (bool, error) {
+	resp, err := t.client.UtxorpcClient.ReadTxWithContext(
+		ctx,
+		connect.NewRequest(&query.ReadTxRequest{Hash: hash}),
+	)
+	if connect.CodeOf(err) == connect.CodeNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read transaction %s: %w", key, err)
+	}
+	block, tip := resp.Msg.GetTx().GetBlockRef(), resp.Msg.GetLedgerTip()
+	if block == nil {
+		return false, nil
+	}
+	next := TxConfirmed
+	if tip.GetHeight() >= block.GetHeight() &&
+		tip.GetHeight()-block.GetHeight()+1 >= t.finality {
+		next = TxFinal
+	}
+	t.update(key, func(tx *trackedTx) {
+		tx.State = next
+		tx.Stage = submit.Stage_STAGE_CONFIRMED
+		tx.Block = block
+	})
+	return true, nil
+}
+
+func (t *Tracker) resubmit(ctx context.Context, key, reason string) error {
+	t.mu.Lock()
+	tx, ok := t.txs[key]
+	if !ok || tx.busy {
+		t.mu.Unlock()
+		return nil
+	}
+	tx.busy = true
+	hash, ttl, cbor := tx.Hash, tx.TTL, tx.cbor
+	exhausted := tx.Resubmissions >= t.maxResubmissions
+	t.mu.Unlock()
+	defer t.update(key, func(tx *trackedTx) { tx.busy = false })
+
+	// fail gives the terminal verdict set by fn unless the transaction
+	// turns out to be on chain after all.
+	fail := func(fn func(*trackedTx)) error {
+		found, err := t.confirmOnChain(ctx, key, hash)
+		if err != nil || found {
+			return err
+		}
+		t.update(key, fn)
+		return nil
+	}
+
+	if exhausted {
+		return fail(func(tx *trackedTx) {
+			tx.State = TxFailed
+			tx.Err = fmt.Errorf("%w: %s after %d resubmissions", ErrTxDropped, reason, tx.Resubmissions)
+		})
+	}
+	if ttl != 0 {
+		tip, err := t.client.GetTipWithContext(ctx)
+		if err != nil {
+			return err
+		}
+		if slot := tip.Msg.GetTip().GetSlot(); slot >= ttl {
+			return fail(func(tx *trackedTx) {
+				tx.State = TxExpired
+				tx.Err = fmt.Errorf("%w: %s at slot %d, past TTL %d", ErrTxDropped, reason, slot, ttl)
+			})
+		}
+	}
+
+	_, err := t.client.SubmitTransactionWithContext(ctx, &submit.SubmitTxRequest{
+		Tx: &submit.AnyChainTx{Type: &submit.AnyChainTx_Raw{Raw: cbor}},
+	})
+	switch code := connect.CodeOf(err); {
+	case err == nil:
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case code == connect.CodeInvalidArgument || code == connect.CodeFailedPrecondition:
+		// Spent inputs are also refused once the transaction itself is on
+		// chain, so check the chain before calling it a rejection.
+		return fail(func(tx *trackedTx) {
+			tx.State = TxFailed
+			tx.Err = errors.Join(ErrTxRejected, err)
+		})
+	default:
+		// Transient failures leave it pending for the next interval.
+		return nil
+	}
+	t.update(key, func(tx *trackedTx) {
+		tx.Resubmissions++
+		tx.State = TxPending
+		tx.Stage = submit.Stage_STAGE_ACKNOWLEDGED
+		tx.Block = nil
+		tx.submittedAt = time.Now()
+	})
+	t.notify()
+	return nil
+}
+
+// update applies fn to a tracked tx and reports any state change.
+func (t *Tracker) update(key string, fn func(*trackedTx)) {
+	t.changeMu.Lock()
+	defer t.changeMu.Unlock()
+	t.mu.Lock()
+	tx, ok := t.txs[key]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	previous := tx.State
+	fn(tx)
+	tx.UpdatedAt = time.Now()
+	snapshot := tx.TrackedTx
+	t.mu.Unlock()
+	t.changed(snapshot, previous)
+}
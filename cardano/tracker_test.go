@@ -0,0 +1,279 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync/syncconnect"
+)
+
+// trackerTxCbor is a minimal transaction with a TTL of slot 100.
+var trackerTxCbor = "84a400818258" + "20" + strings.Repeat("00", 32) + "00" +
+	"0180" + "0200" + "031864" + "a0f5f6"
+
+const trackerTxHash = "b5c69e988662f2ee4de7ac2e16f77fbade9d1477b1abd450be7cbb01d4e03677"
+
+func TestTrackerResubmitsDroppedTxUpToLimit(t *testing.T) {
+	fakeSubmit := &trackerSubmitHandler{}
+	client := newTrackerTestClient(t, fakeSubmit, &notFoundQueryHandler{}, 50)
+
+	var transitions []string
+	tracker := client.NewTracker(
+		WithMaxResubmissions(2),
+		WithTrackerInterval(1),
+		WithStateChangeHandler(func(tx TrackedTx, previous TxState) {
+			transitions = append(transitions, previous.String()+">"+tx.State.String())
+		}),
+	)
+	if err := tracker.Track(trackerTxHash, trackerTxCbor); err != nil {
+		t.Fatalf("Track returned error: %v", err)
+	}
+	for range 3 {
+		if err := tracker.reconcile(context.Background()); err != nil {
+			t.Fatalf("reconcile returned error: %v", err)
+		}
+	}
+
+	tx, ok := tracker.State(trackerTxHash)
+	if !ok {
+		t.Fatal("State reports the transaction as untracked")
+	}
+	if tx.State != TxFailed || !errors.Is(tx.Err, ErrTxDropped) {
+		t.Fatalf("state = %v (%v), want failed with ErrTxDropped", tx.State, tx.Err)
+	}
+	if tx.TTL != 100 || tx.Resubmissions != 2 || fakeSubmit.submits != 2 {
+		t.Fatalf(
+			"TTL %d, %d resubmissions, %d submits; want 100, 2, 2",
+			tx.TTL,
+			tx.Resubmissions,
+			fakeSubmit.submits,
+		)
+	}
+	if want := []string{"pending>failed"}; !slicesEqual(transitions, want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+}
+
+func TestTrackerExpiresTxPastTTL(t *testing.T) {
+	fakeSubmit := &trackerSubmitHandler{}
+	client := newTrackerTestClient(t, fakeSubmit, &notFoundQueryHandler{}, 200)
+
+	tracker := client.NewTracker(WithTrackerInterval(1))
+	if err := tracker.Track(trackerTxHash, trackerTxCbor); err != nil {
+		t.Fatalf("Track returned error: %v", err)
+	}
+	if err := tracker.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile returned error: %v", err)
+	}
+
+	tx, _ := tracker.State(trackerTxHash)
+	if tx.State != TxExpired || fakeSubmit.submits != 0 {
+		t.Fatalf("state = %v after %d submits, want expired after 0", tx.State, fakeSubmit.submits)
+	}
+}
+
+func TestTrackerReportsConfirmedAndFinal(t *testing.T) {
+	fakeQuery := &depthQueryHandler{block: 10, tips: []uint64{10, 12}}
+	client := newTrackerTestClient(t, &trackerSubmitHandler{}, fakeQuery, 50)
+
+	tracker := client.NewTracker(WithTrackerFinality(3))
+	if err := tracker.Track(trackerTxHash, trackerTxCbor); err != nil {
+		t.Fatalf("Track returned error: %v", err)
+	}
+	for _, want := range []TxState{TxConfirmed, TxFinal} {
+		if err := tracker.reconcile(context.Background()); err != nil {
+			t.Fatalf("reconcile returned error: %v", err)
+		}
+		if tx, _ := tracker.State(trackerTxHash); tx.State != want {
+			t.Fatalf("state = %v, want %v", tx.State, want)
+		}
+	}
+}
+
+func TestTrackerKeepsTerminalTxsUntilForgotten(t *testing.T) {
+	fakeQuery := &depthQueryHandler{block: 10, tips: []uint64{12}}
+	client := newTrackerTestClient(t, &trackerSubmitHandler{}, fakeQuery, 50)
+
+	tracker := client.NewTracker(WithTrackerFinality(3))
+	if err := tracker.Track(trackerTxHash, trackerTxCbor); err != nil {
+		t.Fatalf("Track returned error: %v", err)
+	}
+	if err := tracker.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile returned error: %v", err)
+	}
+	if tx, ok := tracker.State(trackerTxHash); !ok || tx.State != TxFinal {
+		t.Fatalf("state = %v, %v, want a kept final snapshot", tx.State, ok)
+	}
+
+	// Forgetting terminal transactions from the handler bounds the tracker.
+	var forgetting *Tracker
+	forgetting = client.NewTracker(
+		WithTrackerFinality(3),
+		WithStateChangeHandler(func(tx TrackedTx, _ TxState) {
+			if tx.State.terminal() {
+				forgetting.Forget(hex.EncodeToString(tx.Hash))
+			}
+		}),
+	)
+	if err := forgetting.Track(trackerTxHash, trackerTxCbor); err != nil {
+		t.Fatalf("Track returned error: %v", err)
+	}
+	if err := forgetting.reconcile(context.Background()); err != nil {
+		t.Fatalf("reconcile returned error: %v", err)
+	}
+	if states := forgetting.States(); len(states) != 0 {
+		t.Fatalf("States = %v, want none after Forget", states)
+	}
+}
+
+func TestTrackerRereadsBeforeTerminalVerdict(t *testing.T) {
+	tests := []struct {
+		name    string
+		fail    connect.Code
+		tipSlot uint64
+	}{
+		// The node refuses a transaction whose inputs it already spent.
+		{name: "refused resubmission", fail: connect.CodeFailedPrecondition, tipSlot: 50},
+		{name: "past TTL", tipSlot: 200},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeSubmit := &trackerSubmitHandler{fail: test.fail}
+			// ReadTx lags the chain: the first read misses the transaction.
+			fakeQuery := &depthQueryHandler{block: 10, tips: []uint64{10}, missing: 1}
+			client := newTrackerTestClient(t, fakeSubmit, fakeQuery, test.tipSlot)
+
+			tracker := client.NewTracker(WithTrackerInterval(1), WithTrackerFinality(3))
+			if err := tracker.Track(trackerTxHash, trackerTxCbor); err != nil {
+				t.Fatalf("Track returned error: %v", err)
+			}
+			if err := tracker.reconcile(context.Background()); err != nil {
+				t.Fatalf("reconcile returned error: %v", err)
+			}
+			tx, _ := tracker.State(trackerTxHash)
+			if tx.State != TxConfirmed || tx.Err != nil {
+				t.Fatalf("state = %v (%v), want confirmed", tx.State, tx.Err)
+			}
+		})
+	}
+}
+
+func TestTrackerKeepsTxPendingOnTransientSubmitError(t *testing.T) {
+	fakeSubmit := &trackerSubmitHandler{fail: connect.CodeUnavailable}
+	client := newTrackerTestClient(t, fakeSubmit, &notFoundQueryHandler{}, 50)
+
+	tracker := client.NewTracker(WithTrackerInterval(1))
+	if err := tracker.Track(trackerTxHash, trackerTxCbor); err != nil {
+		t.Fatalf("Track returned error: %v", err)
+	}
+	for range 2 {
+		if err := tracker.reconcile(context.Background()); err != nil {
+			t.Fatalf("reconcile returned error: %v", err)
+		}
+	}
+	tx, _ := tracker.State(trackerTxHash)
+	if tx.State != TxPending || tx.Err != nil || tx.Resubmissions != 0 {
+		t.Fatalf(
+			"state = %v (%v) after %d resubmissions, want pending after 0",
+			tx.State,
+			tx.Err,
+			tx.Resubmissions,
+		)
+	}
+	if fakeSubmit.submits != 2 {
+		t.Fatalf("submits = %d, want a retry per reconcile", fakeSubmit.submits)
+	}
+}
+
+func TestTrackerRejectsHashMismatch(t *testing.T) {
+	tracker := NewClient().NewTracker()
+	err := tracker.Track(strings.Repeat("00", 32), trackerTxCbor)
+	if err == nil {
+		t.Fatal("Track accepted a hash that does not match the CBOR")
+	}
+	if len(tracker.States()) != 0 {
+		t.Fatal("Track registered a mismatched transaction")
+	}
+}
+
+func newTrackerTestClient(
+	t *testing.T,
+	submitHandler submitconnect.SubmitServiceHandler,
+	queryHandler queryconnect.QueryServiceHandler,
+	tipSlot uint64,
+) *Client {
+	t.Helper()
+	return newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return submitconnect.NewSubmitServiceHandler(submitHandler)
+		},
+		func() (string, http.Handler) {
+			return queryconnect.NewQueryServiceHandler(queryHandler)
+		},
+		func() (string, http.Handler) {
+			return syncconnect.NewSyncServiceHandler(&tipSyncHandler{slot: tipSlot})
+		},
+	)
+}
+
+// trackerSubmitHandler accepts every submission, or fails it with fail
+// when set, and reports an empty mempool.
+type trackerSubmitHandler struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+	fail    connect.Code
+	submits int
+}
+
+func (s *trackerSubmitHandler) SubmitTx(
+	context.Context,
+	*connect.Request[submit.SubmitTxRequest],
+) (*connect.Response[submit.SubmitTxResponse], error) {
+	s.submits++
+	if s.fail != 0 {
+		return nil, connect.NewError(s.fail, errors.New("submit failed"))
+	}
+	return connect.NewResponse(&submit.SubmitTxResponse{}), nil
+}
+
+func (s *trackerSubmitHandler) ReadMempool(
+	context.Context,
+	*connect.Request[submit.ReadMempoolRequest],
+) (*connect.Response[submit.ReadMempoolResponse], error) {
+	return connect.NewResponse(&submit.ReadMempoolResponse{}), nil
+}
+
+type notFoundQueryHandler struct {
+	queryconnect.UnimplementedQueryServiceHandler
+}
+
+func (notFoundQueryHandler) ReadTx(
+	context.Context,
+	*connect.Request[query.ReadTxRequest],
+) (*connect.Response[query.ReadTxResponse], error) {
+	return nil, connect.NewError(connect.CodeNotFound, errors.New("no such tx"))
+}
+
+type tipSyncHandler struct {
+	syncconnect.UnimplementedSyncServiceHandler
+	slot uint64
+}
+
+func (s *tipSyncHandler) ReadTip(
+	context.Context,
+	*connect.Request[sync.ReadTipRequest],
+) (*connect.Response[sync.ReadTipResponse], error) {
+	return connect.NewResponse(&sync.ReadTipResponse{
+		Tip: &sync.BlockRef{Slot: s.slot},
+	}), nil
+}
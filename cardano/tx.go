@@ -0,0 +1,161 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+)
+
+// DecodedTx is a signed transaction decoded locally by [DecodeTransaction].
+type DecodedTx struct {
+	// Hash is the transaction id: the Blake2b-256 hash of the body, hex
+	// encoded. It is the reference a server reports for the transaction.
+	Hash            string
+	Inputs          []*chaincardano.TxInput
+	ReferenceInputs []*chaincardano.TxInput
+	Collateral      []*chaincardano.TxInput
+	// Outputs carry the address, coin and assets of each output.
+	Outputs []*chaincardano.TxOutput
+	Fee     uint64
+	// ValidFrom and TTL bound the validity interval in slots; zero when
+	// unbounded.
+	ValidFrom uint64
+	TTL       uint64
+	// Ledger is the full gouroboros decoding, for fields not copied here.
+	Ledger ledger.Transaction
+}
+
+// DecodeTransaction decodes signed transaction CBOR of any era without
+// contacting a server. txCbor is hex, as accepted by
+// [Client.SubmitTransaction].
+func DecodeTransaction(txCbor string) (*DecodedTx, error) {
+	raw, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	tx, err := decodeLedgerTransaction(raw)
+	if err != nil {
+		return nil, err
+	}
+	converted := txToUtxorpc(tx)
+	return &DecodedTx{
+		Hash:            hex.EncodeToString(converted.GetHash()),
+		Inputs:          converted.GetInputs(),
+		ReferenceInputs: converted.GetReferenceInputs(),
+		Collateral:      txInputsToUtxorpc(tx.Collateral()),
+		Outputs:         converted.GetOutputs(),
+		Fee:             tx.Fee().Uint64(),
+		ValidFrom:       tx.ValidityIntervalStart(),
+		TTL:             tx.TTL(),
+		Ledger:          tx,
+	}, nil
+}
+
+// TransactionHash returns the hex transaction id of signed transaction CBOR
+// given as hex, computed locally from its body.
+func TransactionHash(txCbor string) (string, error) {
+	tx, err := DecodeTransaction(txCbor)
+	if err != nil {
+		return "", err
+	}
+	return tx.Hash, nil
+}
+
+// decodeLedgerTransaction parses signed transaction CBOR of any era.
+func decodeLedgerTransaction(txCbor []byte) (ledger.Transaction, error) {
+	txType, err := ledger.DetermineTransactionType(txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine transaction era: %w", err)
+	}
+	tx, err := ledger.NewTransactionFromCbor(txType, txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// txToUtxorpc converts a decoded transaction to the v1beta Cardano message
+// with the fields needed for local matching: hash, fee, inputs, reference
+// inputs, outputs (address, coin and assets), mint and validity. gouroboros' own
+// Utxorpc method targets v1alpha.
+func txToUtxorpc(tx ledger.Transaction) *chaincardano.Tx {
+	out := &chaincardano.Tx{
+		Hash:            tx.Hash().Bytes(),
+		Fee:             newBigInt(tx.Fee()),
+		Inputs:          txInputsToUtxorpc(tx.Inputs()),
+		ReferenceInputs: txInputsToUtxorpc(tx.ReferenceInputs()),
+		Mint:            multiAssetToUtxorpc(tx.AssetMint()),
+		Validity: &chaincardano.TxValidity{
+			Start: tx.ValidityIntervalStart(),
+			Ttl:   tx.TTL(),
+		},
+	}
+	for _, output := range tx.Outputs() {
+		address, _ := output.Address().Bytes()
+		out.Outputs = append(out.Outputs, &chaincardano.TxOutput{
+			Address: address,
+			Coin:    newBigInt(output.Amount()),
+			Assets:  multiAssetToUtxorpc(output.Assets()),
+		})
+	}
+	return out
+}
+
+func txInputsToUtxorpc(inputs []common.TransactionInput) []*chaincardano.TxInput {
+	var out []*chaincardano.TxInput
+	for _, input := range inputs {
+		out = append(out, &chaincardano.TxInput{
+			TxHash:      input.Id().Bytes(),
+			OutputIndex: input.Index(),
+		})
+	}
+	return out
+}
+
+func multiAssetToUtxorpc(assets *common.MultiAsset[*big.Int]) []*chaincardano.Multiasset {
+	if assets == nil {
+		return nil
+	}
+	policies := assets.Policies()
+	slices.SortFunc(policies, func(a, b common.Blake2b224) int {
+		return bytes.Compare(a.Bytes(), b.Bytes())
+	})
+	var out []*chaincardano.Multiasset
+	for _, policy := range policies {
+		names := assets.Assets(policy)
+		slices.SortFunc(names, bytes.Compare)
+		multiasset := &chaincardano.Multiasset{PolicyId: policy.Bytes()}
+		for _, name := range names {
+			multiasset.Assets = append(multiasset.Assets, &chaincardano.Asset{
+				Name:     name,
+				Quantity: newBigInt(assets.Asset(policy, name)),
+			})
+		}
+		out = append(out, multiasset)
+	}
+	return out
+}
+
+// newBigInt encodes n as a v1beta BigInt, using the int64 form when it fits.
+func newBigInt(n *big.Int) *chaincardano.BigInt {
+	if n == nil {
+		n = new(big.Int)
+	}
+	switch {
+	case n.IsInt64():
+		return &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: n.Int64()}}
+	case n.Sign() > 0:
+		return &chaincardano.BigInt{BigInt: &chaincardano.BigInt_BigUInt{BigUInt: n.Bytes()}}
+	default:
+		// Stored as -1 - n.
+		m := new(big.Int).Neg(n)
+		m.Sub(m, big.NewInt(1))
+		return &chaincardano.BigInt{BigInt: &chaincardano.BigInt_BigNInt{BigNInt: m.Bytes()}}
+	}
+}
@@ -0,0 +1,54 @@
+package cardano
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	tx, err := DecodeTransaction(validationTx("1a001e8480", "a0"))
+	if err != nil {
+		t.Fatalf("DecodeTransaction returned error: %v", err)
+	}
+	if len(tx.Inputs) != 1 || tx.Inputs[0].GetOutputIndex() != 0 ||
+		string(tx.Inputs[0].GetTxHash()) != strings.Repeat("\x00", 32) {
+		t.Fatalf("inputs = %v", tx.Inputs)
+	}
+	if len(tx.Outputs) != 1 || bigIntUint64(tx.Outputs[0].GetCoin()) != 2000000 {
+		t.Fatalf("outputs = %v", tx.Outputs)
+	}
+	if tx.Fee != 1000000 || tx.TTL != 100 || tx.ValidFrom != 0 {
+		t.Fatalf("fee %d, validity [%d, %d)", tx.Fee, tx.ValidFrom, tx.TTL)
+	}
+
+	hash, err := TransactionHash(trackerTxCbor)
+	if err != nil || hash != trackerTxHash {
+		t.Fatalf("TransactionHash = %q, %v; want %q", hash, err, trackerTxHash)
+	}
+	if _, err := DecodeTransaction("84a0"); err == nil {
+		t.Fatal("DecodeTransaction accepted an incomplete transaction")
+	}
+}
+
+func TestSubmitAndWaitCrossChecksReference(t *testing.T) {
+	client := newSubmitTestClient(t, &trackerSubmitHandler{}, &depthQueryHandler{})
+	result, err := client.SubmitAndWait(
+		trackerTxCbor,
+		WithWaitStage(submit.Stage_STAGE_ACKNOWLEDGED),
+	)
+	if err != nil {
+		t.Fatalf("SubmitAndWait returned error: %v", err)
+	}
+	if got := string(result.Ref); got != string(mustDecodeHex(t, trackerTxHash)) {
+		t.Fatalf("Ref = %x, want the local transaction id", result.Ref)
+	}
+
+	client = newSubmitTestClient(t, &scriptedSubmitHandler{}, &depthQueryHandler{})
+	_, err = client.SubmitAndWait(trackerTxCbor)
+	if !errors.Is(err, ErrTxRefMismatch) {
+		t.Fatalf("error = %v, want ErrTxRefMismatch", err)
+	}
+}
@@ -0,0 +1,1060 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"connectrpc.com/connect"
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// maxBalanceRounds bounds the fee and selection fixed-point iteration of
+// [TxBuilder.Build]. It normally settles in two or three rounds.
+const maxBalanceRounds = 32
+
+// ScriptLanguage is the language of a [Script]. Its value is the tag
+// prefixed to the script when hashing it.
+type ScriptLanguage uint8
+
+const (
+	NativeScript ScriptLanguage = iota
+	PlutusV1
+	PlutusV2
+	PlutusV3
+)
+
+func (l ScriptLanguage) String() string {
+	switch l {
+	case NativeScript:
+		return "native"
+	case PlutusV1:
+		return "PlutusV1"
+	case PlutusV2:
+		return "PlutusV2"
+	case PlutusV3:
+		return "PlutusV3"
+	default:
+		return fmt.Sprintf("ScriptLanguage(%d)", uint8(l))
+	}
+}
+
+// Script is a native or Plutus script.
+type Script struct {
+	Language ScriptLanguage
+	// Bytes is the native script CBOR, or for Plutus the CBOR-wrapped flat
+	// program (the compiledCode of a CIP-57 blueprint).
+	Bytes []byte
+}
+
+// Hash returns the script hash: its policy ID, or the credential of
+// addresses it locks.
+func (s Script) Hash() []byte {
+	return common.Blake2b224Hash(slices.Concat([]byte{byte(s.Language)}, s.Bytes)).Bytes()
+}
+
+// content returns the script as it appears inside witness sets and
+// reference scripts.
+func (s Script) content() any {
+	if s.Language == NativeScript {
+		return cbor.RawMessage(s.Bytes)
+	}
+	return s.Bytes
+}
+
+// Output is a transaction output to create.
+type Output struct {
+	// Address is bech32, base58 (Byron) or hex.
+	Address string
+	// Value is raised to the minimum ADA for the output if it holds less.
+	Value Value
+	// Datum is Plutus data CBOR to store inline; DatumHash is the hash of a
+	// datum kept off-chain. Set at most one.
+	Datum     []byte
+	DatumHash []byte
+	// ScriptRef is a reference script to store in the output.
+	ScriptRef *Script
+}
+
+type builderOutput struct {
+	address []byte
+	Output
+}
+
+type exUnits struct {
+	memory uint64
+	steps  uint64
+}
+
+type scriptSpend struct {
+	utxo     *query.AnyUtxoData
+	redeemer []byte
+	units    exUnits
+}
+
+type policyMint struct {
+	policyID []byte
+	// assets maps hex asset names to quantities, negative when burning.
+	assets   map[string]int64
+	redeemer []byte
+	units    exUnits
+}
+
+// TxBuilder assembles an unsigned Conway transaction from outputs, script
+// spends, mints and metadata, then balances it against the chain through a
+// [Client]: inputs are selected from the UTxOs at the change address (with
+// pending mempool transactions taken into account, see
+// [Client.GetSpendableUtxos]), the fee is computed from the current
+// protocol parameters, and Plutus execution units come from Submit.EvalTx.
+//
+// Setters validate their arguments; the first failure is kept and returned
+// by [TxBuilder.Build].
+//
+//	unsigned, err := client.NewTxBuilder().
+//	    PayTo("addr1...", cardano.Value{Lovelace: 5_000_000}).
+//	    ChangeAddress("addr1...").
+//	    Build()
+//	signed, err := cardano.SignTransaction(unsigned, signer)
+type TxBuilder struct {
+	client          *Client
+	outputs         []builderOutput
+	inputs          []*query.AnyUtxoData
+	scriptInputs    []*scriptSpend
+	referenceInputs []*query.AnyUtxoData
+	scripts         []Script
+	datums          [][]byte
+	mints           []*policyMint
+	metadata        cborMap
+	validFrom       uint64
+	ttl             uint64
+	requiredSigners [][]byte
+	changeAddress   []byte
+	selector        CoinSelector
+	err             error
+}
+
+// NewTxBuilder returns an empty [TxBuilder] that reads chain state through
+// c.
+func (c *Client) NewTxBuilder() *TxBuilder {
+	return &TxBuilder{client: c, selector: LargestFirst()}
+}
+
+// PayTo adds an output sending value to address.
+func (b *TxBuilder) PayTo(address string, value Value) *TxBuilder {
+	return b.AddOutput(Output{Address: address, Value: value})
+}
+
+// AddOutput adds an output.
+func (b *TxBuilder) AddOutput(output Output) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	address, err := ParseAddress(output.Address)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	if output.Datum != nil && output.DatumHash != nil {
+		b.err = errors.New("output has both an inline datum and a datum hash")
+		return b
+	}
+	for unit := range output.Value.Assets {
+		if _, _, err := splitAssetUnit(unit); err != nil {
+			b.err = err
+			return b
+		}
+	}
+	output.Value.Assets = maps.Clone(output.Value.Assets)
+	b.outputs = append(b.outputs, builderOutput{address: address, Output: output})
+	return b
+}
+
+// AddInput spends key-locked UTxOs in addition to those selected
+// automatically.
+func (b *TxBuilder) AddInput(utxos ...*query.AnyUtxoData) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	for _, utxo := range utxos {
+		if err := checkUtxo(utxo); err != nil {
+			b.err = err
+			return b
+		}
+	}
+	b.inputs = append(b.inputs, utxos...)
+	return b
+}
+
+// SpendScript spends a UTxO locked by a Plutus script, passing redeemer
+// (Plutus data CBOR). The script must be attached with
+// [TxBuilder.AttachScript] or available as a reference script in an input
+// or reference input. A datum the UTxO holds only by hash must be attached
+// with [TxBuilder.AttachDatum].
+func (b *TxBuilder) SpendScript(utxo *query.AnyUtxoData, redeemer []byte) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	if err := checkUtxo(utxo); err != nil {
+		b.err = err
+		return b
+	}
+	if _, ok := paymentScriptHash(utxoOutput(utxo).GetAddress()); !ok {
+		b.err = fmt.Errorf("utxo %s is not locked by a script", utxoName(utxo))
+		return b
+	}
+	if len(redeemer) == 0 {
+		b.err = errors.New("script spend has no redeemer")
+		return b
+	}
+	b.scriptInputs = append(b.scriptInputs, &scriptSpend{utxo: utxo, redeemer: redeemer})
+	return b
+}
+
+// ReadFrom adds reference inputs, whose reference scripts and datums the
+// transaction can use without spending them.
+func (b *TxBuilder) ReadFrom(utxos ...*query.AnyUtxoData) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	for _, utxo := range utxos {
+		if err := checkUtxo(utxo); err != nil {
+			b.err = err
+			return b
+		}
+	}
+	b.referenceInputs = append(b.referenceInputs, utxos...)
+	return b
+}
+
+// AttachScript includes a script in the witness set.
+func (b *TxBuilder) AttachScript(script Script) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	if script.Language > PlutusV3 {
+		b.err = fmt.Errorf("unsupported script language %s", script.Language)
+		return b
+	}
+	b.scripts = append(b.scripts, script)
+	return b
+}
+
+// AttachDatum includes a datum (Plutus data CBOR) in the witness set, for
+// spending outputs that hold only its hash.
+func (b *TxBuilder) AttachDatum(datum []byte) *TxBuilder {
+	b.datums = append(b.datums, datum)
+	return b
+}
+
+// Mint mints (positive quantities) or burns (negative) assets of a hex
+// policy ID. assets maps hex asset names to quantities. redeemer is the
+// Plutus data CBOR for a Plutus policy and nil for a native one. The
+// policy script is found as for [TxBuilder.SpendScript]. Minting the same
+// policy again merges the assets.
+func (b *TxBuilder) Mint(policyID string, assets map[string]int64, redeemer []byte) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	policy, err := decodePolicyID(policyID)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	for name := range assets {
+		if _, err := decodeAssetName(name); err != nil {
+			b.err = err
+			return b
+		}
+	}
+	i := slices.IndexFunc(b.mints, func(m *policyMint) bool { return bytes.Equal(m.policyID, policy) })
+	if i < 0 {
+		b.mints = append(b.mints, &policyMint{policyID: policy, assets: make(map[string]int64)})
+		i = len(b.mints) - 1
+	}
+	mint := b.mints[i]
+	for name, quantity := range assets {
+		mint.assets[name] += quantity
+	}
+	if redeemer != nil {
+		mint.redeemer = redeemer
+	}
+	return b
+}
+
+// SetMetadata sets transaction metadata under label. value must be
+// encodable as a metadatum: integers, byte strings, text of at most 64
+// bytes, and lists or maps of those.
+func (b *TxBuilder) SetMetadata(label uint64, value any) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	if _, err := cbor.Encode(value); err != nil {
+		b.err = fmt.Errorf("failed to encode metadata %d: %w", label, err)
+		return b
+	}
+	b.metadata = slices.DeleteFunc(b.metadata, func(e cborEntry) bool { return e.key == label })
+	b.metadata = append(b.metadata, cborEntry{label, value})
+	return b
+}
+
+// ValidFrom sets the first slot in which the transaction is valid.
+func (b *TxBuilder) ValidFrom(slot uint64) *TxBuilder {
+	b.validFrom = slot
+	return b
+}
+
+// ValidUntil sets the transaction's TTL: the first slot in which it is no
+// longer valid.
+func (b *TxBuilder) ValidUntil(slot uint64) *TxBuilder {
+	b.ttl = slot
+	return b
+}
+
+// RequireSigner lists a key hash that must sign the transaction, making it
+// visible to scripts. credential is a 28-byte hex hash, a bech32 key hash
+// or an address whose payment key is used.
+func (b *TxBuilder) RequireSigner(credential string) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	keyHash, err := decodePaymentPart(credential)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	b.requiredSigners = append(b.requiredSigners, keyHash)
+	return b
+}
+
+// ChangeAddress sets the address whose UTxOs fund the transaction and
+// which receives the change. It is required.
+func (b *TxBuilder) ChangeAddress(address string) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.changeAddress, b.err = ParseAddress(address)
+	return b
+}
+
+// CoinSelection sets how inputs are picked from the change address UTxOs.
+// The default is [LargestFirst].
+func (b *TxBuilder) CoinSelection(selector CoinSelector) *TxBuilder {
+	if b.err != nil {
+		return b
+	}
+	if selector == nil {
+		b.err = errors.New("coin selector is nil")
+		return b
+	}
+	b.selector = selector
+	return b
+}
+
+// Build calls [TxBuilder.BuildWithContext] with a background context.
+func (b *TxBuilder) Build() (string, error) {
+	return b.BuildWithContext(context.Background())
+}
+
+// BuildWithContext balances the transaction and returns its unsigned CBOR
+// as hex, ready for [SignTransaction].
+//
+// Outputs below the minimum ADA are raised to it. Inputs are added from the
+// change address by the [TxBuilder.CoinSelection] strategy until they
+// cover the outputs, burns and fee; the surplus goes to a change output, or
+// to the fee when it is too small for one. If the transaction runs Plutus
+// scripts, it is evaluated with Submit.EvalTx to set the redeemers'
+// execution units, and collateral is picked from the change address with
+// [SelectCollateral], with a collateral return. It fails with
+// [ErrInsufficientFunds] or [ErrNoCollateral] when funds fall short, and
+// with the evaluation error when a script fails.
+//
+// UTxOs spent by transactions in the mempool are skipped, as in
+// [Client.GetSpendableUtxos]; servers without Submit.ReadMempool fall back
+// to the confirmed UTxOs.
+func (b *TxBuilder) BuildWithContext(ctx context.Context) (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.changeAddress == nil {
+		return "", errors.New("change address is not set")
+	}
+	params, err := b.client.cardanoParams(ctx)
+	if err != nil {
+		return "", err
+	}
+	available, err := b.changeUtxos(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to read change address UTxOs: %w", err)
+	}
+
+	d := &txDraft{builder: b, params: params}
+	d.inputs = slices.Concat(b.inputs, utxosOf(b.scriptInputs))
+	if n := d.redeemerCount(); n > 0 {
+		// Budget the fee for the largest possible execution until the
+		// evaluation reports the actual units.
+		limit := params.GetMaxExecutionUnitsPerTransaction()
+		placeholder := exUnits{memory: limit.GetMemory() / n, steps: limit.GetSteps() / n}
+		for _, units := range d.redeemerTargets() {
+			*units = placeholder
+		}
+	}
+	if err := d.balance(available); err != nil {
+		return "", err
+	}
+
+	if d.redeemerCount() > 0 {
+		raw, err := d.encode(false)
+		if err != nil {
+			return "", err
+		}
+		report, err := b.client.EvaluateTransactionReportWithContext(ctx, hex.EncodeToString(raw))
+		if err != nil {
+			return "", err
+		}
+		if err := report.Err(); err != nil {
+			return "", fmt.Errorf("script evaluation failed: %w", err)
+		}
+		costs := make(map[redeemerKey]exUnits, len(report.Redeemers))
+		for _, cost := range report.Redeemers {
+			costs[redeemerKey{cost.Purpose, cost.Index}] = exUnits{memory: cost.Memory, steps: cost.Steps}
+		}
+		targets := d.redeemerTargets()
+		for key, units := range targets {
+			cost, ok := costs[key]
+			if !ok {
+				return "", fmt.Errorf("evaluation reported no cost for %s", redeemerName(key.purpose, key.index))
+			}
+			*units = cost
+		}
+		d.fee = 0
+		if err := d.balance(available); err != nil {
+			return "", err
+		}
+	}
+
+	raw, err := d.encode(false)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// txDraft is a transaction being balanced.
+type txDraft struct {
+	builder *TxBuilder
+	params  *chaincardano.PParams
+	// inputs are the spent UTxOs, explicit ones first.
+	inputs []*query.AnyUtxoData
+	// selected counts the inputs picked by the coin selector.
+	selected int
+	// fee is the minimum fee found so far; feeField is the fee written to
+	// the body, which absorbs change too small for an output.
+	fee              uint64
+	feeField         uint64
+	outputs          []builderOutput
+	change           *builderOutput
+	collateral       []*query.AnyUtxoData
+	collateralReturn *builderOutput
+	totalCollateral  uint64
+}
+
+type redeemerKey struct {
+	purpose chaincardano.RedeemerPurpose
+	index   uint32
+}
+
+func utxosOf(spends []*scriptSpend) []*query.AnyUtxoData {
+	utxos := make([]*query.AnyUtxoData, len(spends))
+	for i, spend := range spends {
+		utxos[i] = spend.utxo
+	}
+	return utxos
+}
+
+func (d *txDraft) redeemerCount() uint64 {
+	n := uint64(len(d.builder.scriptInputs))
+	for _, mint := range d.builder.mints {
+		if mint.redeemer != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// changeUtxos returns the change address UTxOs not spent in the mempool,
+// or all confirmed ones if the server cannot read its mempool.
+func (b *TxBuilder) changeUtxos(ctx context.Context) ([]*query.AnyUtxoData, error) {
+	predicate := NewUtxoPredicate().Address(hex.EncodeToString(b.changeAddress))
+	spendable, err := b.client.GetSpendableUtxosWithContext(ctx, predicate)
+	if connect.CodeOf(err) == connect.CodeUnimplemented {
+		var confirmed []*query.AnyUtxoData
+		for resp, err := range b.client.GetUtxosByPredicatePagesWithContext(ctx, predicate) {
+			if err != nil {
+				return nil, err
+			}
+			confirmed = append(confirmed, resp.Msg.GetItems()...)
+		}
+		return confirmed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	available := make([]*query.AnyUtxoData, 0, len(spendable))
+	for _, utxo := range spendable {
+		available = append(available, &query.AnyUtxoData{
+			TxoRef:      utxo.Ref,
+			ParsedState: &query.AnyUtxoData_Cardano{Cardano: utxo.Output},
+		})
+	}
+	return available, nil
+}
+
+// balance selects inputs from available and sets the fee, change and
+// collateral until the transaction pays at least its minimum fee.
+func (d *txDraft) balance(available []*query.AnyUtxoData) error {
+	b := d.builder
+	d.outputs = make([]builderOutput, len(b.outputs))
+	for i, out := range b.outputs {
+		// Twice, since raising the coin can lengthen its encoding.
+		for range 2 {
+			minCoin, err := d.minCoin(out)
+			if err != nil {
+				return err
+			}
+			out.Value.Lovelace = max(out.Value.Lovelace, minCoin)
+		}
+		d.outputs[i] = out
+	}
+	minted, burned := b.mintedValue()
+	var produced Value
+	for _, out := range d.outputs {
+		produced = produced.Add(out.Value)
+	}
+	produced = produced.Add(burned)
+
+	used := make(map[string]bool)
+	for _, utxo := range d.inputs {
+		used[utxoKey(utxo)] = true
+	}
+	for range maxBalanceRounds {
+		consumed := minted
+		for _, utxo := range d.inputs {
+			consumed = consumed.Add(UtxoValue(utxo))
+		}
+		required := produced.Add(Value{Lovelace: d.fee})
+		if !consumed.Covers(required) {
+			if err := d.selectMore(available, used, required.Sub(consumed)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		surplus := consumed.Sub(required)
+		d.change = nil
+		d.feeField = d.fee
+		if surplus.Lovelace > 0 || surplus.HasAssets() {
+			change := builderOutput{address: b.changeAddress, Output: Output{Value: surplus}}
+			minCoin, err := d.minCoin(change)
+			if err != nil {
+				return err
+			}
+			if surplus.Lovelace >= minCoin {
+				d.change = &change
+			} else if surplus.HasAssets() {
+				missing := Value{Lovelace: minCoin - surplus.Lovelace}
+				if err := d.selectMore(available, used, missing); err != nil {
+					return err
+				}
+				continue
+			} else {
+				d.feeField += surplus.Lovelace
+			}
+		}
+		if d.redeemerCount() > 0 {
+			if err := d.selectCollateral(available); err != nil {
+				return err
+			}
+		}
+
+		raw, err := d.encode(true)
+		if err != nil {
+			return err
+		}
+		minFee, err := d.minFee(len(raw))
+		if err != nil {
+			return err
+		}
+		if d.feeField >= minFee {
+			return nil
+		}
+		d.fee = minFee
+	}
+	return errors.New("transaction balance did not settle")
+}
+
+// selectMore adds the UTxOs the builder's [CoinSelector] picks from the
+// unused available ones to cover missing. The selectors of this package
+// count the UTxOs picked in earlier rounds towards [WithMaxInputs].
+func (d *txDraft) selectMore(available []*query.AnyUtxoData, used map[string]bool, missing Value) error {
+	var unused []*query.AnyUtxoData
+	for _, utxo := range available {
+		if !used[utxoKey(utxo)] {
+			unused = append(unused, utxo)
+		}
+	}
+	var selected []*query.AnyUtxoData
+	var err error
+	if resumable, ok := d.builder.selector.(resumableSelector); ok {
+		selected, err = resumable.selectAfter(unused, missing, d.selected)
+	} else {
+		selected, err = d.builder.selector.Select(unused, missing)
+	}
+	if err != nil {
+		return err
+	}
+	d.selected += len(selected)
+	for _, utxo := range selected {
+		used[utxoKey(utxo)] = true
+		d.inputs = append(d.inputs, utxo)
+	}
+	return nil
+}
+
+// selectCollateral picks collateral for the current fee with
+// [SelectCollateral], returning the excess to the change address when it
+// is enough for an output.
+func (d *txDraft) selectCollateral(available []*query.AnyUtxoData) error {
+	required := (d.feeField*d.params.GetCollateralPercentage() + 99) / 100
+	seen := make(map[string]bool)
+	var candidates []*query.AnyUtxoData
+	for _, utxo := range slices.Concat(d.inputs, available) {
+		if key := utxoKey(utxo); !seen[key] {
+			seen[key] = true
+			candidates = append(candidates, utxo)
+		}
+	}
+	// #nosec G115 -- the protocol limit is a handful of inputs
+	collateral, err := SelectCollateral(candidates, required, int(d.params.GetMaxCollateralInputs()))
+	if err != nil {
+		return err
+	}
+	var total uint64
+	for _, utxo := range collateral {
+		total += UtxoValue(utxo).Lovelace
+	}
+	d.collateral = collateral
+	d.collateralReturn = nil
+	d.totalCollateral = total
+	ret := builderOutput{
+		address: d.builder.changeAddress,
+		Output:  Output{Value: Value{Lovelace: total - required}},
+	}
+	minCoin, err := d.minCoin(ret)
+	if err != nil {
+		return err
+	}
+	if ret.Value.Lovelace >= minCoin {
+		d.collateralReturn = &ret
+		d.totalCollateral = required
+	}
+	return nil
+}
+
+// minCoin returns the minimum ADA of an output.
+func (d *txDraft) minCoin(out builderOutput) (uint64, error) {
+	encoded, err := encodeOutput(out.address, out.Output)
+	if err != nil {
+		return 0, err
+	}
+	raw, err := cbor.Encode(encoded)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode output: %w", err)
+	}
+	return minUtxoCoin(bigIntUint64(d.params.GetCoinsPerUtxoByte()), len(raw)), nil
+}
+
+// minFee returns the minimum fee of a transaction of size bytes carrying
+// the draft's redeemers and reference scripts.
+func (d *txDraft) minFee(size int) (uint64, error) {
+	inputs := FeeInputs{Size: uint64(size)}
+	for _, units := range d.redeemerTargets() {
+		inputs.Memory += units.memory
+		inputs.Steps += units.steps
+	}
+	for _, utxo := range slices.Concat(d.inputs, d.builder.referenceInputs) {
+		size, err := utxoRefScriptSize(utxo)
+		if err != nil {
+			return 0, fmt.Errorf("utxo %s: %w", utxoName(utxo), err)
+		}
+		inputs.RefScriptSize += size
+	}
+	return CalculateFee(inputs, d.params).Total, nil
+}
+
+// sortedInputs returns the inputs in ledger order, which redeemer indexes
+// refer to.
+func (d *txDraft) sortedInputs() []*query.AnyUtxoData {
+	return sortUtxos(d.inputs)
+}
+
+func sortUtxos(utxos []*query.AnyUtxoData) []*query.AnyUtxoData {
+	sorted := slices.Clone(utxos)
+	slices.SortFunc(sorted, func(a, b *query.AnyUtxoData) int {
+		if c := bytes.Compare(a.GetTxoRef().GetHash(), b.GetTxoRef().GetHash()); c != 0 {
+			return c
+		}
+		return int(a.GetTxoRef().GetIndex()) - int(b.GetTxoRef().GetIndex())
+	})
+	return sorted
+}
+
+// sortedMints returns the mints in ledger (policy ID) order.
+func (d *txDraft) sortedMints() []*policyMint {
+	sorted := slices.Clone(d.builder.mints)
+	slices.SortFunc(sorted, func(a, b *policyMint) int { return bytes.Compare(a.policyID, b.policyID) })
+	return sorted
+}
+
+// redeemerTargets maps each redeemer's ledger purpose and index to its
+// execution units.
+func (d *txDraft) redeemerTargets() map[redeemerKey]*exUnits {
+	targets := make(map[redeemerKey]*exUnits)
+	for i, utxo := range d.sortedInputs() {
+		for _, spend := range d.builder.scriptInputs {
+			if spend.utxo == utxo {
+				// #nosec G115 -- input counts are bounded by the max tx size
+				targets[redeemerKey{chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_SPEND, uint32(i)}] = &spend.units
+			}
+		}
+	}
+	for i, mint := range d.sortedMints() {
+		if mint.redeemer != nil {
+			// #nosec G115 -- policy counts are bounded by the max tx size
+			targets[redeemerKey{chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_MINT, uint32(i)}] = &mint.units
+		}
+	}
+	return targets
+}
+
+// encode returns the transaction CBOR. With dummyWitnesses it carries a
+// zero-filled vkey witness per expected signer, sizing it for the fee.
+func (d *txDraft) encode(dummyWitnesses bool) ([]byte, error) {
+	b := d.builder
+	inputs := d.sortedInputs()
+	body := cborMap{
+		{uint64(0), cborSet(txoRefs(inputs))},
+		{uint64(2), d.feeField},
+	}
+	outputs := make([]any, 0, len(d.outputs)+1)
+	for _, out := range append(slices.Clone(d.outputs), derefOutputs(d.change)...) {
+		encoded, err := encodeOutput(out.address, out.Output)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, encoded)
+	}
+	body = append(body, cborEntry{uint64(1), outputs})
+	if b.ttl > 0 {
+		body = append(body, cborEntry{uint64(3), b.ttl})
+	}
+	var auxData []byte
+	if len(b.metadata) > 0 {
+		var err error
+		if auxData, err = cbor.Encode(b.metadata); err != nil {
+			return nil, fmt.Errorf("failed to encode metadata: %w", err)
+		}
+		body = append(body, cborEntry{uint64(7), common.Blake2b256Hash(auxData).Bytes()})
+	}
+	if b.validFrom > 0 {
+		body = append(body, cborEntry{uint64(8), b.validFrom})
+	}
+	mints := d.sortedMints()
+	if len(mints) > 0 {
+		assets := make(map[string]int64)
+		for _, mint := range mints {
+			for name, quantity := range mint.assets {
+				if quantity != 0 {
+					assets[hex.EncodeToString(mint.policyID)+name] = quantity
+				}
+			}
+		}
+		mint, err := encodeMultiAsset(assets)
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, cborEntry{uint64(9), mint})
+	}
+	if d.collateral != nil && d.redeemerCount() > 0 {
+		body = append(body, cborEntry{uint64(13), cborSet(txoRefs(d.collateral))})
+		if d.collateralReturn != nil {
+			ret, err := encodeOutput(d.collateralReturn.address, d.collateralReturn.Output)
+			if err != nil {
+				return nil, err
+			}
+			body = append(body, cborEntry{uint64(16), ret})
+		}
+		body = append(body, cborEntry{uint64(17), d.totalCollateral})
+	}
+	if len(b.requiredSigners) > 0 {
+		body = append(body, cborEntry{uint64(14), cborSet(b.requiredSigners)})
+	}
+	if len(b.referenceInputs) > 0 {
+		body = append(body, cborEntry{uint64(18), cborSet(txoRefs(sortUtxos(b.referenceInputs)))})
+	}
+
+	witnesses, scriptData, err := d.witnessSet(inputs, mints, dummyWitnesses)
+	if err != nil {
+		return nil, err
+	}
+	if scriptData != nil {
+		body = append(body, cborEntry{uint64(11), scriptData})
+	}
+
+	var aux any
+	if auxData != nil {
+		aux = cbor.RawMessage(auxData)
+	}
+	raw, err := cbor.Encode([]any{body, witnesses, true, aux})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	return raw, nil
+}
+
+// witnessSet returns the witness set and, when it has redeemers or datums,
+// the script data hash.
+func (d *txDraft) witnessSet(
+	inputs []*query.AnyUtxoData,
+	mints []*policyMint,
+	dummyWitnesses bool,
+) (cborMap, []byte, error) {
+	b := d.builder
+	var witnesses cborMap
+	if dummyWitnesses {
+		signers := d.signerCount()
+		vkeys := make([]any, signers)
+		for i := range vkeys {
+			vkeys[i] = []any{make([]byte, 32), make([]byte, 64)}
+		}
+		if signers > 0 {
+			witnesses = append(witnesses, cborEntry{uint64(witnessSetVkeyKey), vkeys})
+		}
+	}
+	byLanguage := make(map[ScriptLanguage][]any)
+	for _, script := range b.scripts {
+		byLanguage[script.Language] = append(byLanguage[script.Language], script.content())
+	}
+	for language, key := range map[ScriptLanguage]uint64{NativeScript: 1, PlutusV1: 3, PlutusV2: 6, PlutusV3: 7} {
+		if scripts := byLanguage[language]; len(scripts) > 0 {
+			witnesses = append(witnesses, cborEntry{key, scripts})
+		}
+	}
+
+	var datums []byte
+	if len(b.datums) > 0 {
+		list := make([]cbor.RawMessage, len(b.datums))
+		for i, datum := range b.datums {
+			list[i] = datum
+		}
+		var err error
+		if datums, err = cbor.Encode(list); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode datums: %w", err)
+		}
+		witnesses = append(witnesses, cborEntry{uint64(4), cbor.RawMessage(datums)})
+	}
+
+	var redeemers []any
+	var hashes [][]byte
+	for i, utxo := range inputs {
+		for _, spend := range b.scriptInputs {
+			if spend.utxo == utxo {
+				redeemers = append(redeemers, []any{uint64(0), uint64(i), cbor.RawMessage(spend.redeemer),
+					[]uint64{spend.units.memory, spend.units.steps}})
+				hash, _ := paymentScriptHash(utxoOutput(utxo).GetAddress())
+				hashes = append(hashes, hash)
+			}
+		}
+	}
+	for i, mint := range mints {
+		if mint.redeemer != nil {
+			redeemers = append(redeemers, []any{uint64(1), uint64(i), cbor.RawMessage(mint.redeemer),
+				[]uint64{mint.units.memory, mint.units.steps}})
+			hashes = append(hashes, mint.policyID)
+		}
+	}
+	if len(redeemers) == 0 && datums == nil {
+		return witnesses, nil, nil
+	}
+	// Without redeemers Conway hashes an empty map, not an empty list.
+	encodedRedeemers := []byte{0xa0}
+	if len(redeemers) > 0 {
+		var err error
+		if encodedRedeemers, err = cbor.Encode(redeemers); err != nil {
+			return nil, nil, fmt.Errorf("failed to encode redeemers: %w", err)
+		}
+		witnesses = append(witnesses, cborEntry{uint64(5), cbor.RawMessage(encodedRedeemers)})
+	}
+
+	languages, err := d.languages(hashes)
+	if err != nil {
+		return nil, nil, err
+	}
+	views, err := languageViews(languages, costModels(d.params.GetCostModels()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode cost models: %w", err)
+	}
+	return witnesses, scriptDataHash(encodedRedeemers, datums, views), nil
+}
+
+// languages returns the sorted, distinct Plutus languages of the scripts
+// with the given hashes.
+func (d *txDraft) languages(hashes [][]byte) ([]ScriptLanguage, error) {
+	known := make(map[string]ScriptLanguage)
+	for _, script := range d.builder.scripts {
+		known[string(script.Hash())] = script.Language
+	}
+	for _, utxo := range slices.Concat(d.inputs, d.builder.referenceInputs) {
+		if script, ok := utxoScript(utxo); ok {
+			known[string(script.Hash())] = script.Language
+		}
+	}
+	var languages []ScriptLanguage
+	for _, hash := range hashes {
+		language, ok := known[string(hash)]
+		if !ok {
+			return nil, fmt.Errorf("no script attached or referenced for hash %x", hash)
+		}
+		if language != NativeScript && !slices.Contains(languages, language) {
+			languages = append(languages, language)
+		}
+	}
+	slices.Sort(languages)
+	return languages, nil
+}
+
+// signerCount returns the number of distinct keys expected to sign: those
+// locking key inputs and collateral, and the required signers.
+func (d *txDraft) signerCount() int {
+	keys := make(map[string]bool)
+	utxos := slices.Clone(d.inputs)
+	if d.collateral != nil && d.redeemerCount() > 0 {
+		utxos = append(utxos, d.collateral...)
+	}
+	for _, utxo := range utxos {
+		if hash, ok := paymentKeyHash(utxoOutput(utxo).GetAddress()); ok {
+			keys[string(hash)] = true
+		}
+	}
+	for _, signer := range d.builder.requiredSigners {
+		keys[string(signer)] = true
+	}
+	return len(keys)
+}
+
+// mintedValue splits the mints into the minted and the burned assets.
+func (b *TxBuilder) mintedValue() (minted, burned Value) {
+	for _, mint := range b.mints {
+		for name, quantity := range mint.assets {
+			unit := hex.EncodeToString(mint.policyID) + name
+			switch {
+			case quantity > 0:
+				minted = minted.Add(Value{Assets: map[string]uint64{unit: uint64(quantity)}})
+			case quantity < 0:
+				burned = burned.Add(Value{Assets: map[string]uint64{unit: uint64(-quantity)}})
+			}
+		}
+	}
+	return minted, burned
+}
+
+func derefOutputs(out *builderOutput) []builderOutput {
+	if out == nil {
+		return nil
+	}
+	return []builderOutput{*out}
+}
+
+func txoRefs(utxos []*query.AnyUtxoData) []any {
+	refs := make([]any, len(utxos))
+	for i, utxo := range utxos {
+		refs[i] = []any{utxo.GetTxoRef().GetHash(), uint64(utxo.GetTxoRef().GetIndex())}
+	}
+	return refs
+}
+
+func checkUtxo(utxo *query.AnyUtxoData) error {
+	if len(utxo.GetTxoRef().GetHash()) != 32 {
+		return errors.New("utxo has no transaction reference")
+	}
+	if utxoOutput(utxo) == nil {
+		return fmt.Errorf("utxo %s has no decodable output", utxoName(utxo))
+	}
+	return nil
+}
+
+func utxoKey(utxo *query.AnyUtxoData) string {
+	return inputKey(utxo.GetTxoRef().GetHash(), utxo.GetTxoRef().GetIndex())
+}
+
+func utxoName(utxo *query.AnyUtxoData) string {
+	return fmt.Sprintf("%x#%d", utxo.GetTxoRef().GetHash(), utxo.GetTxoRef().GetIndex())
+}
+
+// utxoScript returns the Plutus reference script of a UTxO.
+func utxoScript(utxo *query.AnyUtxoData) (Script, bool) {
+	switch script := utxoOutput(utxo).GetScript().GetScript().(type) {
+	case *chaincardano.Script_PlutusV1:
+		return Script{Language: PlutusV1, Bytes: script.PlutusV1}, true
+	case *chaincardano.Script_PlutusV2:
+		return Script{Language: PlutusV2, Bytes: script.PlutusV2}, true
+	case *chaincardano.Script_PlutusV3:
+		return Script{Language: PlutusV3, Bytes: script.PlutusV3}, true
+	}
+	return Script{}, false
+}
+
+// paymentKeyHash returns the payment key hash of a key-locked Shelley
+// address.
+func paymentKeyHash(address []byte) ([]byte, bool) {
+	return paymentCredential(address, false)
+}
+
+// paymentScriptHash returns the payment script hash of a script-locked
+// Shelley address.
+func paymentScriptHash(address []byte) ([]byte, bool) {
+	return paymentCredential(address, true)
+}
+
+func paymentCredential(address []byte, script bool) ([]byte, bool) {
+	addr, err := common.NewAddressFromBytes(address)
+	if err != nil {
+		return nil, false
+	}
+	switch addr.PayloadPayload().(type) {
+	case common.AddressPayloadKeyHash:
+		if !script {
+			return addr.PaymentKeyHash().Bytes(), true
+		}
+	case common.AddressPayloadScriptHash:
+		if script {
+			return addr.PaymentKeyHash().Bytes(), true
+		}
+	}
+	return nil, false
+}
+
+// costModels indexes the Plutus cost models by language.
+func costModels(models *chaincardano.CostModels) map[ScriptLanguage][]int64 {
+	return map[ScriptLanguage][]int64{
+		PlutusV1: models.GetPlutusV1().GetValues(),
+		PlutusV2: models.GetPlutusV2().GetValues(),
+		PlutusV3: models.GetPlutusV3().GetValues(),
+	}
+}
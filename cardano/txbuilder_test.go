@@ -0,0 +1,411 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/blinklabs-io/gouroboros/ledger/conway"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+	"google.golang.org/protobuf/proto"
+)
+
+// builderParams extends validationParams with what script transactions
+// need.
+func builderParams() *chaincardano.PParams {
+	params := proto.Clone(validationParams).(*chaincardano.PParams)
+	params.MaxExecutionUnitsPerTransaction = evalTestParams.GetMaxExecutionUnitsPerTransaction()
+	params.CostModels = &chaincardano.CostModels{
+		PlutusV3: &chaincardano.CostModel{Values: []int64{100, 200, 300}},
+	}
+	return params
+}
+
+func builderUtxo(hash byte, address []byte, value Value) *query.AnyUtxoData {
+	output := &chaincardano.TxOutput{
+		Address: address,
+		Coin:    &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: int64(value.Lovelace)}},
+	}
+	for unit, quantity := range value.Assets {
+		policy, name, _ := splitAssetUnit(unit)
+		output.Assets = append(output.Assets, &chaincardano.Multiasset{
+			PolicyId: policy,
+			Assets: []*chaincardano.Asset{{
+				Name:     name,
+				Quantity: &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: int64(quantity)}},
+			}},
+		})
+	}
+	return &query.AnyUtxoData{
+		TxoRef:      &query.TxoRef{Hash: bytes.Repeat([]byte{hash}, 32)},
+		ParsedState: &query.AnyUtxoData_Cardano{Cardano: output},
+	}
+}
+
+func newBuilderTestClient(t *testing.T, items []*query.AnyUtxoData, submitH *builderSubmitHandler) *Client {
+	t.Helper()
+	return newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return queryconnect.NewQueryServiceHandler(&builderQueryHandler{
+				paramsQueryHandler: paramsQueryHandler{params: builderParams()},
+				items:              items,
+			})
+		},
+		func() (string, http.Handler) {
+			return submitconnect.NewSubmitServiceHandler(submitH)
+		},
+	)
+}
+
+// checkBalanced verifies that a built transaction spends exactly what it
+// produces plus its fee, and pays at least the minimum fee once signed.
+func checkBalanced(t *testing.T, unsigned string, utxos []*query.AnyUtxoData, minted Value) *DecodedTx {
+	t.Helper()
+	signer, err := NewEd25519Signer(mustDecodeHex(t, testSeed))
+	if err != nil {
+		t.Fatalf("NewEd25519Signer returned error: %v", err)
+	}
+	signed, err := SignTransaction(unsigned, signer)
+	if err != nil {
+		t.Fatalf("SignTransaction returned error: %v", err)
+	}
+	tx, err := DecodeTransaction(signed)
+	if err != nil {
+		t.Fatalf("DecodeTransaction returned error: %v", err)
+	}
+
+	consumed := minted
+	for _, input := range tx.Inputs {
+		i := slices.IndexFunc(utxos, func(u *query.AnyUtxoData) bool {
+			return string(u.GetTxoRef().GetHash()) == string(input.GetTxHash())
+		})
+		if i < 0 {
+			t.Fatalf("input %x is not a known UTxO", input.GetTxHash())
+		}
+		consumed = consumed.Add(UtxoValue(utxos[i]))
+	}
+	produced := Value{Lovelace: tx.Fee}
+	for _, output := range tx.Outputs {
+		produced = produced.Add(outputValue(output))
+	}
+	if !consumed.Covers(produced) || !produced.Covers(consumed) {
+		t.Fatalf("consumed %+v, produced %+v", consumed, produced)
+	}
+
+	minFee, err := CalculateTxFee(signed, 0, builderParams())
+	if err != nil {
+		t.Fatalf("CalculateTxFee returned error: %v", err)
+	}
+	if tx.Fee < minFee.Total {
+		t.Fatalf("fee %d is below the signed minimum %d", tx.Fee, minFee.Total)
+	}
+	return tx
+}
+
+func TestTxBuilderPayment(t *testing.T) {
+	wallet, walletRaw := testAddress(t, 0x11, 0x22)
+	payee, payeeRaw := testAddress(t, 0x33, 0x44)
+	utxos := []*query.AnyUtxoData{
+		builderUtxo(0xaa, walletRaw, Value{Lovelace: 3_000_000}),
+		builderUtxo(0xbb, walletRaw, Value{Lovelace: 10_000_000}),
+	}
+	client := newBuilderTestClient(t, utxos, &builderSubmitHandler{})
+
+	unsigned, err := client.NewTxBuilder().
+		PayTo(payee, Value{Lovelace: 5_000_000}).
+		SetMetadata(674, map[string]any{"msg": []string{"hello"}}).
+		ValidUntil(1000).
+		ChangeAddress(wallet).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	tx := checkBalanced(t, unsigned, utxos, Value{})
+	if len(tx.Inputs) != 1 || tx.Inputs[0].GetTxHash()[0] != 0xbb {
+		t.Fatalf("inputs = %v, want only the largest UTxO", tx.Inputs)
+	}
+	if len(tx.Outputs) != 2 ||
+		string(tx.Outputs[0].GetAddress()) != string(payeeRaw) ||
+		bigIntUint64(tx.Outputs[0].GetCoin()) != 5_000_000 ||
+		string(tx.Outputs[1].GetAddress()) != string(walletRaw) {
+		t.Fatalf("outputs = %v, want the payment then change", tx.Outputs)
+	}
+	if tx.TTL != 1000 || tx.Ledger.Metadata() == nil {
+		t.Fatalf("TTL = %d, metadata = %v", tx.TTL, tx.Ledger.Metadata())
+	}
+}
+
+func TestTxBuilderWithoutMempoolUsesConfirmedUtxos(t *testing.T) {
+	wallet, walletRaw := testAddress(t, 0x11, 0x22)
+	payee, _ := testAddress(t, 0x33, 0x44)
+	utxos := []*query.AnyUtxoData{builderUtxo(0xaa, walletRaw, Value{Lovelace: 10_000_000})}
+	// No submit service, so Submit.ReadMempool is unimplemented.
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return queryconnect.NewQueryServiceHandler(&builderQueryHandler{
+			paramsQueryHandler: paramsQueryHandler{params: builderParams()},
+			items:              utxos,
+		})
+	})
+
+	unsigned, err := client.NewTxBuilder().
+		PayTo(payee, Value{Lovelace: 5_000_000}).
+		ChangeAddress(wallet).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	checkBalanced(t, unsigned, utxos, Value{})
+}
+
+func TestTxBuilderAssetsAndMinAda(t *testing.T) {
+	wallet, walletRaw := testAddress(t, 0x11, 0x22)
+	payee, _ := testAddress(t, 0x33, 0x44)
+	policy := strings.Repeat("ab", credentialHashSize)
+	token := policy + "746f6b656e"
+	utxos := []*query.AnyUtxoData{
+		builderUtxo(0xaa, walletRaw, Value{Lovelace: 1_500_000, Assets: map[string]uint64{token: 10}}),
+		builderUtxo(0xbb, walletRaw, Value{Lovelace: 4_000_000}),
+	}
+	client := newBuilderTestClient(t, utxos, &builderSubmitHandler{})
+
+	unsigned, err := client.NewTxBuilder().
+		PayTo(payee, Value{Assets: map[string]uint64{token: 4}}).
+		ChangeAddress(wallet).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	tx := checkBalanced(t, unsigned, utxos, Value{})
+	payment := outputValue(tx.Outputs[0])
+	if payment.Assets[token] != 4 || payment.Lovelace == 0 {
+		t.Fatalf("payment = %+v, want 4 tokens and the minimum ADA", payment)
+	}
+	if change := outputValue(tx.Outputs[1]); change.Assets[token] != 6 {
+		t.Fatalf("change = %+v, want the 6 remaining tokens", change)
+	}
+}
+
+func TestTxBuilderCoinSelection(t *testing.T) {
+	wallet, walletRaw := testAddress(t, 0x11, 0x22)
+	payee, _ := testAddress(t, 0x33, 0x44)
+	var utxos []*query.AnyUtxoData
+	for i := range 10 {
+		utxos = append(utxos, builderUtxo(byte(i+1), walletRaw, Value{Lovelace: 2_000_000}))
+	}
+	client := newBuilderTestClient(t, utxos, &builderSubmitHandler{})
+
+	unsigned, err := client.NewTxBuilder().
+		PayTo(payee, Value{Lovelace: 5_000_000}).
+		ChangeAddress(wallet).
+		CoinSelection(RandomImprove(WithSelectionSeed(3))).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	tx := checkBalanced(t, unsigned, utxos, Value{})
+	if len(tx.Inputs) < 3 {
+		t.Fatalf("inputs = %v, want at least 3", tx.Inputs)
+	}
+
+	_, err = client.NewTxBuilder().
+		PayTo(payee, Value{Lovelace: 5_000_000}).
+		ChangeAddress(wallet).
+		CoinSelection(LargestFirst(WithMaxInputs(2))).
+		Build()
+	if !errors.Is(err, ErrMaxInputsExceeded) {
+		t.Fatalf("Build error = %v, want ErrMaxInputsExceeded", err)
+	}
+
+	// Three inputs cover the payment, but not its fee as well.
+	_, err = client.NewTxBuilder().
+		PayTo(payee, Value{Lovelace: 6_000_000}).
+		ChangeAddress(wallet).
+		CoinSelection(LargestFirst(WithMaxInputs(3))).
+		Build()
+	if !errors.Is(err, ErrMaxInputsExceeded) {
+		t.Fatalf("Build error = %v, want ErrMaxInputsExceeded across rounds", err)
+	}
+}
+
+func TestTxBuilderInsufficientFunds(t *testing.T) {
+	wallet, walletRaw := testAddress(t, 0x11, 0x22)
+	client := newBuilderTestClient(t, []*query.AnyUtxoData{
+		builderUtxo(0xaa, walletRaw, Value{Lovelace: 2_000_000}),
+	}, &builderSubmitHandler{})
+
+	_, err := client.NewTxBuilder().
+		PayTo(wallet, Value{Lovelace: 5_000_000}).
+		ChangeAddress(wallet).
+		Build()
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Build error = %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestTxBuilderSetterErrors(t *testing.T) {
+	client := NewClient()
+	_, err := client.NewTxBuilder().PayTo("not an address", Value{}).Build()
+	if err == nil {
+		t.Fatal("Build accepted an invalid address")
+	}
+	_, err = client.NewTxBuilder().Mint("abcd", nil, nil).Build()
+	if err == nil {
+		t.Fatal("Build accepted an invalid policy ID")
+	}
+}
+
+func TestTxBuilderScriptSpendAndMint(t *testing.T) {
+	wallet, walletRaw := testAddress(t, 0x11, 0x22)
+	script := Script{Language: PlutusV3, Bytes: mustDecodeHex(t, "46010000222601")}
+	scriptAddress := append([]byte{0x71}, script.Hash()...)
+	locked := builderUtxo(0xcc, scriptAddress, Value{Lovelace: 20_000_000})
+	locked.GetCardano().Datum = &chaincardano.Datum{Hash: make([]byte, 32)}
+	utxos := []*query.AnyUtxoData{
+		builderUtxo(0xaa, walletRaw, Value{Lovelace: 8_000_000}),
+		locked,
+	}
+	fakeSubmit := &builderSubmitHandler{memory: 1000, steps: 2000}
+	client := newBuilderTestClient(t, utxos[:1], fakeSubmit)
+	policy := script.Hash()
+	token := AssetUnit(policy, []byte("nft"))
+
+	unsigned, err := client.NewTxBuilder().
+		SpendScript(locked, mustDecodeHex(t, "d87980")).
+		AttachScript(script).
+		AttachDatum(mustDecodeHex(t, "00")).
+		Mint(hex.EncodeToString(policy), map[string]int64{"6e6674": 1}, mustDecodeHex(t, "00")).
+		PayTo(wallet, Value{Lovelace: 15_000_000, Assets: map[string]uint64{token: 1}}).
+		ChangeAddress(wallet).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if fakeSubmit.evaluations != 1 {
+		t.Fatalf("EvalTx called %d times, want 1", fakeSubmit.evaluations)
+	}
+
+	tx := checkBalanced(t, unsigned, utxos, Value{Assets: map[string]uint64{token: 1}})
+	redeemers := 0
+	for key, value := range tx.Ledger.Witnesses().Redeemers().Iter() {
+		redeemers++
+		if value.ExUnits.Memory != 1000 || value.ExUnits.Steps != 2000 {
+			t.Fatalf("redeemer %v units = %+v, want the evaluated units", key, value.ExUnits)
+		}
+	}
+	if redeemers != 2 {
+		t.Fatalf("%d redeemers, want a spend and a mint", redeemers)
+	}
+	if tx.Ledger.ScriptDataHash() == nil {
+		t.Fatal("transaction has no script data hash")
+	}
+	collateral := tx.Ledger.Collateral()
+	if len(collateral) != 1 || collateral[0].Id().Bytes()[0] != 0xaa {
+		t.Fatalf("collateral = %v, want the wallet UTxO", collateral)
+	}
+	want := (tx.Fee*150 + 99) / 100
+	if got := tx.Ledger.TotalCollateral(); got == nil || got.Uint64() != want {
+		t.Fatalf("total collateral = %v, want %d", got, want)
+	}
+}
+
+func TestTxBuilderDatumOnlyScriptDataHash(t *testing.T) {
+	wallet, walletRaw := testAddress(t, 0x11, 0x22)
+	utxos := []*query.AnyUtxoData{builderUtxo(0xaa, walletRaw, Value{Lovelace: 8_000_000})}
+	client := newBuilderTestClient(t, utxos, &builderSubmitHandler{})
+
+	unsigned, err := client.NewTxBuilder().
+		AttachDatum(mustDecodeHex(t, "d87980")).
+		PayTo(wallet, Value{Lovelace: 2_000_000}).
+		ChangeAddress(wallet).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	tx := checkBalanced(t, unsigned, utxos, Value{})
+	conwayTx, ok := tx.Ledger.(*conway.ConwayTransaction)
+	if !ok {
+		t.Fatalf("built a %T, want a Conway transaction", tx.Ledger)
+	}
+	if conwayTx.WitnessSet.WsRedeemers.Len() != 0 {
+		t.Fatal("datum-only transaction carries redeemers")
+	}
+
+	// As the Conway ledger rule computes it: an empty redeemer map, the
+	// witness datums and the views of no languages.
+	views, err := common.EncodeLangViews(map[uint]struct{}{}, nil)
+	if err != nil {
+		t.Fatalf("EncodeLangViews returned error: %v", err)
+	}
+	want := common.Blake2b256Hash(slices.Concat(
+		[]byte{0xa0},
+		conwayTx.WitnessSet.WsPlutusData.Cbor(),
+		views,
+	))
+	if got := tx.Ledger.ScriptDataHash(); got == nil || *got != want {
+		t.Fatalf("script data hash = %v, want %s", got, want)
+	}
+}
+
+type builderQueryHandler struct {
+	paramsQueryHandler
+	items []*query.AnyUtxoData
+}
+
+func (q *builderQueryHandler) SearchUtxos(
+	context.Context,
+	*connect.Request[query.SearchUtxosRequest],
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	return connect.NewResponse(&query.SearchUtxosResponse{Items: q.items}), nil
+}
+
+// builderSubmitHandler serves an empty mempool and evaluates every
+// redeemer of a transaction at fixed units.
+type builderSubmitHandler struct {
+	submitconnect.UnimplementedSubmitServiceHandler
+	memory      uint64
+	steps       uint64
+	evaluations int
+}
+
+func (s *builderSubmitHandler) ReadMempool(
+	context.Context,
+	*connect.Request[submit.ReadMempoolRequest],
+) (*connect.Response[submit.ReadMempoolResponse], error) {
+	return connect.NewResponse(&submit.ReadMempoolResponse{}), nil
+}
+
+func (s *builderSubmitHandler) EvalTx(
+	_ context.Context,
+	req *connect.Request[submit.EvalTxRequest],
+) (*connect.Response[submit.EvalTxResponse], error) {
+	s.evaluations++
+	tx, err := decodeLedgerTransaction(req.Msg.GetTx().GetRaw())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	eval := &chaincardano.TxEval{}
+	for key := range tx.Witnesses().Redeemers().Iter() {
+		purpose := chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_SPEND
+		if key.Tag == 1 {
+			purpose = chaincardano.RedeemerPurpose_REDEEMER_PURPOSE_MINT
+		}
+		eval.Redeemers = append(eval.Redeemers, evalRedeemer(purpose, key.Index, s.memory, s.steps))
+	}
+	return connect.NewResponse(&submit.EvalTxResponse{
+		Report: &submit.AnyChainEval{Chain: &submit.AnyChainEval_Cardano{Cardano: eval}},
+	}), nil
+}
@@ -0,0 +1,186 @@
+package cardano
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"slices"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+)
+
+// cborMajorMap is the CBOR major type of maps.
+const cborMajorMap = 5
+
+// Tags used in transaction CBOR.
+const (
+	cborTagEncoded = 24  // embedded CBOR data item
+	cborTagSet     = 258 // Conway set
+)
+
+// cborEntry is one key and value of a [cborMap].
+type cborEntry struct {
+	key   any
+	value any
+}
+
+// cborMap encodes as a CBOR map with its keys in core deterministic order.
+// Unlike a Go map it can hold byte-string keys.
+type cborMap []cborEntry
+
+func (m cborMap) MarshalCBOR() ([]byte, error) {
+	type pair struct{ key, value []byte }
+	pairs := make([]pair, 0, len(m))
+	for _, entry := range m {
+		key, err := cbor.Encode(entry.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := cbor.Encode(entry.value)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair{key, value})
+	}
+	slices.SortFunc(pairs, func(a, b pair) int { return bytes.Compare(a.key, b.key) })
+	out := cborHead(cborMajorMap, uint64(len(pairs)))
+	for _, p := range pairs {
+		out = append(out, p.key...)
+		out = append(out, p.value...)
+	}
+	return out, nil
+}
+
+// cborHead returns the header of a CBOR data item of the given major type
+// and argument.
+func cborHead(major byte, n uint64) []byte {
+	m := major << 5
+	switch {
+	case n < 24:
+		return []byte{m | byte(n)}
+	case n <= 0xff:
+		return []byte{m | 24, byte(n)}
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{m | 25}, uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{m | 26}, uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{m | 27}, n)
+	}
+}
+
+// cborSet wraps items in the Conway set tag.
+func cborSet(items any) cbor.Tag {
+	return cbor.Tag{Number: cborTagSet, Content: items}
+}
+
+// encodeValue returns the ledger form of a value: a bare coin, or a coin
+// and a multi-asset map.
+func encodeValue(v Value) (any, error) {
+	if !v.HasAssets() {
+		return v.Lovelace, nil
+	}
+	assets := make(map[string]int64, len(v.Assets))
+	for unit, quantity := range v.Assets {
+		if quantity == 0 {
+			continue
+		}
+		// #nosec G115 -- asset quantities are bounded by 2^63 on chain
+		assets[unit] = int64(quantity)
+	}
+	multiAsset, err := encodeMultiAsset(assets)
+	if err != nil {
+		return nil, err
+	}
+	return []any{v.Lovelace, multiAsset}, nil
+}
+
+// encodeMultiAsset groups asset units by policy into a nested map. Negative
+// quantities are only valid in a mint.
+func encodeMultiAsset(assets map[string]int64) (cborMap, error) {
+	policies := make(map[string]cborMap)
+	var policyIDs [][]byte
+	for unit, quantity := range assets {
+		policyID, name, err := splitAssetUnit(unit)
+		if err != nil {
+			return nil, err
+		}
+		key := string(policyID)
+		if _, ok := policies[key]; !ok {
+			policyIDs = append(policyIDs, policyID)
+		}
+		policies[key] = append(policies[key], cborEntry{name, quantity})
+	}
+	out := make(cborMap, 0, len(policyIDs))
+	for _, policyID := range policyIDs {
+		out = append(out, cborEntry{policyID, policies[string(policyID)]})
+	}
+	return out, nil
+}
+
+// encodeOutput returns the post-Alonzo map form of an output.
+func encodeOutput(address []byte, out Output) (cborMap, error) {
+	value, err := encodeValue(out.Value)
+	if err != nil {
+		return nil, err
+	}
+	m := cborMap{{uint64(0), address}, {uint64(1), value}}
+	switch {
+	case out.Datum != nil:
+		m = append(m, cborEntry{uint64(2), []any{
+			uint64(1),
+			cbor.Tag{Number: cborTagEncoded, Content: out.Datum},
+		}})
+	case out.DatumHash != nil:
+		m = append(m, cborEntry{uint64(2), []any{uint64(0), out.DatumHash}})
+	}
+	if out.ScriptRef != nil {
+		script, err := cbor.Encode([]any{uint64(out.ScriptRef.Language), out.ScriptRef.content()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode reference script: %w", err)
+		}
+		m = append(m, cborEntry{uint64(3), cbor.Tag{Number: cborTagEncoded, Content: script}})
+	}
+	return m, nil
+}
+
+// languageViews encodes the cost models of the given Plutus languages as
+// hashed into the script data hash. PlutusV1 keeps its historical quirks:
+// the key is a serialized byte string and the costs are an
+// indefinite-length list wrapped in a byte string.
+func languageViews(languages []ScriptLanguage, models map[ScriptLanguage][]int64) ([]byte, error) {
+	var views cborMap
+	for _, language := range languages {
+		costs := models[language]
+		switch language {
+		case PlutusV1:
+			list := []byte{0x9f}
+			for _, cost := range costs {
+				encoded, err := cbor.Encode(cost)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, encoded...)
+			}
+			list = append(list, 0xff)
+			views = append(views, cborEntry{[]byte{0x00}, list})
+		case PlutusV2, PlutusV3:
+			if costs == nil {
+				costs = []int64{}
+			}
+			views = append(views, cborEntry{uint64(language - 1), costs})
+		}
+	}
+	if views == nil {
+		views = cborMap{}
+	}
+	return cbor.Encode(views)
+}
+
+// scriptDataHash returns the hash binding redeemers, witness datums and
+// the cost models of the languages in use.
+func scriptDataHash(redeemers, datums, views []byte) []byte {
+	data := slices.Concat(redeemers, datums, views)
+	return common.Blake2b256Hash(data).Bytes()
+}
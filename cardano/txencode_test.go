@@ -0,0 +1,22 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestLanguageViews(t *testing.T) {
+	models := map[ScriptLanguage][]int64{
+		PlutusV1: {1, -2},
+		PlutusV3: {3},
+	}
+	views, err := languageViews([]ScriptLanguage{PlutusV1, PlutusV3}, models)
+	if err != nil {
+		t.Fatalf("languageViews returned error: %v", err)
+	}
+	// {2: [3], h'00': h'9f0121ff'}
+	want := "a2" + "02" + "8103" + "4100" + "44" + "9f0121ff"
+	if got := hex.EncodeToString(views); got != want {
+		t.Fatalf("languageViews = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,330 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	gosync "sync"
+
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultMultiplexerDepth = 32
+
+// TxHandler receives a transaction event dispatched by a [TxMultiplexer].
+// Returning an error stops [TxMultiplexer.Run].
+type TxHandler func(ctx context.Context, tx *watch.AnyChainTx) error
+
+// TxMultiplexerOption configures a [TxMultiplexer] during
+// [Client.NewTxMultiplexer].
+type TxMultiplexerOption func(*TxMultiplexer)
+
+// WithMultiplexerIntersect sets the points the first upstream stream starts
+// from, newest first. Without it the stream starts at the server's
+// default (normally the tip).
+func WithMultiplexerIntersect(points ...*watch.BlockRef) TxMultiplexerOption {
+	return func(m *TxMultiplexer) {
+		m.points = cloneWatchRefs(points)
+	}
+}
+
+// WithMultiplexerEmulation serves the upstream stream from Sync.FollowTip
+// (see [Client.EmulateWatchTransaction]) instead of trying Watch.WatchTx
+// first.
+func WithMultiplexerEmulation() TxMultiplexerOption {
+	return func(m *TxMultiplexer) {
+		m.emulate = true
+	}
+}
+
+// TxSubscription is a predicate registered on a [TxMultiplexer] together
+// with its handlers.
+type TxSubscription struct {
+	predicate *watch.TxPredicate
+	onApply   TxHandler
+	onUndo    TxHandler
+}
+
+// TxMultiplexer shares one upstream WatchTx stream among many
+// subscriptions. The upstream predicate is the any_of of every
+// subscription's predicate; each received transaction is then matched
+// locally with [MatchTx] and handed to the subscriptions it satisfies.
+//
+// Subscriptions may be added or removed while [TxMultiplexer.Run] is
+// active. Each change restarts the upstream stream from the last fully
+// processed block, so new subscriptions see events from that point on;
+// transactions already dispatched for a partially processed block are not
+// delivered twice. Handlers run on the Run goroutine, one at a time.
+type TxMultiplexer struct {
+	client  *Client
+	emulate bool
+
+	mu      gosync.Mutex
+	subs    map[*TxSubscription]struct{}
+	changed bool
+	restart context.CancelFunc
+	wake    chan struct{}
+
+	// points holds recently completed blocks, newest first.
+	points []*watch.BlockRef
+	// current is the block whose transactions are being dispatched;
+	// delivered holds the hashes of those already dispatched.
+	current   *watch.BlockRef
+	delivered map[string]struct{}
+}
+
+// NewTxMultiplexer returns a [TxMultiplexer] with no subscriptions.
+func (c *Client) NewTxMultiplexer(options ...TxMultiplexerOption) *TxMultiplexer {
+	m := &TxMultiplexer{
+		client: c,
+		subs:   make(map[*TxSubscription]struct{}),
+		wake:   make(chan struct{}, 1),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Subscribe registers predicate with its Apply and Undo handlers; either
+// handler may be nil. A nil predicate matches every transaction.
+func (m *TxMultiplexer) Subscribe(
+	predicate *watch.TxPredicate,
+	onApply TxHandler,
+	onUndo TxHandler,
+) *TxSubscription {
+	sub := &TxSubscription{
+		predicate: predicate,
+		onApply:   onApply,
+		onUndo:    onUndo,
+	}
+	m.mu.Lock()
+	m.subs[sub] = struct{}{}
+	m.markChangedLocked()
+	m.mu.Unlock()
+	return sub
+}
+
+// Unsubscribe removes sub. Its handlers are not called after Unsubscribe
+// returns unless a dispatch to them is already in progress.
+func (m *TxMultiplexer) Unsubscribe(sub *TxSubscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.subs[sub]; !ok {
+		return
+	}
+	delete(m.subs, sub)
+	m.markChangedLocked()
+}
+
+func (m *TxMultiplexer) markChangedLocked() {
+	m.changed = true
+	if m.restart != nil {
+		m.restart()
+	}
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run streams transactions and dispatches them until ctx is done, a
+// handler fails, or the upstream stream ends or fails. While there are no
+// subscriptions it waits without holding a stream open.
+func (m *TxMultiplexer) Run(ctx context.Context) error {
+	for {
+		predicate, ok := m.upstreamPredicate()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-m.wake:
+				continue
+			}
+		}
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		m.mu.Lock()
+		m.restart = cancel
+		m.changed = false
+		intersect := cloneWatchRefs(m.points)
+		m.mu.Unlock()
+
+		restarted, err := m.stream(streamCtx, predicate, intersect)
+		m.mu.Lock()
+		m.restart = nil
+		restarted = restarted || m.changed
+		m.mu.Unlock()
+		cancel()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !restarted {
+			return err
+		}
+	}
+}
+
+// stream runs one upstream stream. It reports restarted when the stream
+// was cut short by a subscription change.
+func (m *TxMultiplexer) stream(
+	ctx context.Context,
+	predicate *watch.TxPredicate,
+	intersect []*watch.BlockRef,
+) (bool, error) {
+	req := &watch.WatchTxRequest{Predicate: predicate, Intersect: intersect}
+	var stream TxStream
+	var err error
+	if m.emulate {
+		stream, err = m.client.EmulateWatchTransactionWithContext(ctx, req)
+	} else {
+		stream, err = m.client.WatchTransactionWithFallbackWithContext(ctx, req)
+	}
+	if err != nil {
+		return false, err
+	}
+	defer stream.Close()
+
+	for stream.Receive() {
+		if err := m.dispatch(ctx, stream.Msg()); err != nil {
+			return false, err
+		}
+		m.mu.Lock()
+		changed := m.changed
+		m.mu.Unlock()
+		if changed {
+			return true, nil
+		}
+	}
+	return ctx.Err() != nil, stream.Err()
+}
+
+func (m *TxMultiplexer) upstreamPredicate() (*watch.TxPredicate, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.subs) == 0 {
+		return nil, false
+	}
+	merged := &watch.TxPredicate{}
+	for sub := range m.subs {
+		if sub.predicate == nil {
+			return nil, true
+		}
+		merged.AnyOf = append(merged.AnyOf, sub.predicate)
+	}
+	if len(merged.AnyOf) == 1 {
+		return merged.AnyOf[0], true
+	}
+	return merged, true
+}
+
+func (m *TxMultiplexer) subscriptions() []*TxSubscription {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	subs := make([]*TxSubscription, 0, len(m.subs))
+	for sub := range m.subs {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+func (m *TxMultiplexer) dispatch(ctx context.Context, resp *watch.WatchTxResponse) error {
+	switch action := resp.GetAction().(type) {
+	case *watch.WatchTxResponse_Apply:
+		ref := watchRefOf(action.Apply.GetBlock())
+		if ref == nil {
+			return errors.New("applied transaction has no block header")
+		}
+		m.enterBlock(ref)
+		key := hex.EncodeToString(action.Apply.GetCardano().GetHash())
+		if _, done := m.delivered[key]; done {
+			return nil
+		}
+		m.delivered[key] = struct{}{}
+		for _, sub := range m.subscriptions() {
+			if sub.onApply == nil || !MatchTx(sub.predicate, action.Apply.GetCardano()) {
+				continue
+			}
+			if err := sub.onApply(ctx, action.Apply); err != nil {
+				return fmt.Errorf("apply handler failed: %w", err)
+			}
+		}
+	case *watch.WatchTxResponse_Undo:
+		for _, sub := range m.subscriptions() {
+			if sub.onUndo == nil || !MatchTx(sub.predicate, action.Undo.GetCardano()) {
+				continue
+			}
+			if err := sub.onUndo(ctx, action.Undo); err != nil {
+				return fmt.Errorf("undo handler failed: %w", err)
+			}
+		}
+		if ref := watchRefOf(action.Undo.GetBlock()); ref != nil {
+			m.rollBack(ref.GetSlot())
+		}
+	case *watch.WatchTxResponse_Idle:
+		m.enterBlock(action.Idle)
+		m.completeCurrent()
+	}
+	return nil
+}
+
+// enterBlock records that events now belong to ref, completing the
+// previous block if ref is a different one.
+func (m *TxMultiplexer) enterBlock(ref *watch.BlockRef) {
+	if m.current != nil && proto.Equal(m.current, ref) {
+		return
+	}
+	m.completeCurrent()
+	m.current = proto.Clone(ref).(*watch.BlockRef)
+	m.delivered = make(map[string]struct{})
+}
+
+func (m *TxMultiplexer) completeCurrent() {
+	if m.current == nil {
+		return
+	}
+	m.mu.Lock()
+	m.points = append([]*watch.BlockRef{m.current}, m.points...)
+	if len(m.points) > defaultMultiplexerDepth {
+		m.points = m.points[:defaultMultiplexerDepth]
+	}
+	m.mu.Unlock()
+	m.current = nil
+	m.delivered = nil
+}
+
+// rollBack forgets every block at or after slot.
+func (m *TxMultiplexer) rollBack(slot uint64) {
+	if m.current != nil && m.current.GetSlot() >= slot {
+		m.current = nil
+		m.delivered = nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for len(m.points) > 0 && m.points[0].GetSlot() >= slot {
+		m.points = m.points[1:]
+	}
+}
+
+func watchRefOf(block *watch.AnyChainBlock) *watch.BlockRef {
+	header := block.GetCardano().GetHeader()
+	if header == nil {
+		return nil
+	}
+	return &watch.BlockRef{
+		Slot:   header.GetSlot(),
+		Hash:   header.GetHash(),
+		Height: header.GetHeight(),
+	}
+}
+
+func cloneWatchRefs(refs []*watch.BlockRef) []*watch.BlockRef {
+	cloned := make([]*watch.BlockRef, 0, len(refs))
+	for _, ref := range refs {
+		cloned = append(cloned, proto.Clone(ref).(*watch.BlockRef))
+	}
+	return cloned
+}
@@ -0,0 +1,169 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	gosync "sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch/watchconnect"
+)
+
+func TestTxMultiplexerDispatchesToMatchingSubscribers(t *testing.T) {
+	_, first := testAddress(t, 0x11, 0x22)
+	_, second := testAddress(t, 0x33, 0x44)
+	payFirst := watchTx(10, 0x01, first)
+	paySecond := watchTx(10, 0x02, second)
+
+	fakeWatch := &scriptedWatchHandler{scripts: [][]*watch.WatchTxResponse{{
+		{Action: &watch.WatchTxResponse_Apply{Apply: payFirst}},
+		{Action: &watch.WatchTxResponse_Apply{Apply: paySecond}},
+		{Action: &watch.WatchTxResponse_Undo{Undo: payFirst}},
+	}}}
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return watchconnect.NewWatchServiceHandler(fakeWatch)
+	})
+
+	mux := client.NewTxMultiplexer()
+	var firstEvents, secondEvents []string
+	record := func(events *[]string, kind string) TxHandler {
+		return func(_ context.Context, tx *watch.AnyChainTx) error {
+			*events = append(*events, kind+":"+hex.EncodeToString(tx.GetCardano().GetHash()))
+			return nil
+		}
+	}
+	mux.Subscribe(
+		producesAddress(t, first),
+		record(&firstEvents, "apply"),
+		record(&firstEvents, "undo"),
+	)
+	mux.Subscribe(producesAddress(t, second), record(&secondEvents, "apply"), nil)
+
+	if err := mux.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if want := []string{"apply:01", "undo:01"}; !slicesEqual(firstEvents, want) {
+		t.Fatalf("first subscriber events = %v, want %v", firstEvents, want)
+	}
+	if want := []string{"apply:02"}; !slicesEqual(secondEvents, want) {
+		t.Fatalf("second subscriber events = %v, want %v", secondEvents, want)
+	}
+	if got := len(fakeWatch.requests()[0].GetPredicate().GetAnyOf()); got != 2 {
+		t.Fatalf("upstream any_of has %d predicates, want 2", got)
+	}
+}
+
+func TestTxMultiplexerResubscribesFromLastCompletedBlock(t *testing.T) {
+	_, first := testAddress(t, 0x11, 0x22)
+	_, second := testAddress(t, 0x33, 0x44)
+	idle := &watch.BlockRef{Slot: 5, Hash: []byte{5}, Height: 5}
+	payFirst := watchTx(6, 0x01, first)
+	paySecond := watchTx(6, 0x02, second)
+
+	fakeWatch := &scriptedWatchHandler{scripts: [][]*watch.WatchTxResponse{
+		{
+			{Action: &watch.WatchTxResponse_Idle{Idle: idle}},
+			{Action: &watch.WatchTxResponse_Apply{Apply: payFirst}},
+		},
+		{
+			{Action: &watch.WatchTxResponse_Apply{Apply: payFirst}},
+			{Action: &watch.WatchTxResponse_Apply{Apply: paySecond}},
+		},
+	}, holdOpen: 1}
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return watchconnect.NewWatchServiceHandler(fakeWatch)
+	})
+
+	mux := client.NewTxMultiplexer()
+	var firstCount, secondCount int
+	mux.Subscribe(producesAddress(t, first), func(context.Context, *watch.AnyChainTx) error {
+		firstCount++
+		mux.Subscribe(producesAddress(t, second), func(context.Context, *watch.AnyChainTx) error {
+			secondCount++
+			return nil
+		}, nil)
+		return nil
+	}, nil)
+
+	if err := mux.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	if firstCount != 1 || secondCount != 1 {
+		t.Fatalf("handler calls = (%d, %d), want (1, 1)", firstCount, secondCount)
+	}
+	requests := fakeWatch.requests()
+	if len(requests) != 2 {
+		t.Fatalf("upstream streams = %d, want 2", len(requests))
+	}
+	if got := requests[1].GetIntersect(); len(got) != 1 || got[0].GetSlot() != 5 {
+		t.Fatalf("resubscribe intersect = %v, want slot 5", got)
+	}
+}
+
+func watchTx(slot uint64, hashByte byte, address []byte) *watch.AnyChainTx {
+	return &watch.AnyChainTx{
+		Chain: &watch.AnyChainTx_Cardano{Cardano: &chaincardano.Tx{
+			Hash:    []byte{hashByte},
+			Outputs: []*chaincardano.TxOutput{{Address: address}},
+		}},
+		Block: &watch.AnyChainBlock{Chain: &watch.AnyChainBlock_Cardano{
+			Cardano: chainBlock(slot).GetCardano(),
+		}},
+	}
+}
+
+func producesAddress(t *testing.T, address []byte) *watch.TxPredicate {
+	t.Helper()
+	predicate, err := NewTxPredicate().
+		Produces(NewOutputPattern().Address(hex.EncodeToString(address))).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	return predicate
+}
+
+// scriptedWatchHandler sends scripts[i] on the i-th WatchTx call. The
+// holdOpen-th call (1-based) stays open after its script until the client
+// goes away.
+type scriptedWatchHandler struct {
+	watchconnect.UnimplementedWatchServiceHandler
+	scripts  [][]*watch.WatchTxResponse
+	holdOpen int
+
+	mu   gosync.Mutex
+	reqs []*watch.WatchTxRequest
+}
+
+func (s *scriptedWatchHandler) WatchTx(
+	ctx context.Context,
+	req *connect.Request[watch.WatchTxRequest],
+	stream *connect.ServerStream[watch.WatchTxResponse],
+) error {
+	s.mu.Lock()
+	s.reqs = append(s.reqs, req.Msg)
+	call := len(s.reqs)
+	s.mu.Unlock()
+	if call > len(s.scripts) {
+		return nil
+	}
+	for _, resp := range s.scripts[call-1] {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	if call == s.holdOpen {
+		<-ctx.Done()
+	}
+	return nil
+}
+
+func (s *scriptedWatchHandler) requests() []*watch.WatchTxRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*watch.WatchTxRequest(nil), s.reqs...)
+}
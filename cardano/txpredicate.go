@@ -0,0 +1,284 @@
+package cardano
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+	"google.golang.org/protobuf/proto"
+)
+
+// TxPredicateBuilder builds a [watch.TxPredicate] for Watch.WatchTx.
+//
+// Pattern setters on one builder narrow a single Cardano tx pattern, so all
+// of them must match. [TxPredicateBuilder.AllOf], [TxPredicateBuilder.AnyOf]
+// and [TxPredicateBuilder.Not] attach nested predicates. Inputs are validated
+// as they are set and the first error is returned by
+// [TxPredicateBuilder.Build].
+//
+//	predicate, err := cardano.NewTxPredicate().
+//	    Produces(cardano.NewOutputPattern().Address("addr1...")).
+//	    Not(cardano.NewTxPredicate().MintsPolicy("29d2...70c6")).
+//	    Build()
+//	stream, err := client.WatchTransactionWithContext(ctx, &watch.WatchTxRequest{
+//	    Predicate: predicate,
+//	})
+//
+// The v1beta Cardano tx pattern has no metadata field, so transactions
+// cannot be selected by metadata label on the server; filter on
+// Tx.Auxiliary.Metadata after receiving them instead.
+type TxPredicateBuilder struct {
+	pattern *chaincardano.TxPattern
+	address *OutputPattern
+	not     []*TxPredicateBuilder
+	allOf   []*TxPredicateBuilder
+	anyOf   []*TxPredicateBuilder
+	err     error
+}
+
+// NewTxPredicate returns an empty [TxPredicateBuilder].
+func NewTxPredicate() *TxPredicateBuilder {
+	return &TxPredicateBuilder{}
+}
+
+// Consumes matches transactions spending an output that matches pattern.
+func (b *TxPredicateBuilder) Consumes(pattern *OutputPattern) *TxPredicateBuilder {
+	b.setOutput("consumes", pattern, func(p *chaincardano.TxPattern) **chaincardano.TxOutputPattern {
+		return &p.Consumes
+	})
+	return b
+}
+
+// Produces matches transactions creating an output that matches pattern.
+func (b *TxPredicateBuilder) Produces(pattern *OutputPattern) *TxPredicateBuilder {
+	b.setOutput("produces", pattern, func(p *chaincardano.TxPattern) **chaincardano.TxOutputPattern {
+		return &p.Produces
+	})
+	return b
+}
+
+// HasAddress matches transactions touching an exact address anywhere
+// (inputs, outputs, collateral, ...). See [OutputPattern.Address] for the
+// accepted formats.
+func (b *TxPredicateBuilder) HasAddress(address string) *TxPredicateBuilder {
+	b.addressPattern().Address(address)
+	return b
+}
+
+// HasPaymentPart matches transactions touching an address with the given
+// payment credential. See [OutputPattern.PaymentPart] for the accepted
+// formats.
+func (b *TxPredicateBuilder) HasPaymentPart(credential string) *TxPredicateBuilder {
+	b.addressPattern().PaymentPart(credential)
+	return b
+}
+
+// HasDelegationPart matches transactions touching an address with the given
+// stake credential. See [OutputPattern.DelegationPart] for the accepted
+// formats.
+func (b *TxPredicateBuilder) HasDelegationPart(credential string) *TxPredicateBuilder {
+	b.addressPattern().DelegationPart(credential)
+	return b
+}
+
+// MovesPolicy matches transactions moving any asset of a hex policy ID.
+func (b *TxPredicateBuilder) MovesPolicy(policyID string) *TxPredicateBuilder {
+	return b.MovesAsset(policyID, "")
+}
+
+// MovesAsset matches transactions moving an asset. Both arguments are hex;
+// either may be empty to match on the other alone.
+func (b *TxPredicateBuilder) MovesAsset(policyID, assetName string) *TxPredicateBuilder {
+	b.setAsset("moves_asset", policyID, assetName, func(p *chaincardano.TxPattern) **chaincardano.AssetPattern {
+		return &p.MovesAsset
+	})
+	return b
+}
+
+// MintsPolicy matches transactions minting or burning any asset of a hex
+// policy ID.
+func (b *TxPredicateBuilder) MintsPolicy(policyID string) *TxPredicateBuilder {
+	return b.MintsAsset(policyID, "")
+}
+
+// MintsAsset matches transactions minting or burning an asset. Both
+// arguments are hex; either may be empty to match on the other alone.
+func (b *TxPredicateBuilder) MintsAsset(policyID, assetName string) *TxPredicateBuilder {
+	b.setAsset("mints_asset", policyID, assetName, func(p *chaincardano.TxPattern) **chaincardano.AssetPattern {
+		return &p.MintsAsset
+	})
+	return b
+}
+
+// AllOf requires every one of predicates to match.
+func (b *TxPredicateBuilder) AllOf(predicates ...*TxPredicateBuilder) *TxPredicateBuilder {
+	b.allOf = append(b.allOf, predicates...)
+	return b
+}
+
+// AnyOf requires at least one of predicates to match.
+func (b *TxPredicateBuilder) AnyOf(predicates ...*TxPredicateBuilder) *TxPredicateBuilder {
+	b.anyOf = append(b.anyOf, predicates...)
+	return b
+}
+
+// Not requires none of predicates to match.
+func (b *TxPredicateBuilder) Not(predicates ...*TxPredicateBuilder) *TxPredicateBuilder {
+	b.not = append(b.not, predicates...)
+	return b
+}
+
+// Build returns the predicate, or the first validation error found in this
+// builder or any nested one. An empty builder is an error, since it would
+// match every transaction.
+func (b *TxPredicateBuilder) Build() (*watch.TxPredicate, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	predicate := &watch.TxPredicate{}
+	var pattern *chaincardano.TxPattern
+	if b.pattern != nil {
+		pattern = proto.Clone(b.pattern).(*chaincardano.TxPattern)
+	}
+	if b.address != nil {
+		address, err := b.address.Build()
+		if err != nil {
+			return nil, err
+		}
+		if pattern == nil {
+			pattern = &chaincardano.TxPattern{}
+		}
+		pattern.HasAddress = address.GetAddress()
+	}
+	if pattern != nil {
+		predicate.Match = &watch.AnyChainTxPattern{
+			Chain: &watch.AnyChainTxPattern_Cardano{Cardano: pattern},
+		}
+	}
+	var err error
+	if predicate.Not, err = buildTxPredicates(b.not); err != nil {
+		return nil, err
+	}
+	if predicate.AllOf, err = buildTxPredicates(b.allOf); err != nil {
+		return nil, err
+	}
+	if predicate.AnyOf, err = buildTxPredicates(b.anyOf); err != nil {
+		return nil, err
+	}
+	if predicate.Match == nil && len(predicate.Not) == 0 &&
+		len(predicate.AllOf) == 0 && len(predicate.AnyOf) == 0 {
+		return nil, errors.New("tx predicate has no patterns set")
+	}
+	return predicate, nil
+}
+
+func buildTxPredicates(builders []*TxPredicateBuilder) ([]*watch.TxPredicate, error) {
+	if len(builders) == 0 {
+		return nil, nil
+	}
+	predicates := make([]*watch.TxPredicate, 0, len(builders))
+	for _, builder := range builders {
+		predicate, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+	return predicates, nil
+}
+
+func (b *TxPredicateBuilder) txPattern() *chaincardano.TxPattern {
+	if b.pattern == nil {
+		b.pattern = &chaincardano.TxPattern{}
+	}
+	return b.pattern
+}
+
+func (b *TxPredicateBuilder) addressPattern() *OutputPattern {
+	if b.address == nil {
+		b.address = NewOutputPattern()
+	}
+	return b.address
+}
+
+func (b *TxPredicateBuilder) setOutput(
+	field string,
+	pattern *OutputPattern,
+	target func(*chaincardano.TxPattern) **chaincardano.TxOutputPattern,
+) {
+	if b.err != nil {
+		return
+	}
+	built, err := pattern.Build()
+	if err != nil {
+		b.err = err
+		return
+	}
+	slot := target(b.txPattern())
+	if *slot != nil {
+		b.err = errPatternFieldSet(field)
+		return
+	}
+	*slot = built
+}
+
+func (b *TxPredicateBuilder) setAsset(
+	field, policyID, assetName string,
+	target func(*chaincardano.TxPattern) **chaincardano.AssetPattern,
+) {
+	if b.err != nil {
+		return
+	}
+	built, err := newAssetPattern(policyID, assetName)
+	if err != nil {
+		b.err = err
+		return
+	}
+	slot := target(b.txPattern())
+	if *slot != nil {
+		b.err = errPatternFieldSet(field)
+		return
+	}
+	*slot = built
+}
+
+// WatchTransactionsByPredicate calls
+// [Client.WatchTransactionsByPredicateWithContext] with a background context.
+func (c *Client) WatchTransactionsByPredicate(
+	predicate *TxPredicateBuilder,
+	blockHashStr string,
+	blockIndex int64,
+) (*connect.ServerStreamForClient[watch.WatchTxResponse], error) {
+	return c.WatchTransactionsByPredicateWithContext(
+		context.Background(),
+		predicate,
+		blockHashStr,
+		blockIndex,
+	)
+}
+
+// WatchTransactionsByPredicateWithContext opens a Watch.WatchTx stream of
+// transactions matching predicate, starting from the given intersect point
+// (see [Client.WatchTransaction] for the hash and slot conventions). The
+// caller must close the returned stream.
+func (c *Client) WatchTransactionsByPredicateWithContext(
+	ctx context.Context,
+	predicate *TxPredicateBuilder,
+	blockHashStr string,
+	blockIndex int64,
+) (*connect.ServerStreamForClient[watch.WatchTxResponse], error) {
+	built, err := predicate.Build()
+	if err != nil {
+		return nil, err
+	}
+	intersect, err := watchIntersect(blockHashStr, blockIndex)
+	if err != nil {
+		return nil, err
+	}
+	return c.WatchTransactionWithContext(ctx, &watch.WatchTxRequest{
+		Predicate: built,
+		Intersect: intersect,
+	})
+}
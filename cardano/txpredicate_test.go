@@ -0,0 +1,157 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch/watchconnect"
+)
+
+// testAddress returns a mainnet base address built from repeated payment
+// and stake hash bytes, as bech32 and raw bytes.
+func testAddress(t *testing.T, payment, stake byte) (string, []byte) {
+	t.Helper()
+	addr, err := common.NewAddressFromParts(
+		common.AddressTypeKeyKey,
+		common.AddressNetworkMainnet,
+		bytes.Repeat([]byte{payment}, credentialHashSize),
+		bytes.Repeat([]byte{stake}, credentialHashSize),
+	)
+	if err != nil {
+		t.Fatalf("NewAddressFromParts returned error: %v", err)
+	}
+	raw, err := addr.Bytes()
+	if err != nil {
+		t.Fatalf("Address.Bytes returned error: %v", err)
+	}
+	return addr.String(), raw
+}
+
+func TestTxPredicateBuilderComposesPatterns(t *testing.T) {
+	address, rawAddress := testAddress(t, 0x11, 0x22)
+	policy := strings.Repeat("ab", credentialHashSize)
+
+	predicate, err := NewTxPredicate().
+		Produces(NewOutputPattern().Address(address)).
+		HasPaymentPart(address).
+		HasDelegationPart(strings.Repeat("22", credentialHashSize)).
+		AnyOf(
+			NewTxPredicate().MintsPolicy(policy),
+			NewTxPredicate().MovesAsset(policy, "746f6b656e"),
+		).
+		Not(NewTxPredicate().Consumes(NewOutputPattern().Policy(policy))).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+
+	pattern := predicate.GetMatch().GetCardano()
+	if got := pattern.GetProduces().GetAddress().GetExactAddress(); !bytes.Equal(got, rawAddress) {
+		t.Fatalf("produces address = %x, want %x", got, rawAddress)
+	}
+	hasAddress := pattern.GetHasAddress()
+	if got := hasAddress.GetPaymentPart(); !bytes.Equal(got, bytes.Repeat([]byte{0x11}, 28)) {
+		t.Fatalf("payment part = %x", got)
+	}
+	if got := hasAddress.GetDelegationPart(); !bytes.Equal(got, bytes.Repeat([]byte{0x22}, 28)) {
+		t.Fatalf("delegation part = %x", got)
+	}
+	if got := len(predicate.GetAnyOf()); got != 2 {
+		t.Fatalf("len(any_of) = %d, want 2", got)
+	}
+	mints := predicate.GetAnyOf()[0].GetMatch().GetCardano().GetMintsAsset()
+	if len(mints.GetPolicyId()) != credentialHashSize || mints.GetAssetName() != nil {
+		t.Fatalf("mints_asset = %v, want policy only", mints)
+	}
+	moves := predicate.GetAnyOf()[1].GetMatch().GetCardano().GetMovesAsset()
+	if string(moves.GetAssetName()) != "token" {
+		t.Fatalf("moves_asset name = %q, want %q", moves.GetAssetName(), "token")
+	}
+	if predicate.GetNot()[0].GetMatch().GetCardano().GetConsumes().GetAsset() == nil {
+		t.Fatal("not[0] consumes asset pattern is nil")
+	}
+}
+
+func TestTxPredicateBuilderRejectsInvalidInputs(t *testing.T) {
+	policy := strings.Repeat("ab", credentialHashSize)
+
+	tests := []struct {
+		name    string
+		builder *TxPredicateBuilder
+	}{
+		{name: "empty", builder: NewTxPredicate()},
+		{name: "bad address", builder: NewTxPredicate().HasAddress("addr1invalid")},
+		{name: "non-hex policy", builder: NewTxPredicate().MintsPolicy("zz")},
+		{name: "short policy", builder: NewTxPredicate().MintsPolicy("abcd")},
+		{name: "long asset name", builder: NewTxPredicate().MovesAsset(policy, strings.Repeat("00", 33))},
+		{name: "short credential", builder: NewTxPredicate().HasPaymentPart("abcd")},
+		{name: "empty output pattern", builder: NewTxPredicate().Produces(NewOutputPattern())},
+		{
+			name:    "output asset set twice",
+			builder: NewTxPredicate().Produces(NewOutputPattern().Policy(policy).Asset(policy, "00")),
+		},
+		{
+			name:    "output address set twice",
+			builder: NewTxPredicate().Produces(NewOutputPattern().PaymentPart(policy).PaymentPart(policy)),
+		},
+		{
+			name:    "field set twice",
+			builder: NewTxPredicate().MintsPolicy(policy).MintsPolicy(policy),
+		},
+		{
+			name:    "nested error",
+			builder: NewTxPredicate().AllOf(NewTxPredicate().MovesPolicy("zz")),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.builder.Build(); err == nil {
+				t.Fatal("Build returned nil error")
+			}
+		})
+	}
+}
+
+func TestWatchTransactionsByPredicateSendsPredicate(t *testing.T) {
+	fakeWatch := &recordingWatchHandler{}
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return watchconnect.NewWatchServiceHandler(fakeWatch)
+	})
+
+	stream, err := client.WatchTransactionsByPredicate(
+		NewTxPredicate().MintsPolicy(strings.Repeat("ab", credentialHashSize)),
+		"",
+		-1,
+	)
+	if err != nil {
+		t.Fatalf("WatchTransactionsByPredicate returned error: %v", err)
+	}
+	for stream.Receive() {
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if fakeWatch.req.GetPredicate().GetMatch().GetCardano().GetMintsAsset() == nil {
+		t.Fatalf("server predicate = %v, want mints_asset pattern", fakeWatch.req.GetPredicate())
+	}
+}
+
+type recordingWatchHandler struct {
+	watchconnect.UnimplementedWatchServiceHandler
+	req *watch.WatchTxRequest
+}
+
+func (w *recordingWatchHandler) WatchTx(
+	_ context.Context,
+	req *connect.Request[watch.WatchTxRequest],
+	_ *connect.ServerStream[watch.WatchTxResponse],
+) error {
+	w.req = req.Msg
+	return nil
+}
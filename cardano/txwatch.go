@@ -0,0 +1,314 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+	sdk "github.com/utxorpc/go-sdk"
+)
+
+// emulatedRollbackDepth is how many blocks below the newest one an emulated
+// WatchTx stream remembers for Reset events: the Cardano mainnet security
+// parameter k.
+const emulatedRollbackDepth uint64 = 2160
+
+// TxStream is a stream of WatchTx events. It is satisfied by the
+// *connect.ServerStreamForClient returned by [Client.WatchTransaction] and by
+// the emulated streams of [Client.EmulateWatchTransaction] and
+// [Client.WatchTransactionWithFallback], so a receive loop works unchanged
+// with any of them:
+//
+//	for stream.Receive() {
+//	    msg := stream.Msg()
+//	}
+//	if err := stream.Err(); err != nil { ... }
+type TxStream interface {
+	Receive() bool
+	Msg() *watch.WatchTxResponse
+	Err() error
+	Close() error
+}
+
+var _ TxStream = (*connect.ServerStreamForClient[watch.WatchTxResponse])(nil)
+
+// EmulateWatchTransaction calls [Client.EmulateWatchTransactionWithContext]
+// with a background context.
+func (c *Client) EmulateWatchTransaction(
+	watchReq *watch.WatchTxRequest,
+) (TxStream, error) {
+	return c.EmulateWatchTransactionWithContext(context.Background(), watchReq)
+}
+
+// EmulateWatchTransactionWithContext serves a WatchTx request from
+// Sync.FollowTip, for servers that do not implement the Watch service.
+//
+// FollowTip is opened from the request's intersect and watchReq.Predicate
+// is applied to every transaction with [MatchTx]. Each applied block yields
+// an Apply event per matching transaction, or a single Idle event if none
+// match; each undone block yields an Undo event per matching transaction,
+// in reverse order. A Reset event yields the Undo events of the matching
+// blocks applied after the reset point, newest first; a Reset deeper than
+// the last 2160 blocks ends the stream with [sdk.ErrRollbackTooDeep]. The
+// request's field mask is not applied. The caller must close the returned
+// stream.
+func (c *Client) EmulateWatchTransactionWithContext(
+	ctx context.Context,
+	watchReq *watch.WatchTxRequest,
+) (TxStream, error) {
+	intersect := make([]*sync.BlockRef, 0, len(watchReq.GetIntersect()))
+	for _, ref := range watchReq.GetIntersect() {
+		intersect = append(intersect, &sync.BlockRef{
+			Slot:   ref.GetSlot(),
+			Hash:   ref.GetHash(),
+			Height: ref.GetHeight(),
+		})
+	}
+	req := connect.NewRequest(&sync.FollowTipRequest{Intersect: intersect})
+	upstream, err := c.UtxorpcClient.FollowTipWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &emulatedTxStream{
+		upstream:  upstream,
+		predicate: watchReq.GetPredicate(),
+	}, nil
+}
+
+// WatchTransactionWithFallback calls
+// [Client.WatchTransactionWithFallbackWithContext] with a background
+// context.
+func (c *Client) WatchTransactionWithFallback(
+	watchReq *watch.WatchTxRequest,
+) (TxStream, error) {
+	return c.WatchTransactionWithFallbackWithContext(context.Background(), watchReq)
+}
+
+// WatchTransactionWithFallbackWithContext opens Watch.WatchTx and, if the
+// server answers Unimplemented, transparently switches to
+// [Client.EmulateWatchTransactionWithContext] with the same request. The
+// server's answer is only known once the first message or error arrives,
+// so the switch happens inside the first Receive call. The caller must
+// close the returned stream.
+func (c *Client) WatchTransactionWithFallbackWithContext(
+	ctx context.Context,
+	watchReq *watch.WatchTxRequest,
+) (TxStream, error) {
+	native, err := c.WatchTransactionWithContext(ctx, watchReq)
+	if connect.CodeOf(err) == connect.CodeUnimplemented {
+		return c.EmulateWatchTransactionWithContext(ctx, watchReq)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fallbackTxStream{
+		ctx:     ctx,
+		client:  c,
+		req:     watchReq,
+		current: native,
+	}, nil
+}
+
+type emulatedTxStream struct {
+	upstream  *connect.ServerStreamForClient[sync.FollowTipResponse]
+	predicate *watch.TxPredicate
+	pending   []*watch.WatchTxResponse
+	msg       *watch.WatchTxResponse
+	err       error
+	// matched holds the recent applied blocks with matching transactions,
+	// oldest first, so a Reset can undo them.
+	matched []*sync.AnyChainBlock
+	// forgotten is the slot of the newest block dropped from matched.
+	forgotten uint64
+}
+
+func (s *emulatedTxStream) Receive() bool {
+	for len(s.pending) == 0 {
+		if s.err != nil || !s.upstream.Receive() {
+			s.msg = nil
+			return false
+		}
+		s.pending, s.err = s.events(s.upstream.Msg())
+	}
+	s.msg, s.pending = s.pending[0], s.pending[1:]
+	return true
+}
+
+func (s *emulatedTxStream) Msg() *watch.WatchTxResponse {
+	return s.msg
+}
+
+func (s *emulatedTxStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.upstream.Err()
+}
+
+func (s *emulatedTxStream) Close() error {
+	return s.upstream.Close()
+}
+
+// events translates one FollowTip event into the WatchTx events a server
+// would send, keeping track of the blocks a later Reset must undo.
+func (s *emulatedTxStream) events(
+	resp *sync.FollowTipResponse,
+) ([]*watch.WatchTxResponse, error) {
+	switch action := resp.GetAction().(type) {
+	case *sync.FollowTipResponse_Apply:
+		events := watchEvents(s.predicate, action.Apply, false)
+		if len(events) > 0 && events[0].GetIdle() == nil {
+			s.remember(action.Apply)
+		}
+		return events, nil
+	case *sync.FollowTipResponse_Undo:
+		hash := sdk.BlockRefOf(action.Undo).GetHash()
+		s.matched = slices.DeleteFunc(s.matched, func(block *sync.AnyChainBlock) bool {
+			return bytes.Equal(sdk.BlockRefOf(block).GetHash(), hash)
+		})
+		return watchEvents(s.predicate, action.Undo, true), nil
+	case *sync.FollowTipResponse_Reset_:
+		return s.reset(action.Reset_)
+	default:
+		return nil, nil
+	}
+}
+
+// remember adds block to matched and drops the blocks it buries beyond
+// emulatedRollbackDepth.
+func (s *emulatedTxStream) remember(block *sync.AnyChainBlock) {
+	s.matched = append(s.matched, block)
+	height := sdk.BlockRefOf(block).GetHeight()
+	for len(s.matched) > 0 &&
+		sdk.BlockRefOf(s.matched[0]).GetHeight()+emulatedRollbackDepth <= height {
+		s.forgotten = sdk.BlockRefOf(s.matched[0]).GetSlot()
+		s.matched = s.matched[1:]
+	}
+}
+
+// reset undoes the matched blocks applied after point, newest first.
+func (s *emulatedTxStream) reset(
+	point *sync.BlockRef,
+) ([]*watch.WatchTxResponse, error) {
+	if point.GetSlot() < s.forgotten {
+		return nil, fmt.Errorf(
+			"%w: reset to slot %d",
+			sdk.ErrRollbackTooDeep,
+			point.GetSlot(),
+		)
+	}
+	var events []*watch.WatchTxResponse
+	for len(s.matched) > 0 {
+		last := s.matched[len(s.matched)-1]
+		if sdk.BlockRefOf(last).GetSlot() <= point.GetSlot() {
+			break
+		}
+		events = append(events, watchEvents(s.predicate, last, true)...)
+		s.matched = s.matched[:len(s.matched)-1]
+	}
+	return events, nil
+}
+
+// watchEvents translates an applied or undone block into the WatchTx events
+// a server would send for predicate.
+func watchEvents(
+	predicate *watch.TxPredicate,
+	block *sync.AnyChainBlock,
+	undo bool,
+) []*watch.WatchTxResponse {
+	cardanoBlock := block.GetCardano()
+	if cardanoBlock == nil {
+		return nil
+	}
+	watchBlock := &watch.AnyChainBlock{
+		NativeBytes: block.GetNativeBytes(),
+		Chain:       &watch.AnyChainBlock_Cardano{Cardano: cardanoBlock},
+	}
+
+	var events []*watch.WatchTxResponse
+	for _, tx := range cardanoBlock.GetBody().GetTx() {
+		if !MatchTx(predicate, tx) {
+			continue
+		}
+		matched := &watch.AnyChainTx{
+			Chain: &watch.AnyChainTx_Cardano{Cardano: tx},
+			Block: watchBlock,
+		}
+		if undo {
+			events = append(events, &watch.WatchTxResponse{
+				Action: &watch.WatchTxResponse_Undo{Undo: matched},
+			})
+		} else {
+			events = append(events, &watch.WatchTxResponse{
+				Action: &watch.WatchTxResponse_Apply{Apply: matched},
+			})
+		}
+	}
+	if undo {
+		slices.Reverse(events)
+		return events
+	}
+	if len(events) == 0 {
+		header := cardanoBlock.GetHeader()
+		events = append(events, &watch.WatchTxResponse{
+			Action: &watch.WatchTxResponse_Idle{Idle: &watch.BlockRef{
+				Slot:   header.GetSlot(),
+				Hash:   header.GetHash(),
+				Height: header.GetHeight(),
+			}},
+		})
+	}
+	return events
+}
+
+type fallbackTxStream struct {
+	ctx     context.Context
+	client  *Client
+	req     *watch.WatchTxRequest
+	current TxStream
+	started bool
+	err     error
+}
+
+func (s *fallbackTxStream) Receive() bool {
+	if s.err != nil {
+		return false
+	}
+	if s.started {
+		return s.current.Receive()
+	}
+	s.started = true
+	if s.current.Receive() {
+		return true
+	}
+	if connect.CodeOf(s.current.Err()) != connect.CodeUnimplemented {
+		return false
+	}
+	_ = s.current.Close()
+	emulated, err := s.client.EmulateWatchTransactionWithContext(s.ctx, s.req)
+	if err != nil {
+		s.err = err
+		return false
+	}
+	s.current = emulated
+	return s.current.Receive()
+}
+
+func (s *fallbackTxStream) Msg() *watch.WatchTxResponse {
+	return s.current.Msg()
+}
+
+func (s *fallbackTxStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.current.Err()
+}
+
+func (s *fallbackTxStream) Close() error {
+	return s.current.Close()
+}
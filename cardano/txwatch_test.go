@@ -0,0 +1,222 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync/syncconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/watch/watchconnect"
+	sdk "github.com/utxorpc/go-sdk"
+)
+
+func TestWatchTransactionWithFallbackEmulatesOverFollowTip(t *testing.T) {
+	_, watched := testAddress(t, 0x11, 0x22)
+	_, other := testAddress(t, 0x33, 0x44)
+	pays := func(address []byte, fee uint64) *chaincardano.Tx {
+		return &chaincardano.Tx{
+			Outputs: []*chaincardano.TxOutput{{Address: address}},
+			Fee:     &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: int64(fee)}},
+		}
+	}
+	matching := txBlock(10, pays(other, 1), pays(watched, 2), pays(watched, 3))
+
+	fakeSync := &scriptedFollowTipHandler{
+		responses: []*sync.FollowTipResponse{
+			{Action: &sync.FollowTipResponse_Apply{Apply: matching}},
+			{Action: &sync.FollowTipResponse_Apply{Apply: txBlock(11, pays(other, 4))}},
+			{Action: &sync.FollowTipResponse_Undo{Undo: matching}},
+		},
+	}
+	client := newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return syncconnect.NewSyncServiceHandler(fakeSync)
+		},
+		func() (string, http.Handler) {
+			return watchconnect.NewWatchServiceHandler(
+				watchconnect.UnimplementedWatchServiceHandler{},
+			)
+		},
+	)
+
+	predicate, err := NewTxPredicate().
+		Produces(NewOutputPattern().PaymentPart(hex.EncodeToString(hash28(0x11)))).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	stream, err := client.WatchTransactionWithFallback(&watch.WatchTxRequest{
+		Predicate: predicate,
+		Intersect: []*watch.BlockRef{{Slot: 9, Hash: []byte{9}}},
+	})
+	if err != nil {
+		t.Fatalf("WatchTransactionWithFallback returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var got []string
+	for stream.Receive() {
+		switch action := stream.Msg().GetAction().(type) {
+		case *watch.WatchTxResponse_Apply:
+			got = append(got, "apply:"+feeOf(action.Apply))
+		case *watch.WatchTxResponse_Undo:
+			got = append(got, "undo:"+feeOf(action.Undo))
+		case *watch.WatchTxResponse_Idle:
+			got = append(got, "idle")
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	want := []string{"apply:2", "apply:3", "idle", "undo:3", "undo:2"}
+	if !slicesEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	if len(fakeSync.intersect) != 1 || fakeSync.intersect[0].GetSlot() != 9 {
+		t.Fatalf("FollowTip intersect = %v, want slot 9", fakeSync.intersect)
+	}
+}
+
+func TestEmulateWatchTransactionUndoesBlocksOnReset(t *testing.T) {
+	_, watched := testAddress(t, 0x11, 0x22)
+	pays := func(fee int64) *chaincardano.Tx {
+		return &chaincardano.Tx{
+			Outputs: []*chaincardano.TxOutput{{Address: watched}},
+			Fee:     &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: fee}},
+		}
+	}
+	reset := func(slot uint64) *sync.FollowTipResponse {
+		return &sync.FollowTipResponse{
+			Action: &sync.FollowTipResponse_Reset_{Reset_: &sync.BlockRef{Slot: slot}},
+		}
+	}
+	fakeSync := &scriptedFollowTipHandler{
+		responses: []*sync.FollowTipResponse{
+			reset(9),
+			{Action: &sync.FollowTipResponse_Apply{Apply: txBlock(10, pays(1))}},
+			{Action: &sync.FollowTipResponse_Apply{Apply: txBlock(11, pays(2), pays(3))}},
+			{Action: &sync.FollowTipResponse_Apply{Apply: txBlock(12)}},
+			reset(10),
+			{Action: &sync.FollowTipResponse_Apply{Apply: txBlock(13, pays(4))}},
+		},
+	}
+	client := newTestServerClient(t, func() (string, http.Handler) {
+		return syncconnect.NewSyncServiceHandler(fakeSync)
+	})
+
+	predicate, err := NewTxPredicate().
+		Produces(NewOutputPattern().PaymentPart(hex.EncodeToString(hash28(0x11)))).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	stream, err := client.EmulateWatchTransaction(&watch.WatchTxRequest{Predicate: predicate})
+	if err != nil {
+		t.Fatalf("EmulateWatchTransaction returned error: %v", err)
+	}
+	defer stream.Close()
+
+	var got []string
+	for stream.Receive() {
+		switch action := stream.Msg().GetAction().(type) {
+		case *watch.WatchTxResponse_Apply:
+			got = append(got, "apply:"+feeOf(action.Apply))
+		case *watch.WatchTxResponse_Undo:
+			got = append(got, "undo:"+feeOf(action.Undo))
+		case *watch.WatchTxResponse_Idle:
+			got = append(got, "idle")
+		}
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	want := []string{"apply:1", "apply:2", "apply:3", "idle", "undo:3", "undo:2", "apply:4"}
+	if !slicesEqual(got, want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+
+	// A Reset below the blocks the stream still remembers cannot be undone.
+	deep := &emulatedTxStream{predicate: predicate}
+	for _, slot := range []uint64{1, 1 + emulatedRollbackDepth} {
+		apply := &sync.FollowTipResponse{
+			Action: &sync.FollowTipResponse_Apply{Apply: txBlock(slot, pays(5))},
+		}
+		if _, err := deep.events(apply); err != nil {
+			t.Fatalf("events returned error: %v", err)
+		}
+	}
+	if _, err := deep.events(reset(0)); !errors.Is(err, sdk.ErrRollbackTooDeep) {
+		t.Fatalf("deep reset error = %v, want ErrRollbackTooDeep", err)
+	}
+}
+
+func TestWatchTransactionWithFallbackPrefersNativeWatch(t *testing.T) {
+	fakeWatch := &recordingWatchHandler{}
+	fakeSync := &scriptedFollowTipHandler{}
+	client := newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return syncconnect.NewSyncServiceHandler(fakeSync)
+		},
+		func() (string, http.Handler) {
+			return watchconnect.NewWatchServiceHandler(fakeWatch)
+		},
+	)
+
+	stream, err := client.WatchTransactionWithFallback(&watch.WatchTxRequest{})
+	if err != nil {
+		t.Fatalf("WatchTransactionWithFallback returned error: %v", err)
+	}
+	defer stream.Close()
+	for stream.Receive() {
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("stream error: %v", err)
+	}
+	if fakeWatch.req == nil {
+		t.Fatal("native WatchTx was not called")
+	}
+	if fakeSync.called {
+		t.Fatal("FollowTip was called although WatchTx is implemented")
+	}
+}
+
+func txBlock(slot uint64, txs ...*chaincardano.Tx) *sync.AnyChainBlock {
+	block := chainBlock(slot)
+	block.GetCardano().Body = &chaincardano.BlockBody{Tx: txs}
+	return block
+}
+
+func feeOf(tx *watch.AnyChainTx) string {
+	return strconv.FormatInt(tx.GetCardano().GetFee().GetInt(), 10)
+}
+
+type scriptedFollowTipHandler struct {
+	syncconnect.UnimplementedSyncServiceHandler
+	responses []*sync.FollowTipResponse
+	intersect []*sync.BlockRef
+	called    bool
+}
+
+func (s *scriptedFollowTipHandler) FollowTip(
+	_ context.Context,
+	req *connect.Request[sync.FollowTipRequest],
+	stream *connect.ServerStream[sync.FollowTipResponse],
+) error {
+	s.called = true
+	s.intersect = req.Msg.GetIntersect()
+	for _, resp := range s.responses {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
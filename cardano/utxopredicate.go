@@ -0,0 +1,213 @@
+package cardano
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// UtxoPredicateBuilder builds a [query.UtxoPredicate] for Query.SearchUtxos.
+//
+// Pattern setters on one builder narrow a single Cardano output pattern, so
+// all of them must match. [UtxoPredicateBuilder.AllOf],
+// [UtxoPredicateBuilder.AnyOf] and [UtxoPredicateBuilder.Not] attach nested
+// predicates. Inputs are validated as they are set and the first error is
+// returned by [UtxoPredicateBuilder.Build].
+//
+// UTxOs at either of two addresses holding a policy, but not one of its
+// assets:
+//
+//	predicate := cardano.NewUtxoPredicate().
+//	    AnyOf(
+//	        cardano.NewUtxoPredicate().Address("addr1..."),
+//	        cardano.NewUtxoPredicate().Address("addr1..."),
+//	    ).
+//	    Policy(policyHex).
+//	    Not(cardano.NewUtxoPredicate().Asset(policyHex, assetNameHex))
+//	resp, err := client.GetUtxosByPredicate(predicate)
+type UtxoPredicateBuilder struct {
+	pattern *OutputPattern
+	not     []*UtxoPredicateBuilder
+	allOf   []*UtxoPredicateBuilder
+	anyOf   []*UtxoPredicateBuilder
+}
+
+// NewUtxoPredicate returns an empty [UtxoPredicateBuilder].
+func NewUtxoPredicate() *UtxoPredicateBuilder {
+	return &UtxoPredicateBuilder{}
+}
+
+// Address matches UTxOs locked at an exact address. See
+// [OutputPattern.Address] for the accepted formats.
+func (b *UtxoPredicateBuilder) Address(address string) *UtxoPredicateBuilder {
+	b.outputPattern().Address(address)
+	return b
+}
+
+// PaymentPart matches UTxOs whose address has the given payment
+// credential. See [OutputPattern.PaymentPart] for the accepted formats.
+func (b *UtxoPredicateBuilder) PaymentPart(credential string) *UtxoPredicateBuilder {
+	b.outputPattern().PaymentPart(credential)
+	return b
+}
+
+// DelegationPart matches UTxOs whose address has the given stake
+// credential. See [OutputPattern.DelegationPart] for the accepted formats.
+func (b *UtxoPredicateBuilder) DelegationPart(credential string) *UtxoPredicateBuilder {
+	b.outputPattern().DelegationPart(credential)
+	return b
+}
+
+// Policy matches UTxOs holding any asset of a hex policy ID.
+func (b *UtxoPredicateBuilder) Policy(policyID string) *UtxoPredicateBuilder {
+	b.outputPattern().Policy(policyID)
+	return b
+}
+
+// Asset matches UTxOs holding a specific asset. Both arguments are hex;
+// either may be empty to match on the other alone.
+func (b *UtxoPredicateBuilder) Asset(policyID, assetName string) *UtxoPredicateBuilder {
+	b.outputPattern().Asset(policyID, assetName)
+	return b
+}
+
+// AllOf requires every one of predicates to match.
+func (b *UtxoPredicateBuilder) AllOf(predicates ...*UtxoPredicateBuilder) *UtxoPredicateBuilder {
+	b.allOf = append(b.allOf, predicates...)
+	return b
+}
+
+// AnyOf requires at least one of predicates to match.
+func (b *UtxoPredicateBuilder) AnyOf(predicates ...*UtxoPredicateBuilder) *UtxoPredicateBuilder {
+	b.anyOf = append(b.anyOf, predicates...)
+	return b
+}
+
+// Not requires none of predicates to match.
+func (b *UtxoPredicateBuilder) Not(predicates ...*UtxoPredicateBuilder) *UtxoPredicateBuilder {
+	b.not = append(b.not, predicates...)
+	return b
+}
+
+// Build returns the predicate, or the first validation error found in this
+// builder or any nested one. An empty builder is an error, since it would
+// match every UTxO.
+func (b *UtxoPredicateBuilder) Build() (*query.UtxoPredicate, error) {
+	predicate := &query.UtxoPredicate{}
+	if b.pattern != nil {
+		pattern, err := b.pattern.Build()
+		if err != nil {
+			return nil, err
+		}
+		predicate.Match = &query.AnyUtxoPattern{
+			UtxoPattern: &query.AnyUtxoPattern_Cardano{Cardano: pattern},
+		}
+	}
+	var err error
+	if predicate.Not, err = buildUtxoPredicates(b.not); err != nil {
+		return nil, err
+	}
+	if predicate.AllOf, err = buildUtxoPredicates(b.allOf); err != nil {
+		return nil, err
+	}
+	if predicate.AnyOf, err = buildUtxoPredicates(b.anyOf); err != nil {
+		return nil, err
+	}
+	if predicate.Match == nil && len(predicate.Not) == 0 &&
+		len(predicate.AllOf) == 0 && len(predicate.AnyOf) == 0 {
+		return nil, errors.New("utxo predicate has no patterns set")
+	}
+	return predicate, nil
+}
+
+func buildUtxoPredicates(builders []*UtxoPredicateBuilder) ([]*query.UtxoPredicate, error) {
+	if len(builders) == 0 {
+		return nil, nil
+	}
+	predicates := make([]*query.UtxoPredicate, 0, len(builders))
+	for _, builder := range builders {
+		predicate, err := builder.Build()
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+	return predicates, nil
+}
+
+func (b *UtxoPredicateBuilder) outputPattern() *OutputPattern {
+	if b.pattern == nil {
+		b.pattern = NewOutputPattern()
+	}
+	return b.pattern
+}
+
+// GetUtxosByPredicate calls [Client.GetUtxosByPredicateWithContext] with a
+// background context.
+func (c *Client) GetUtxosByPredicate(
+	predicate *UtxoPredicateBuilder,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	return c.GetUtxosByPredicateWithContext(
+		context.Background(),
+		predicate,
+		options...,
+	)
+}
+
+// GetUtxosByPredicateWithContext searches for UTxOs matching predicate via
+// Query.SearchUtxos. By default, the first page of up to 100 results is
+// returned. Use SearchOption values to configure the page, or
+// [Client.GetUtxosByPredicatePages] to iterate all pages.
+func (c *Client) GetUtxosByPredicateWithContext(
+	ctx context.Context,
+	predicate *UtxoPredicateBuilder,
+	options ...SearchOption,
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	built, err := predicate.Build()
+	if err != nil {
+		return nil, err
+	}
+	req := connect.NewRequest(newSearchRequest(built, options...))
+	return c.UtxorpcClient.SearchUtxosWithContext(ctx, req)
+}
+
+// GetUtxosByPredicatePages calls [Client.GetUtxosByPredicatePagesWithContext]
+// with a background context.
+func (c *Client) GetUtxosByPredicatePages(
+	predicate *UtxoPredicateBuilder,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	return c.GetUtxosByPredicatePagesWithContext(
+		context.Background(),
+		predicate,
+		options...,
+	)
+}
+
+// GetUtxosByPredicatePagesWithContext lazily returns all SearchUtxos pages
+// for predicate. An invalid predicate is yielded as the sequence's first
+// error without making an RPC.
+func (c *Client) GetUtxosByPredicatePagesWithContext(
+	ctx context.Context,
+	predicate *UtxoPredicateBuilder,
+	options ...SearchOption,
+) iter.Seq2[*connect.Response[query.SearchUtxosResponse], error] {
+	built, err := predicate.Build()
+	if err != nil {
+		return func(yield func(
+			*connect.Response[query.SearchUtxosResponse],
+			error,
+		) bool,
+		) {
+			yield(nil, err)
+		}
+	}
+	return c.UtxorpcClient.SearchUtxosPagesWithContext(
+		ctx,
+		connect.NewRequest(newSearchRequest(built, options...)),
+	)
+}
@@ -0,0 +1,81 @@
+package cardano
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	sdk "github.com/utxorpc/go-sdk"
+)
+
+func TestGetUtxosByPredicateBuildsSearchRequest(t *testing.T) {
+	fakeQuery := &recordingQueryClient{}
+	client := NewClient(sdk.WithBaseUrl("http://example.test"))
+	client.UtxorpcClient.Query = fakeQuery
+
+	first, firstRaw := testAddress(t, 0x01, 0x02)
+	second, _ := testAddress(t, 0x03, 0x04)
+	policy := strings.Repeat("cd", credentialHashSize)
+
+	_, err := client.GetUtxosByPredicateWithContext(
+		context.Background(),
+		NewUtxoPredicate().
+			AnyOf(
+				NewUtxoPredicate().Address(first),
+				NewUtxoPredicate().Address(second),
+			).
+			Policy(policy).
+			Not(NewUtxoPredicate().Asset(policy, "6e6f")).
+			AllOf(NewUtxoPredicate().DelegationPart(first)),
+		WithSearchMaxItems(10),
+	)
+	if err != nil {
+		t.Fatalf("GetUtxosByPredicateWithContext returned error: %v", err)
+	}
+	req := fakeQuery.searchUtxosReq.Msg
+	if got := req.GetMaxItems(); got != 10 {
+		t.Fatalf("max_items = %d, want 10", got)
+	}
+
+	predicate := req.GetPredicate()
+	if got := predicate.GetMatch().GetCardano().GetAsset().GetPolicyId(); len(got) != credentialHashSize {
+		t.Fatalf("match policy_id = %x", got)
+	}
+	anyOf := predicate.GetAnyOf()
+	if len(anyOf) != 2 {
+		t.Fatalf("len(any_of) = %d, want 2", len(anyOf))
+	}
+	if got := anyOf[0].GetMatch().GetCardano().GetAddress().GetExactAddress(); !bytes.Equal(got, firstRaw) {
+		t.Fatalf("any_of[0] address = %x, want %x", got, firstRaw)
+	}
+	if got := string(predicate.GetNot()[0].GetMatch().GetCardano().GetAsset().GetAssetName()); got != "no" {
+		t.Fatalf("not[0] asset name = %q, want %q", got, "no")
+	}
+	delegation := predicate.GetAllOf()[0].GetMatch().GetCardano().GetAddress().GetDelegationPart()
+	if !bytes.Equal(delegation, bytes.Repeat([]byte{0x02}, credentialHashSize)) {
+		t.Fatalf("all_of[0] delegation part = %x", delegation)
+	}
+}
+
+func TestGetUtxosByPredicatePagesYieldsBuildError(t *testing.T) {
+	fakeQuery := &recordingQueryClient{}
+	client := NewClient(sdk.WithBaseUrl("http://example.test"))
+	client.UtxorpcClient.Query = fakeQuery
+
+	var calls int
+	for _, err := range client.GetUtxosByPredicatePages(
+		NewUtxoPredicate().PaymentPart("not-a-credential"),
+	) {
+		calls++
+		if err == nil {
+			t.Fatal("page error = nil, want validation error")
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("yielded %d items, want 1", calls)
+	}
+	if fakeQuery.searchUtxosReq != nil {
+		t.Fatal("SearchUtxos was called for an invalid predicate")
+	}
+}
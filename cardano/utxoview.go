@@ -0,0 +1,117 @@
+package cardano
+
+import (
+	"context"
+
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// SpendableUtxo is an output that is unspent once pending mempool
+// transactions are taken into account.
+type SpendableUtxo struct {
+	Ref    *query.TxoRef
+	Output *chaincardano.TxOutput
+	// Pending marks an output created by a transaction still in the
+	// mempool. It can be spent by chaining onto that transaction, but
+	// disappears if the transaction is dropped.
+	Pending bool
+}
+
+// Spendable adjusts confirmed UTxOs for the current mempool view: entries
+// spent by a pending transaction are removed, and outputs of pending
+// transactions that satisfy pred and are not themselves spent by another
+// pending transaction are added with Pending set. A nil pred adds every
+// such output. Confirmed entries keep their order and come first; pending
+// outputs follow, oldest transaction first.
+//
+// Pending outputs are only known for transactions the observer could
+// decode (see [MempoolTx]).
+func (o *MempoolObserver) Spendable(
+	confirmed []*query.AnyUtxoData,
+	pred *query.UtxoPredicate,
+) []SpendableUtxo {
+	pending := o.Transactions()
+
+	o.mu.Lock()
+	spent := func(txHash []byte, index uint32) bool {
+		_, ok := o.spends[inputKey(txHash, index)]
+		return ok
+	}
+	var utxos []SpendableUtxo
+	seen := make(map[string]bool)
+	for _, item := range confirmed {
+		ref := item.GetTxoRef()
+		if spent(ref.GetHash(), ref.GetIndex()) {
+			continue
+		}
+		seen[inputKey(ref.GetHash(), ref.GetIndex())] = true
+		utxos = append(utxos, SpendableUtxo{Ref: ref, Output: item.GetCardano()})
+	}
+	for _, tx := range pending {
+		for i, output := range tx.Tx.GetOutputs() {
+			// #nosec G115 -- output counts are far below 2^32
+			index := uint32(i)
+			key := inputKey(tx.Hash, index)
+			if seen[key] || spent(tx.Hash, index) {
+				continue
+			}
+			if pred != nil && !MatchUtxo(pred, output) {
+				continue
+			}
+			utxos = append(utxos, SpendableUtxo{
+				Ref:     &query.TxoRef{Hash: tx.Hash, Index: index},
+				Output:  output,
+				Pending: true,
+			})
+		}
+	}
+	o.mu.Unlock()
+	return utxos
+}
+
+// GetSpendableUtxos calls [Client.GetSpendableUtxosWithContext] with a
+// background context.
+func (c *Client) GetSpendableUtxos(
+	predicate *UtxoPredicateBuilder,
+	options ...SearchOption,
+) ([]SpendableUtxo, error) {
+	return c.GetSpendableUtxosWithContext(
+		context.Background(),
+		predicate,
+		options...,
+	)
+}
+
+// GetSpendableUtxosWithContext returns the UTxOs matching predicate in the
+// mempool-adjusted state: every page of Query.SearchUtxos, minus outputs
+// spent by transactions in a Submit.ReadMempool snapshot, plus matching
+// outputs those transactions create. See [MempoolObserver.Spendable].
+//
+// The mempool is read before the search, so a transaction confirming in
+// between shows up once, as confirmed. To reuse a live mempool view across
+// calls, search with [Client.GetUtxosByPredicatePages] and pass the results
+// to [MempoolObserver.Spendable] instead.
+func (c *Client) GetSpendableUtxosWithContext(
+	ctx context.Context,
+	predicate *UtxoPredicateBuilder,
+	options ...SearchOption,
+) ([]SpendableUtxo, error) {
+	built, err := predicate.Build()
+	if err != nil {
+		return nil, err
+	}
+	observer := c.NewMempoolObserver()
+	if err := observer.Resync(ctx); err != nil {
+		return nil, err
+	}
+
+	var confirmed []*query.AnyUtxoData
+	for resp, err := range c.GetUtxosByPredicatePagesWithContext(ctx, predicate, options...) {
+		if err != nil {
+			return nil, err
+		}
+		confirmed = append(confirmed, resp.Msg.GetItems()...)
+	}
+	return observer.Spendable(confirmed, built), nil
+}
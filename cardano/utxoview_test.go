@@ -0,0 +1,88 @@
+package cardano
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/submit/submitconnect"
+)
+
+func TestGetSpendableUtxosAdjustsForMempool(t *testing.T) {
+	address, ours := testAddress(t, 1, 2)
+	_, theirs := testAddress(t, 3, 4)
+
+	// bb spends confirmed 11…#0 and pays us at #0 and someone else at #1;
+	// cc spends bb#2, so bb#2 is not spendable either.
+	spending := parsedMempoolTx("bb", 100, "11")
+	spending.GetCardano().Outputs = []*chaincardano.TxOutput{
+		{Address: ours}, {Address: theirs}, {Address: ours},
+	}
+	chained := parsedMempoolTx("cc", 100, "00")
+	chained.GetCardano().Inputs = []*chaincardano.TxInput{
+		{TxHash: mustDecodeHex(t, "bb"), OutputIndex: 2},
+	}
+	fakeSubmit := &mempoolSubmitHandler{
+		items: []*submit.TxInMempool{spending, chained},
+	}
+	fakeQuery := &searchQueryHandler{items: []*query.AnyUtxoData{
+		confirmedUtxo(t, "11", ours),
+		confirmedUtxo(t, "33", ours),
+	}}
+	client := newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return submitconnect.NewSubmitServiceHandler(fakeSubmit)
+		},
+		func() (string, http.Handler) {
+			return queryconnect.NewQueryServiceHandler(fakeQuery)
+		},
+	)
+
+	utxos, err := client.GetSpendableUtxos(NewUtxoPredicate().Address(address))
+	if err != nil {
+		t.Fatalf("GetSpendableUtxos returned error: %v", err)
+	}
+	var got []string
+	for _, utxo := range utxos {
+		got = append(got, fmt.Sprintf(
+			"%x#%d pending=%v",
+			utxo.Ref.GetHash()[:1],
+			utxo.Ref.GetIndex(),
+			utxo.Pending,
+		))
+	}
+	want := []string{"33#0 pending=false", "bb#0 pending=true"}
+	if !slicesEqual(got, want) {
+		t.Fatalf("spendable = %v, want %v", got, want)
+	}
+}
+
+func confirmedUtxo(t *testing.T, hash string, address []byte) *query.AnyUtxoData {
+	t.Helper()
+	return &query.AnyUtxoData{
+		TxoRef: &query.TxoRef{Hash: mustDecodeHex(t, strings.Repeat(hash, 32))},
+		ParsedState: &query.AnyUtxoData_Cardano{
+			Cardano: &chaincardano.TxOutput{Address: address},
+		},
+	}
+}
+
+type searchQueryHandler struct {
+	queryconnect.UnimplementedQueryServiceHandler
+	items []*query.AnyUtxoData
+}
+
+func (q *searchQueryHandler) SearchUtxos(
+	context.Context,
+	*connect.Request[query.SearchUtxosRequest],
+) (*connect.Response[query.SearchUtxosResponse], error) {
+	return connect.NewResponse(&query.SearchUtxosResponse{Items: q.items}), nil
+}
@@ -0,0 +1,325 @@
+package cardano
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/blinklabs-io/gouroboros/cbor"
+	"github.com/blinklabs-io/gouroboros/ledger"
+	"github.com/blinklabs-io/gouroboros/ledger/common"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// minUtxoOverhead is the per-output overhead in bytes added to the output
+// size when computing the minimum ADA of an output (CIP-55).
+const minUtxoOverhead = 160
+
+// ValidationRule identifies the ledger rule a [Violation] breaks.
+type ValidationRule string
+
+// Rules checked by [ValidateTransaction].
+const (
+	RuleMaxTxSize        ValidationRule = "max-tx-size"
+	RuleMinFee           ValidationRule = "min-fee"
+	RuleMinUtxo          ValidationRule = "min-utxo"
+	RuleMaxValueSize     ValidationRule = "max-value-size"
+	RuleCollateral       ValidationRule = "collateral"
+	RuleCollateralInputs ValidationRule = "collateral-inputs"
+	RuleValidityInterval ValidationRule = "validity-interval"
+	RuleUnknownInput     ValidationRule = "unknown-input"
+)
+
+// InputKind identifies the input list a [Violation] index refers to.
+type InputKind string
+
+// Input lists of a transaction.
+const (
+	InputSpent      InputKind = "input"
+	InputReference  InputKind = "reference-input"
+	InputCollateral InputKind = "collateral"
+)
+
+// Violation is a ledger rule a transaction breaks.
+type Violation struct {
+	Rule ValidationRule
+	// Index is the position of the output or input concerned, or -1 when
+	// the rule applies to the whole transaction.
+	Index int
+	// Input names the list Index refers to for input rules such as
+	// [RuleUnknownInput], and is empty otherwise.
+	Input InputKind
+	// Actual and Limit are the offending value and the bound it crosses,
+	// in the rule's unit (bytes, lovelace, slots or inputs).
+	Actual  uint64
+	Limit   uint64
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Index < 0 {
+		return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%s[%d]: %s", v.Rule, v.Index, v.Message)
+}
+
+// ValidationContext is the ledger state a transaction is checked against.
+type ValidationContext struct {
+	Params *chaincardano.PParams
+	// Utxos are the resolved inputs, collateral inputs and reference
+	// inputs. Inputs missing here are reported as [RuleUnknownInput].
+	Utxos []*query.AnyUtxoData
+	// TipSlot is the slot the validity interval is checked against.
+	TipSlot uint64
+}
+
+// ValidateTransaction checks a transaction locally against the rules a
+// node would most commonly reject it for: maximum transaction size, minimum
+// fee (see [CalculateTxFee]), minimum ADA and maximum value size per output,
+// collateral amount and input count for transactions with redeemers, the
+// validity interval against the tip, and unknown inputs. txCbor is hex.
+//
+// It returns every violation found, or nil if there are none. The error is
+// non-nil only if the transaction cannot be decoded. Passing the checks does
+// not guarantee acceptance; scripts in particular are not run (see
+// [Client.EvaluateTransactionReport]).
+func ValidateTransaction(txCbor string, vc ValidationContext) ([]Violation, error) {
+	raw, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	tx, err := decodeLedgerTransaction(raw)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{tx: tx, params: vc.Params, utxos: make(map[string]*query.AnyUtxoData)}
+	for _, item := range vc.Utxos {
+		ref := item.GetTxoRef()
+		v.utxos[inputKey(ref.GetHash(), ref.GetIndex())] = item
+	}
+
+	// #nosec G115 -- lengths are never negative
+	v.checkSize(uint64(len(raw)))
+	v.checkInputs()
+	v.checkFee(txCbor)
+	v.checkOutputs()
+	v.checkCollateral()
+	v.checkValidity(vc.TipSlot)
+	return v.violations, nil
+}
+
+// ValidateTransaction calls [Client.ValidateTransactionWithContext] with a
+// background context.
+func (c *Client) ValidateTransaction(txCbor string) ([]Violation, error) {
+	return c.ValidateTransactionWithContext(context.Background(), txCbor)
+}
+
+// ValidateTransactionWithContext runs [ValidateTransaction] against the
+// current protocol parameters, the tip slot and the transaction's inputs
+// resolved via Query.ReadUtxos.
+func (c *Client) ValidateTransactionWithContext(
+	ctx context.Context,
+	txCbor string,
+) ([]Violation, error) {
+	raw, err := hex.DecodeString(txCbor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	tx, err := decodeLedgerTransaction(raw)
+	if err != nil {
+		return nil, err
+	}
+	params, err := c.cardanoParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := c.GetTipWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tip: %w", err)
+	}
+
+	var refs []*query.TxoRef
+	for _, input := range slices.Concat(tx.Inputs(), tx.Collateral(), tx.ReferenceInputs()) {
+		refs = append(refs, &query.TxoRef{Hash: input.Id().Bytes(), Index: input.Index()})
+	}
+	var utxos []*query.AnyUtxoData
+	if len(refs) > 0 {
+		resp, err := c.GetUtxosByRefsWithContext(ctx, refs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transaction inputs: %w", err)
+		}
+		utxos = resp.Msg.GetItems()
+	}
+	return ValidateTransaction(txCbor, ValidationContext{
+		Params:  params,
+		Utxos:   utxos,
+		TipSlot: tip.Msg.GetTip().GetSlot(),
+	})
+}
+
+type validator struct {
+	tx         ledger.Transaction
+	params     *chaincardano.PParams
+	utxos      map[string]*query.AnyUtxoData
+	violations []Violation
+}
+
+func (v *validator) add(rule ValidationRule, index int, actual, limit uint64, format string, args ...any) {
+	v.violations = append(v.violations, Violation{
+		Rule:    rule,
+		Index:   index,
+		Actual:  actual,
+		Limit:   limit,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) addUnknownInput(kind InputKind, index int, input common.TransactionInput) {
+	v.violations = append(v.violations, Violation{
+		Rule:    RuleUnknownInput,
+		Index:   index,
+		Input:   kind,
+		Message: fmt.Sprintf("%s %s is not an unspent output", kind, input),
+	})
+}
+
+func (v *validator) utxo(input common.TransactionInput) (*query.AnyUtxoData, bool) {
+	item, ok := v.utxos[inputKey(input.Id().Bytes(), input.Index())]
+	return item, ok
+}
+
+func (v *validator) checkSize(size uint64) {
+	if limit := v.params.GetMaxTxSize(); limit > 0 && size > limit {
+		v.add(RuleMaxTxSize, -1, size, limit, "transaction is %d bytes, limit %d", size, limit)
+	}
+}
+
+func (v *validator) checkInputs() {
+	for i, input := range v.tx.Inputs() {
+		if _, ok := v.utxo(input); !ok {
+			v.addUnknownInput(InputSpent, i, input)
+		}
+	}
+	for i, input := range v.tx.ReferenceInputs() {
+		if _, ok := v.utxo(input); !ok {
+			v.addUnknownInput(InputReference, i, input)
+		}
+	}
+}
+
+func (v *validator) checkFee(txCbor string) {
+	var refScriptSize uint64
+	for _, input := range slices.Concat(v.tx.Inputs(), v.tx.ReferenceInputs()) {
+		if item, ok := v.utxo(input); ok {
+			// An unsizable script leaves the fee underestimated, which
+			// only makes this check more lenient.
+			size, _ := utxoRefScriptSize(item)
+			refScriptSize += size
+		}
+	}
+	minFee, err := CalculateTxFee(txCbor, refScriptSize, v.params)
+	if err != nil {
+		return
+	}
+	if fee := v.tx.Fee().Uint64(); fee < minFee.Total {
+		v.add(RuleMinFee, -1, fee, minFee.Total, "fee %d is below the minimum %d", fee, minFee.Total)
+	}
+}
+
+func (v *validator) checkOutputs() {
+	perByte := bigIntUint64(v.params.GetCoinsPerUtxoByte())
+	maxValueSize := v.params.GetMaxValueSize()
+	for i, output := range v.tx.Outputs() {
+		coin := output.Amount().Uint64()
+		outputCbor := output.Cbor()
+		if len(outputCbor) == 0 {
+			outputCbor, _ = cbor.Encode(output)
+		}
+		if minCoin := minUtxoCoin(perByte, len(outputCbor)); coin < minCoin {
+			v.add(RuleMinUtxo, i, coin, minCoin, "output holds %d lovelace, minimum %d", coin, minCoin)
+		}
+		if maxValueSize == 0 {
+			continue
+		}
+		var value any = coin
+		if assets := output.Assets(); assets != nil && len(assets.Policies()) > 0 {
+			value = []any{coin, assets}
+		}
+		valueCbor, err := cbor.Encode(value)
+		if err != nil {
+			continue
+		}
+		// #nosec G115 -- lengths are never negative
+		if size := uint64(len(valueCbor)); size > maxValueSize {
+			v.add(RuleMaxValueSize, i, size, maxValueSize, "output value is %d bytes, limit %d", size, maxValueSize)
+		}
+	}
+}
+
+func (v *validator) checkCollateral() {
+	redeemers := v.tx.Witnesses().Redeemers()
+	if redeemers == nil {
+		return
+	}
+	hasRedeemers := false
+	for range redeemers.Iter() {
+		hasRedeemers = true
+		break
+	}
+	if !hasRedeemers {
+		return
+	}
+
+	collateral := v.tx.Collateral()
+	// #nosec G115 -- lengths are never negative
+	count := uint64(len(collateral))
+	if limit := v.params.GetMaxCollateralInputs(); count == 0 || (limit > 0 && count > limit) {
+		v.add(RuleCollateralInputs, -1, count, limit, "%d collateral inputs, want 1 to %d", count, limit)
+	}
+
+	var balance uint64
+	for i, input := range collateral {
+		item, ok := v.utxo(input)
+		if !ok {
+			v.addUnknownInput(InputCollateral, i, input)
+			return
+		}
+		balance += utxoLovelace(item)
+	}
+	if ret := v.tx.CollateralReturn(); ret != nil {
+		balance -= min(balance, ret.Amount().Uint64())
+	}
+	// required = ceil(fee × percentage / 100)
+	required := (v.tx.Fee().Uint64()*v.params.GetCollateralPercentage() + 99) / 100
+	if balance < required {
+		v.add(RuleCollateral, -1, balance, required, "collateral %d lovelace is below the required %d", balance, required)
+	}
+}
+
+func (v *validator) checkValidity(tipSlot uint64) {
+	if start := v.tx.ValidityIntervalStart(); start > 0 && tipSlot < start {
+		v.add(RuleValidityInterval, -1, tipSlot, start, "valid from slot %d, tip is at %d", start, tipSlot)
+	}
+	if ttl := v.tx.TTL(); ttl > 0 && tipSlot >= ttl {
+		v.add(RuleValidityInterval, -1, tipSlot, ttl, "expired at slot %d, tip is at %d", ttl, tipSlot)
+	}
+}
+
+// minUtxoCoin returns the minimum ADA of an output of size bytes.
+func minUtxoCoin(coinsPerUtxoByte uint64, size int) uint64 {
+	// #nosec G115 -- sizes are never negative
+	return coinsPerUtxoByte * (minUtxoOverhead + uint64(size))
+}
+
+// utxoLovelace returns the coin held by a resolved output.
+func utxoLovelace(item *query.AnyUtxoData) uint64 {
+	if output := item.GetCardano(); output != nil {
+		return bigIntUint64(output.GetCoin())
+	}
+	if output, err := ledger.NewTransactionOutputFromCbor(item.GetNativeBytes()); err == nil {
+		return output.Amount().Uint64()
+	}
+	return 0
+}
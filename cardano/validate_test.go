@@ -0,0 +1,152 @@
+package cardano
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query/queryconnect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync/syncconnect"
+	"google.golang.org/protobuf/proto"
+)
+
+// validationTx returns a transaction spending 00…#0 into one enterprise
+// address output holding coinHex (CBOR), with a 1 ADA fee, TTL 100 and the
+// given witness set.
+func validationTx(coinHex, witnesses string) string {
+	return "84a400818258" + "20" + strings.Repeat("00", 32) + "00" +
+		"0181" + "82581d61" + strings.Repeat("11", 28) + coinHex +
+		"021a000f4240" + "031864" + witnesses + "f5f6"
+}
+
+var validationParams = &chaincardano.PParams{
+	MinFeeCoefficient:    mainnetFeeParams.GetMinFeeCoefficient(),
+	MinFeeConstant:       mainnetFeeParams.GetMinFeeConstant(),
+	Prices:               mainnetFeeParams.GetPrices(),
+	CoinsPerUtxoByte:     &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: 4310}},
+	MaxTxSize:            16384,
+	MaxValueSize:         5000,
+	CollateralPercentage: 150,
+	MaxCollateralInputs:  3,
+}
+
+func validationUtxo(t *testing.T) *query.AnyUtxoData {
+	t.Helper()
+	return &query.AnyUtxoData{
+		TxoRef: &query.TxoRef{Hash: mustDecodeHex(t, strings.Repeat("00", 32))},
+		ParsedState: &query.AnyUtxoData_Cardano{Cardano: &chaincardano.TxOutput{
+			Coin: &chaincardano.BigInt{BigInt: &chaincardano.BigInt_Int{Int: 5000000}},
+		}},
+	}
+}
+
+func TestValidateTransaction(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   string
+		vc   func(t *testing.T) ValidationContext
+		want []string
+	}{
+		{
+			name: "valid",
+			tx:   validationTx("1a001e8480", "a0"),
+			vc: func(t *testing.T) ValidationContext {
+				return ValidationContext{
+					Params:  validationParams,
+					Utxos:   []*query.AnyUtxoData{validationUtxo(t)},
+					TipSlot: 50,
+				}
+			},
+		},
+		{
+			name: "size, inputs, min ADA and expiry",
+			tx:   validationTx("01", "a0"),
+			vc: func(*testing.T) ValidationContext {
+				params := proto.Clone(validationParams).(*chaincardano.PParams)
+				params.MaxTxSize = 10
+				return ValidationContext{Params: params, TipSlot: 200}
+			},
+			want: []string{
+				"max-tx-size",
+				"unknown-input[0]",
+				"min-utxo[0]",
+				"validity-interval",
+			},
+		},
+		{
+			name: "missing collateral",
+			tx:   validationTx("1a001e8480", "a10581840000008200"+"00"),
+			vc: func(t *testing.T) ValidationContext {
+				return ValidationContext{
+					Params:  validationParams,
+					Utxos:   []*query.AnyUtxoData{validationUtxo(t)},
+					TipSlot: 50,
+				}
+			},
+			want: []string{"collateral-inputs", "collateral"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations, err := ValidateTransaction(test.tx, test.vc(t))
+			if err != nil {
+				t.Fatalf("ValidateTransaction returned error: %v", err)
+			}
+			var got []string
+			for _, v := range violations {
+				got = append(got, strings.SplitN(v.String(), ":", 2)[0])
+			}
+			if !slicesEqual(got, test.want) {
+				t.Fatalf("violations = %v, want %v", violations, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateTransactionNamesUnknownInputKind(t *testing.T) {
+	// validationTx with collateral input 22…#0 and one redeemer.
+	tx := "84a500818258" + "20" + strings.Repeat("00", 32) + "00" +
+		"0181" + "82581d61" + strings.Repeat("11", 28) + "1a001e8480" +
+		"021a000f4240" + "031864" +
+		"0d818258" + "20" + strings.Repeat("22", 32) + "00" +
+		"a10581840000008200" + "00" + "f5f6"
+	violations, err := ValidateTransaction(tx, ValidationContext{
+		Params:  validationParams,
+		Utxos:   []*query.AnyUtxoData{validationUtxo(t)},
+		TipSlot: 50,
+	})
+	if err != nil {
+		t.Fatalf("ValidateTransaction returned error: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Rule != RuleUnknownInput ||
+		violations[0].Input != InputCollateral || violations[0].Index != 0 {
+		t.Fatalf("violations = %v, want only the unknown collateral input", violations)
+	}
+}
+
+func TestClientValidateTransactionReadsLedgerState(t *testing.T) {
+	fakeQuery := &feeQueryHandler{
+		paramsQueryHandler: paramsQueryHandler{params: validationParams},
+		items:              []*query.AnyUtxoData{validationUtxo(t)},
+	}
+	client := newTestServerClient(
+		t,
+		func() (string, http.Handler) {
+			return queryconnect.NewQueryServiceHandler(fakeQuery)
+		},
+		func() (string, http.Handler) {
+			return syncconnect.NewSyncServiceHandler(&tipSyncHandler{slot: 150})
+		},
+	)
+
+	violations, err := client.ValidateTransaction(validationTx("1a001e8480", "a0"))
+	if err != nil {
+		t.Fatalf("ValidateTransaction returned error: %v", err)
+	}
+	if len(violations) != 1 || violations[0].Rule != RuleValidityInterval ||
+		violations[0].Actual != 150 || violations[0].Limit != 100 {
+		t.Fatalf("violations = %v, want only the expired validity interval", violations)
+	}
+}
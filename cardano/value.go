@@ -0,0 +1,131 @@
+package cardano
+
+import (
+	"encoding/hex"
+	"fmt"
+	"maps"
+
+	"github.com/blinklabs-io/gouroboros/ledger"
+	chaincardano "github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/query"
+)
+
+// Value is an amount of lovelace and native assets.
+type Value struct {
+	Lovelace uint64
+	// Assets maps asset units (see [AssetUnit]) to quantities. Entries with
+	// a zero quantity are dropped by the arithmetic methods.
+	Assets map[string]uint64
+}
+
+// AssetUnit returns the key of an asset in [Value.Assets]: the hex policy ID
+// followed by the hex asset name.
+func AssetUnit(policyID, assetName []byte) string {
+	return hex.EncodeToString(policyID) + hex.EncodeToString(assetName)
+}
+
+// splitAssetUnit returns the policy ID and asset name of an asset unit.
+func splitAssetUnit(unit string) ([]byte, []byte, error) {
+	raw, err := hex.DecodeString(unit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid asset unit %q: %w", unit, err)
+	}
+	if len(raw) < credentialHashSize || len(raw) > credentialHashSize+maxAssetNameSize {
+		return nil, nil, fmt.Errorf("invalid asset unit %q: got %d bytes", unit, len(raw))
+	}
+	return raw[:credentialHashSize], raw[credentialHashSize:], nil
+}
+
+// Add returns v + o.
+func (v Value) Add(o Value) Value {
+	sum := Value{Lovelace: v.Lovelace + o.Lovelace, Assets: maps.Clone(v.Assets)}
+	for unit, quantity := range o.Assets {
+		if quantity == 0 {
+			continue
+		}
+		if sum.Assets == nil {
+			sum.Assets = make(map[string]uint64)
+		}
+		sum.Assets[unit] += quantity
+	}
+	return sum
+}
+
+// Sub returns v - o, clipping each quantity at zero.
+func (v Value) Sub(o Value) Value {
+	diff := Value{Lovelace: v.Lovelace - min(v.Lovelace, o.Lovelace)}
+	for unit, quantity := range v.Assets {
+		if quantity > o.Assets[unit] {
+			if diff.Assets == nil {
+				diff.Assets = make(map[string]uint64)
+			}
+			diff.Assets[unit] = quantity - o.Assets[unit]
+		}
+	}
+	return diff
+}
+
+// Covers reports whether v holds at least o of lovelace and every asset.
+func (v Value) Covers(o Value) bool {
+	if v.Lovelace < o.Lovelace {
+		return false
+	}
+	for unit, quantity := range o.Assets {
+		if v.Assets[unit] < quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAssets reports whether v holds any native asset.
+func (v Value) HasAssets() bool {
+	for _, quantity := range v.Assets {
+		if quantity > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// UtxoValue returns the lovelace and assets held by a UTxO, read from its
+// parsed Cardano output or, failing that, its native CBOR.
+func UtxoValue(item *query.AnyUtxoData) Value {
+	return outputValue(utxoOutput(item))
+}
+
+// utxoOutput returns the parsed Cardano output of a UTxO, decoding its
+// native CBOR when the server sent none. Only the address, coin and assets
+// are filled in from native CBOR. It returns nil if neither is usable.
+func utxoOutput(item *query.AnyUtxoData) *chaincardano.TxOutput {
+	if output := item.GetCardano(); output != nil {
+		return output
+	}
+	output, err := ledger.NewTransactionOutputFromCbor(item.GetNativeBytes())
+	if err != nil {
+		return nil
+	}
+	address, _ := output.Address().Bytes()
+	return &chaincardano.TxOutput{
+		Address: address,
+		Coin:    newBigInt(output.Amount()),
+		Assets:  multiAssetToUtxorpc(output.Assets()),
+	}
+}
+
+func outputValue(output *chaincardano.TxOutput) Value {
+	value := Value{Lovelace: bigIntUint64(output.GetCoin())}
+	for _, multiasset := range output.GetAssets() {
+		for _, asset := range multiasset.GetAssets() {
+			quantity := bigIntUint64(asset.GetQuantity())
+			if quantity == 0 {
+				continue
+			}
+			if value.Assets == nil {
+				value.Assets = make(map[string]uint64)
+			}
+			value.Assets[AssetUnit(multiasset.GetPolicyId(), asset.GetName())] += quantity
+		}
+	}
+	return value
+}
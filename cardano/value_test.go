@@ -0,0 +1,24 @@
+package cardano
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValueArithmetic(t *testing.T) {
+	token := strings.Repeat("ab", credentialHashSize) + "01"
+	a := Value{Lovelace: 5, Assets: map[string]uint64{token: 3}}
+	b := Value{Lovelace: 2, Assets: map[string]uint64{token: 4}}
+
+	sum := a.Add(b)
+	if sum.Lovelace != 7 || sum.Assets[token] != 7 || a.Assets[token] != 3 {
+		t.Fatalf("Add = %+v, a = %+v", sum, a)
+	}
+	diff := a.Sub(b)
+	if diff.Lovelace != 3 || diff.HasAssets() {
+		t.Fatalf("Sub = %+v, want 3 lovelace and no assets", diff)
+	}
+	if a.Covers(b) || !sum.Covers(b) {
+		t.Fatal("Covers is wrong")
+	}
+}
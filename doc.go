@@ -45,6 +45,7 @@
 //	WithRequestTimeout(d)        — per-request timeout (default client only)
 //	WithHttpClient(c)            — replace the entire HTTP client
 //	WithConnectOptions(opts...)  — options/interceptors for all service clients
+//	WithBlockCache(cache)        — serve FetchBlock from a BlockCache
 //
 // Client lifecycle:
 //
@@ -80,9 +81,27 @@
 //
 //	WatchTx                                            — server-streaming
 //
+// Chain following:
+//
+//	NewFollower(client, store, opts...)                — checkpointed FollowTip driver
+//	NewMemoryCheckpointStore(), NewFileCheckpointStore(path)
+//	BlockRefOf(block)                                  — BlockRef for a parsed block
+//
+// Broadcast (submit through several endpoints at once):
+//
+//	Broadcast(clients, tx, opts...)                    — returns once a quorum accepts
+//	WithBroadcastQuorum(n), WithBroadcastTimeout(d)
+//	(*BroadcastResult).Outcomes() / Wait(ctx) / Accepted() / Conflicting()
+//
+// Block caching:
+//
+//	NewBlockCache(opts...)                             — LRU + on-disk immutable tier
+//	WithBlockCacheSize(n), WithBlockCacheDir(dir), WithRollbackWindow(k)
+//
 // Errors:
 //
 //	AsConnectError(err) — exposes a Connect code/message/details/metadata.
+//	ErrQuorumNotReached — a broadcast ended below its quorum.
 //	HandleError(err)    — deprecated panic-based compatibility helper.
 //
 // # Method-pair convention
@@ -52,6 +52,12 @@ func main() {
 		"addr_test1qptfy9zhaeuqfptcu79q6gm9l3r6cfp5gnlqc7m42qwln0lsvex239qmryg4yh3pda3rh3rnce4wd46gdyqlscrq7s4shekqrt",
 	)
 	fmt.Println()
+	// everything delegating to the same stake key, at any address
+	getUtxosByStakeCredential(
+		client,
+		"addr_test1qptfy9zhaeuqfptcu79q6gm9l3r6cfp5gnlqc7m42qwln0lsvex239qmryg4yh3pda3rh3rnce4wd46gdyqlscrq7s4shekqrt",
+	)
+	fmt.Println()
 	// https://preprod.cexplorer.io/asset/asset1tvkt35str8aeepuflxmnjzcdj87em8xrlx4ehz
 	// Use policy ID and asset name in hex format (https://cips.cardano.org/cip/CIP-68/)
 	// Hunt
@@ -70,6 +76,12 @@ func main() {
 		"0014df1044454449",
 	)
 	fmt.Println()
+	// Hunt again, by CIP-14 fingerprint within its policy
+	getUtxosByAsset(
+		client,
+		"63f9a5fc96d4f87026e97af4569975016b50eef092a46859b61898e5.asset1clcnzd552jmj5zret6ew4cuxhv0fkgjykwvjmw",
+	)
+	fmt.Println()
 	// No assets
 	searchUtxos(
 		client,
@@ -331,25 +343,39 @@ func searchUtxos(
 
 func getUtxosByAddress(
 	client *utxorpc.Client,
-	rawAddress string,
+	address string,
 ) {
-	// Use to support bech32/base58 addresses
-	addr, err := common.NewAddress(rawAddress)
+	// Use to support bech32/base58/hex addresses
+	addrBytes, err := utxorpc.ParseAddress(address)
 	if err != nil {
-		log.Fatalf("failed to create address: %v", err)
+		log.Fatalf("failed to parse address: %v", err)
 	}
-	addrCbor, err := addr.Bytes()
+
+	fmt.Printf("searching utxos: address: %s\n", address)
+	resp, err := client.GetUtxosByAddress(addrBytes)
 	if err != nil {
-		log.Fatalf("failed to convert address: %v", err)
+		reportError(err)
+		return
 	}
+	printSearchResponse(resp)
+}
 
-	fmt.Printf("searching utxos: address: %s\n", rawAddress)
-	resp, err := client.GetUtxosByAddress(addrCbor)
+func getUtxosByStakeCredential(
+	client *utxorpc.Client,
+	credential string,
+) {
+	// The delegation part of an address, a stake address or a bech32/hex
+	// stake key hash
+	fmt.Printf("searching utxos: stake credential: %s\n", credential)
+	resp, err := client.GetUtxosByStakeCredential(credential)
 	if err != nil {
 		reportError(err)
 		return
 	}
+	printSearchResponse(resp)
+}
 
+func printSearchResponse(resp *connect.Response[query.SearchUtxosResponse]) {
 	// Uncomment to print the full response for debugging
 	// fmt.Printf("Response: %+v\n", resp)
 
@@ -371,23 +397,39 @@ func getUtxosByAddress(
 			fmt.Printf("    Address: %x\n", cardano.GetAddress())
 			fmt.Printf("    Coin: %v\n", cardano.GetCoin())
 			fmt.Println("    Assets:")
-			for _, multiasset := range cardano.GetAssets() {
-				fmt.Printf("      Policy ID: %x\n", multiasset.GetPolicyId())
-				for _, asset := range multiasset.GetAssets() {
-					fmt.Printf(
-						"        Asset Name: %s\n",
-						string(asset.GetName()),
-					)
-					fmt.Printf(
-						"        Quantity: %v\n",
-						asset.GetQuantity(),
-					)
+			for _, asset := range utxorpc.UtxoAssets(item) {
+				fmt.Printf("      Policy ID: %x\n", asset.PolicyID)
+				fmt.Printf("        Asset Name: %s\n", asset.DisplayName())
+				if label, ok := asset.Label(); ok {
+					fmt.Printf("        CIP-67 Label: %d\n", label)
 				}
+				fmt.Printf("        Fingerprint: %s\n", asset.Fingerprint())
+				fmt.Printf("        Quantity: %d\n", asset.Quantity)
 			}
 		}
 	}
 }
 
+func getUtxosByAsset(
+	client *utxorpc.Client,
+	asset string,
+) {
+	// asset is a policy ID, an asset unit or "policy.name" with a hex name or
+	// fingerprint; use utxorpc.TextAssetID for UTF-8 names
+	assetID, err := utxorpc.ParseAssetID(asset)
+	if err != nil {
+		log.Fatalf("failed to parse asset: %v", err)
+	}
+
+	fmt.Printf("searching utxos: asset: %s\n", asset)
+	resp, err := client.GetUtxosByAssetID(assetID)
+	if err != nil {
+		reportError(err)
+		return
+	}
+	printSearchResponse(resp)
+}
+
 func reportError(err error) {
 	var transportErr net.Error
 	if errors.As(err, &transportErr) {
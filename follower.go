@@ -0,0 +1,422 @@
+package sdk
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	gosync "sync"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultCheckpointDepth = 32
+
+// ErrRollbackTooDeep is returned by [Follower.Run] when the server rolls
+// back past every point the Follower knows to be stable. The checkpoint is
+// left as it was; the points it holds may no longer be on chain, so the
+// caller must choose where to resume, for example with a larger
+// [WithCheckpointDepth] or explicit [WithStartPoints].
+var ErrRollbackTooDeep = errors.New("rollback is deeper than the checkpoint")
+
+// CheckpointStore persists the chain points a [Follower] has finished
+// processing. Points are ordered newest first; the first entry is the last
+// block whose handler returned successfully.
+//
+// Implementations must be safe for use by a single Follower; they are not
+// required to support concurrent writers.
+type CheckpointStore interface {
+	// Load returns the stored points, newest first. An empty result means
+	// no checkpoint has been saved yet.
+	Load(ctx context.Context) ([]*sync.BlockRef, error)
+	// Save replaces the stored points with the given list.
+	Save(ctx context.Context, points []*sync.BlockRef) error
+}
+
+// MemoryCheckpointStore is a [CheckpointStore] that keeps points in memory.
+// It is useful for tests and for processes that do not need to survive a
+// restart.
+type MemoryCheckpointStore struct {
+	mu     gosync.Mutex
+	points []*sync.BlockRef
+}
+
+// NewMemoryCheckpointStore returns an empty [MemoryCheckpointStore].
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+// Load returns a copy of the stored points.
+func (s *MemoryCheckpointStore) Load(
+	context.Context,
+) ([]*sync.BlockRef, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return cloneBlockRefs(s.points), nil
+}
+
+// Save replaces the stored points with a copy of points.
+func (s *MemoryCheckpointStore) Save(
+	_ context.Context,
+	points []*sync.BlockRef,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.points = cloneBlockRefs(points)
+	return nil
+}
+
+// FileCheckpointStore is a [CheckpointStore] that keeps points in a JSON
+// file. Saves write a temporary file next to the target and rename it into
+// place, so a crash mid-write leaves the previous checkpoint intact.
+type FileCheckpointStore struct {
+	path string
+}
+
+// NewFileCheckpointStore returns a [FileCheckpointStore] backed by path. The
+// file is created on the first Save; a missing file loads as no checkpoint.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+type checkpointPoint struct {
+	Slot      uint64 `json:"slot"`
+	Hash      string `json:"hash"`
+	Height    uint64 `json:"height,omitempty"`
+	Timestamp uint64 `json:"timestamp,omitempty"`
+}
+
+// Load reads the stored points from disk.
+func (s *FileCheckpointStore) Load(
+	context.Context,
+) ([]*sync.BlockRef, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+
+	var stored []checkpointPoint
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint file: %w", err)
+	}
+	points := make([]*sync.BlockRef, 0, len(stored))
+	for _, point := range stored {
+		hash, err := hex.DecodeString(point.Hash)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to decode checkpoint hash %q: %w",
+				point.Hash,
+				err,
+			)
+		}
+		points = append(points, &sync.BlockRef{
+			Slot:      point.Slot,
+			Hash:      hash,
+			Height:    point.Height,
+			Timestamp: point.Timestamp,
+		})
+	}
+	return points, nil
+}
+
+// Save atomically replaces the checkpoint file with points.
+func (s *FileCheckpointStore) Save(
+	_ context.Context,
+	points []*sync.BlockRef,
+) error {
+	stored := make([]checkpointPoint, 0, len(points))
+	for _, point := range points {
+		stored = append(stored, checkpointPoint{
+			Slot:      point.GetSlot(),
+			Hash:      hex.EncodeToString(point.GetHash()),
+			Height:    point.GetHeight(),
+			Timestamp: point.GetTimestamp(),
+		})
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync checkpoint file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close checkpoint file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint file: %w", err)
+	}
+	return nil
+}
+
+// BlockHandler processes a block delivered by a [Follower].
+type BlockHandler func(ctx context.Context, block *sync.AnyChainBlock) error
+
+// ResetHandler is called when the server tells a [Follower] to restart from
+// the given point.
+type ResetHandler func(ctx context.Context, point *sync.BlockRef) error
+
+// FollowerOption configures a [Follower] during [NewFollower].
+type FollowerOption func(*Follower)
+
+// WithApplyHandler sets the handler called for every applied block.
+func WithApplyHandler(handler BlockHandler) FollowerOption {
+	return func(f *Follower) {
+		f.onApply = handler
+	}
+}
+
+// WithUndoHandler sets the handler called for every rolled-back block.
+func WithUndoHandler(handler BlockHandler) FollowerOption {
+	return func(f *Follower) {
+		f.onUndo = handler
+	}
+}
+
+// WithResetHandler sets the handler called when the server resets the
+// stream to a given point.
+func WithResetHandler(handler ResetHandler) FollowerOption {
+	return func(f *Follower) {
+		f.onReset = handler
+	}
+}
+
+// WithCheckpointDepth sets how many recent points are persisted and offered
+// as intersect candidates on restart. Values below 1 are ignored.
+func WithCheckpointDepth(depth int) FollowerOption {
+	return func(f *Follower) {
+		if depth > 0 {
+			f.depth = depth
+		}
+	}
+}
+
+// WithStartPoints sets the intersect used when the store holds no
+// checkpoint. Without it, a fresh Follower starts from the server's
+// default (usually the current tip).
+func WithStartPoints(points ...*sync.BlockRef) FollowerOption {
+	return func(f *Follower) {
+		f.start = cloneBlockRefs(points)
+	}
+}
+
+// Follower drives [UtxorpcClient.FollowTipWithContext], dispatches each
+// Apply / Undo / Reset event to user handlers, and persists a checkpoint
+// through a [CheckpointStore] after every handled event.
+//
+// Processing is at-least-once: the checkpoint only advances after a handler
+// returns nil, so a block whose handler failed (or that was in flight when
+// the process stopped) is delivered again after a restart.
+//
+// Construct via [NewFollower]; the zero value is not usable.
+type Follower struct {
+	client  *UtxorpcClient
+	store   CheckpointStore
+	onApply BlockHandler
+	onUndo  BlockHandler
+	onReset ResetHandler
+	depth   int
+	start   []*sync.BlockRef
+
+	// mu guards points, which Run updates while Checkpoint may read it
+	// from another goroutine.
+	mu     gosync.Mutex
+	points []*sync.BlockRef
+	// intersect holds the points the current stream was opened from,
+	// kept to fall back on when a rollback undoes every newer point.
+	intersect []*sync.BlockRef
+}
+
+// NewFollower constructs a [Follower] that reads from client and persists
+// checkpoints to store.
+func NewFollower(
+	client *UtxorpcClient,
+	store CheckpointStore,
+	options ...FollowerOption,
+) *Follower {
+	f := &Follower{
+		client: client,
+		store:  store,
+		depth:  defaultCheckpointDepth,
+	}
+	for _, option := range options {
+		option(f)
+	}
+	return f
+}
+
+// Checkpoint returns the points the Follower has finished processing,
+// newest first.
+func (f *Follower) Checkpoint() []*sync.BlockRef {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return cloneBlockRefs(f.points)
+}
+
+func (f *Follower) setPoints(points []*sync.BlockRef) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.points = points
+}
+
+// Run loads the stored checkpoint, opens a FollowTip stream intersecting at
+// the stored points (or the configured start points), and processes events
+// until ctx is cancelled, the stream ends, a handler or the store returns
+// an error, or the server rolls back past the checkpoint
+// ([ErrRollbackTooDeep]). A cancelled context is reported as ctx.Err().
+func (f *Follower) Run(ctx context.Context) error {
+	points, err := f.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	f.setPoints(points)
+
+	intersect := points
+	if len(intersect) == 0 {
+		intersect = f.start
+	}
+	f.intersect = cloneBlockRefs(intersect)
+	req := connect.NewRequest(&sync.FollowTipRequest{
+		Intersect: cloneBlockRefs(intersect),
+	})
+	stream, err := f.client.FollowTipWithContext(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	for stream.Receive() {
+		if err := f.handle(ctx, stream.Msg()); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return stream.Err()
+}
+
+func (f *Follower) handle(
+	ctx context.Context,
+	resp *sync.FollowTipResponse,
+) error {
+	// Only Run writes points, so it reads them here without the lock. Each
+	// block is checked before its handler runs, so a handler never sees a
+	// block the checkpoint then refuses.
+	var points []*sync.BlockRef
+	switch action := resp.GetAction().(type) {
+	case *sync.FollowTipResponse_Apply:
+		ref := BlockRefOf(action.Apply)
+		if ref == nil {
+			return errors.New("applied block has no header")
+		}
+		if f.onApply != nil {
+			if err := f.onApply(ctx, action.Apply); err != nil {
+				return fmt.Errorf("apply handler failed: %w", err)
+			}
+		}
+		points = append([]*sync.BlockRef{ref}, f.points...)
+		if len(points) > f.depth {
+			points = points[:f.depth]
+		}
+	case *sync.FollowTipResponse_Undo:
+		ref := BlockRefOf(action.Undo)
+		if ref == nil {
+			return errors.New("undone block has no header")
+		}
+		points = pointsBefore(f.points, ref.GetSlot())
+		if len(points) == 0 {
+			points = pointsBefore(f.intersect, ref.GetSlot())
+		}
+		if len(points) == 0 {
+			return fmt.Errorf(
+				"%w: undo of slot %d",
+				ErrRollbackTooDeep,
+				ref.GetSlot(),
+			)
+		}
+		if f.onUndo != nil {
+			if err := f.onUndo(ctx, action.Undo); err != nil {
+				return fmt.Errorf("undo handler failed: %w", err)
+			}
+		}
+	case *sync.FollowTipResponse_Reset_:
+		if f.onReset != nil {
+			if err := f.onReset(ctx, action.Reset_); err != nil {
+				return fmt.Errorf("reset handler failed: %w", err)
+			}
+		}
+		points = pointsBefore(f.points, action.Reset_.GetSlot()+1)
+		if len(points) == 0 ||
+			points[0].GetSlot() != action.Reset_.GetSlot() {
+			points = append(
+				[]*sync.BlockRef{proto.Clone(action.Reset_).(*sync.BlockRef)},
+				points...,
+			)
+		}
+	default:
+		return nil
+	}
+	f.setPoints(points)
+
+	if err := f.store.Save(ctx, points); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// BlockRefOf returns the [sync.BlockRef] (slot, hash, height, timestamp)
+// identifying block, or nil if the block carries no parsed header.
+func BlockRefOf(block *sync.AnyChainBlock) *sync.BlockRef {
+	cardanoBlock := block.GetCardano()
+	header := cardanoBlock.GetHeader()
+	if header == nil {
+		return nil
+	}
+	return &sync.BlockRef{
+		Slot:      header.GetSlot(),
+		Hash:      header.GetHash(),
+		Height:    header.GetHeight(),
+		Timestamp: cardanoBlock.GetTimestamp(),
+	}
+}
+
+// pointsBefore drops every leading point at or after slot.
+func pointsBefore(points []*sync.BlockRef, slot uint64) []*sync.BlockRef {
+	for i, point := range points {
+		if point.GetSlot() < slot {
+			return points[i:]
+		}
+	}
+	return nil
+}
+
+func cloneBlockRefs(points []*sync.BlockRef) []*sync.BlockRef {
+	if points == nil {
+		return nil
+	}
+	cloned := make([]*sync.BlockRef, 0, len(points))
+	for _, point := range points {
+		cloned = append(cloned, proto.Clone(point).(*sync.BlockRef))
+	}
+	return cloned
+}
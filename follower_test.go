@@ -0,0 +1,311 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/cardano"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync"
+	"github.com/utxorpc/go-codegen/utxorpc/v1beta/sync/syncconnect"
+)
+
+func TestFollowerCheckpointsAppliedBlocksAndHandlesUndo(t *testing.T) {
+	fakeSync := &scriptedSyncHandler{
+		responses: []*sync.FollowTipResponse{
+			applyResponse(10, 0x0a),
+			applyResponse(11, 0x0b),
+			undoResponse(11, 0x0b),
+			applyResponse(12, 0x0c),
+		},
+	}
+	path, handler := syncconnect.NewSyncServiceHandler(fakeSync)
+	client := newTestServerClient(t, path, handler)
+	store := NewMemoryCheckpointStore()
+
+	var applied, undone []uint64
+	follower := NewFollower(
+		client,
+		store,
+		WithStartPoints(&sync.BlockRef{Slot: 9, Hash: []byte{0x09}}),
+		WithApplyHandler(func(_ context.Context, block *sync.AnyChainBlock) error {
+			applied = append(applied, BlockRefOf(block).GetSlot())
+			return nil
+		}),
+		WithUndoHandler(func(_ context.Context, block *sync.AnyChainBlock) error {
+			undone = append(undone, BlockRefOf(block).GetSlot())
+			return nil
+		}),
+	)
+
+	if err := follower.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if got := fakeSync.intersects[0]; len(got) != 1 || got[0].GetSlot() != 9 {
+		t.Fatalf("first intersect = %v, want start point at slot 9", got)
+	}
+	if !slicesEqualUint64(applied, []uint64{10, 11, 12}) {
+		t.Fatalf("applied slots = %v, want [10 11 12]", applied)
+	}
+	if !slicesEqualUint64(undone, []uint64{11}) {
+		t.Fatalf("undone slots = %v, want [11]", undone)
+	}
+
+	points, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got := slotsOf(points); !slicesEqualUint64(got, []uint64{12, 10}) {
+		t.Fatalf("checkpoint slots = %v, want [12 10]", got)
+	}
+}
+
+func TestFollowerHandlerErrorDoesNotAdvanceCheckpoint(t *testing.T) {
+	fakeSync := &scriptedSyncHandler{
+		responses: []*sync.FollowTipResponse{
+			applyResponse(20, 0x14),
+			applyResponse(21, 0x15),
+		},
+	}
+	path, handler := syncconnect.NewSyncServiceHandler(fakeSync)
+	client := newTestServerClient(t, path, handler)
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	handlerErr := errors.New("database unavailable")
+
+	follower := NewFollower(
+		client,
+		store,
+		WithApplyHandler(func(_ context.Context, block *sync.AnyChainBlock) error {
+			if BlockRefOf(block).GetSlot() == 21 {
+				return handlerErr
+			}
+			return nil
+		}),
+	)
+	if err := follower.Run(context.Background()); !errors.Is(err, handlerErr) {
+		t.Fatalf("Run error = %v, want %v", err, handlerErr)
+	}
+
+	points, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got := slotsOf(points); !slicesEqualUint64(got, []uint64{20}) {
+		t.Fatalf("checkpoint slots = %v, want [20]", got)
+	}
+	if !bytes.Equal(points[0].GetHash(), []byte{0x14}) {
+		t.Fatalf("checkpoint hash = %x, want 14", points[0].GetHash())
+	}
+
+	// A restarted follower intersects at the stored point.
+	fakeSync.responses = nil
+	restarted := NewFollower(client, store)
+	if err := restarted.Run(context.Background()); err != nil {
+		t.Fatalf("Run after restart returned error: %v", err)
+	}
+	if got := slotsOf(fakeSync.intersects[1]); !slicesEqualUint64(got, []uint64{20}) {
+		t.Fatalf("restart intersect slots = %v, want [20]", got)
+	}
+}
+
+func TestFollowerDeepRollbackKeepsStablePoint(t *testing.T) {
+	fakeSync := &scriptedSyncHandler{
+		responses: []*sync.FollowTipResponse{
+			applyResponse(10, 0x0a),
+			undoResponse(10, 0x0a),
+		},
+	}
+	path, handler := syncconnect.NewSyncServiceHandler(fakeSync)
+	client := newTestServerClient(t, path, handler)
+	store := NewMemoryCheckpointStore()
+
+	// Undoing every applied block falls back to the start point.
+	follower := NewFollower(
+		client,
+		store,
+		WithStartPoints(&sync.BlockRef{Slot: 9, Hash: []byte{0x09}}),
+	)
+	if err := follower.Run(context.Background()); err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+	points, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got := slotsOf(points); !slicesEqualUint64(got, []uint64{9}) {
+		t.Fatalf("checkpoint slots = %v, want [9]", got)
+	}
+
+	// Rolling back past the stored point is an error and keeps the
+	// checkpoint as it was.
+	fakeSync.responses = []*sync.FollowTipResponse{undoResponse(8, 0x08)}
+	undone := 0
+	restarted := NewFollower(
+		client,
+		store,
+		WithUndoHandler(func(context.Context, *sync.AnyChainBlock) error {
+			undone++
+			return nil
+		}),
+	)
+	if err := restarted.Run(context.Background()); !errors.Is(err, ErrRollbackTooDeep) {
+		t.Fatalf("Run error = %v, want ErrRollbackTooDeep", err)
+	}
+	if undone != 0 {
+		t.Fatalf("undo handler ran %d times for a rejected rollback", undone)
+	}
+	points, err = store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if got := slotsOf(points); !slicesEqualUint64(got, []uint64{9}) {
+		t.Fatalf("checkpoint slots = %v, want [9]", got)
+	}
+}
+
+func TestFollowerRejectsHeaderlessBlockBeforeHandler(t *testing.T) {
+	fakeSync := &scriptedSyncHandler{
+		responses: []*sync.FollowTipResponse{{
+			Action: &sync.FollowTipResponse_Apply{Apply: &sync.AnyChainBlock{}},
+		}},
+	}
+	path, handler := syncconnect.NewSyncServiceHandler(fakeSync)
+	client := newTestServerClient(t, path, handler)
+
+	applied := 0
+	follower := NewFollower(
+		client,
+		NewMemoryCheckpointStore(),
+		WithApplyHandler(func(context.Context, *sync.AnyChainBlock) error {
+			applied++
+			return nil
+		}),
+	)
+	if err := follower.Run(context.Background()); err == nil {
+		t.Fatal("Run accepted a block without a header")
+	}
+	if applied != 0 {
+		t.Fatalf("apply handler ran %d times for a headerless block", applied)
+	}
+}
+
+func TestFollowerCheckpointIsSafeDuringRun(t *testing.T) {
+	var responses []*sync.FollowTipResponse
+	for slot := range uint64(50) {
+		responses = append(responses, applyResponse(slot+1, byte(slot+1)))
+	}
+	fakeSync := &scriptedSyncHandler{responses: responses}
+	path, handler := syncconnect.NewSyncServiceHandler(fakeSync)
+	client := newTestServerClient(t, path, handler)
+	follower := NewFollower(client, NewMemoryCheckpointStore())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- follower.Run(context.Background())
+	}()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Run returned error: %v", err)
+			}
+			if got := follower.Checkpoint(); len(got) == 0 || got[0].GetSlot() != 50 {
+				t.Fatalf("checkpoint = %v, want slot 50 first", slotsOf(got))
+			}
+			return
+		default:
+			follower.Checkpoint()
+		}
+	}
+}
+
+type scriptedSyncHandler struct {
+	syncconnect.UnimplementedSyncServiceHandler
+	responses  []*sync.FollowTipResponse
+	intersects [][]*sync.BlockRef
+}
+
+func (s *scriptedSyncHandler) FollowTip(
+	_ context.Context,
+	req *connect.Request[sync.FollowTipRequest],
+	stream *connect.ServerStream[sync.FollowTipResponse],
+) error {
+	s.intersects = append(s.intersects, req.Msg.GetIntersect())
+	for _, resp := range s.responses {
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newTestServerClient starts an h2c test server for a generated Connect
+// handler and returns a client pointed at it.
+func newTestServerClient(
+	t *testing.T,
+	path string,
+	handler http.Handler,
+) *UtxorpcClient {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	t.Cleanup(server.Close)
+	return NewClient(WithBaseUrl(server.URL))
+}
+
+func testBlock(slot uint64, hash byte) *sync.AnyChainBlock {
+	return &sync.AnyChainBlock{
+		Chain: &sync.AnyChainBlock_Cardano{
+			Cardano: &cardano.Block{
+				Header: &cardano.BlockHeader{
+					Slot:   slot,
+					Hash:   []byte{hash},
+					Height: slot,
+				},
+			},
+		},
+	}
+}
+
+func applyResponse(slot uint64, hash byte) *sync.FollowTipResponse {
+	return &sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Apply{Apply: testBlock(slot, hash)},
+	}
+}
+
+func undoResponse(slot uint64, hash byte) *sync.FollowTipResponse {
+	return &sync.FollowTipResponse{
+		Action: &sync.FollowTipResponse_Undo{Undo: testBlock(slot, hash)},
+	}
+}
+
+func slotsOf(points []*sync.BlockRef) []uint64 {
+	slots := make([]uint64, 0, len(points))
+	for _, point := range points {
+		slots = append(slots, point.GetSlot())
+	}
+	return slots
+}
+
+func slicesEqualUint64(left, right []uint64) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+	return true
+}
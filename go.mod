@@ -7,7 +7,9 @@ go 1.25.7
 
 require (
 	connectrpc.com/connect v1.20.0
+	filippo.io/edwards25519 v1.2.0
 	github.com/blinklabs-io/gouroboros v0.189.4
+	github.com/btcsuite/btcd/btcutil v1.2.0
 	github.com/utxorpc/go-codegen v0.19.2
 	golang.org/x/net v0.57.0
 	google.golang.org/protobuf v1.36.11
@@ -18,7 +20,6 @@ require (
 	github.com/bits-and-blooms/bitset v1.24.4 // indirect
 	github.com/blinklabs-io/plutigo v0.1.17 // indirect
 	github.com/btcsuite/btcd/btcec/v2 v2.5.0 // indirect
-	github.com/btcsuite/btcd/btcutil v1.2.0 // indirect
 	github.com/btcsuite/btcd/chaincfg/chainhash v1.2.0 // indirect
 	github.com/btcsuite/btcd/chainhash/v2 v2.0.0 // indirect
 	github.com/consensys/gnark-crypto v0.20.1 // indirect